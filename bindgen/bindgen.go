@@ -0,0 +1,468 @@
+// Package bindgen generates Go bindings for Move modules from their normalized definitions, and holds the types used by
+// the generated code.
+package bindgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+const (
+	bindgenImport      = "github.com/W3Tools/gosui/bindgen"
+	transactionsImport = "github.com/W3Tools/gosui/transactions"
+	suiTypesImport     = "github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	bcsImport          = "github.com/fardream/go-bcs/bcs"
+)
+
+// Options defines the options of the generated bindings.
+type Options struct {
+	// PackageID is the ID of the package called by the generated functions, the address of the modules is used when it
+	// is empty. It differs from the address of the modules for upgraded packages.
+	PackageID string
+	// ImportPath is the Go import path of the output directory, it is needed when a module uses the structs of another module.
+	ImportPath string
+}
+
+// Generate generates a Go package for each Move module, the returned map key is the path of the Go file relative to the
+// output directory and the value is its formatted source.
+func Generate(modules types.SuiMoveNormalizedModules, options Options) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(modules))
+	for _, name := range sortedKeys(modules) {
+		module := modules[name]
+		f := &file{modules: modules, module: &module, options: options, imports: make(map[string]bool), names: make(map[string]bool)}
+		source, err := f.generate()
+		if err != nil {
+			return nil, fmt.Errorf("can not generate module [%s]: %v", name, err)
+		}
+		files[path.Join(packageName(name), packageName(name)+".go")] = source
+	}
+	return files, nil
+}
+
+// file generates the Go file of a Move module.
+type file struct {
+	modules types.SuiMoveNormalizedModules
+	module  *types.SuiMoveNormalizedModule
+	options Options
+	imports map[string]bool // import paths used by the file
+	names   map[string]bool // declared package level names
+	body    bytes.Buffer
+}
+
+func (f *file) generate() ([]byte, error) {
+	packageID := f.options.PackageID
+	if packageID == "" {
+		packageID = f.module.Address
+	}
+	f.declare("PackageID", "ModuleName")
+
+	structs := sortedKeys(f.module.Structs)
+	structNames := make(map[string]string, len(structs))
+	for _, name := range structs {
+		structNames[name] = f.declare(exportedName(name))
+	}
+	typeNames := make(map[string]string, len(structs))
+	for _, name := range structs {
+		typeNames[name] = f.declare(structNames[name] + "Type")
+	}
+
+	if len(structs) > 0 {
+		f.printf("// Type names of the structs without type arguments, such as the types of events.\nconst (\n")
+		for _, name := range structs {
+			f.printf("%s = %q\n", typeNames[name], fmt.Sprintf("%s::%s::%s", f.module.Address, f.module.Name, name))
+		}
+		f.printf(")\n\n")
+	}
+	for _, name := range structs {
+		if err := f.generateStruct(name, structNames[name]); err != nil {
+			return nil, fmt.Errorf("invalid struct [%s]: %v", name, err)
+		}
+	}
+	for _, name := range sortedKeys(f.module.ExposedFunctions) {
+		function := f.module.ExposedFunctions[name]
+		if function.Visibility != types.Public && !function.IsEntry {
+			continue
+		}
+		f.generateFunction(name, &function)
+	}
+
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by gosui-bindgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&source, "// Package %s contains the bindings of the Move module `%s::%s`.\n", packageName(f.module.Name), utils.NormalizeShortSuiAddress(f.module.Address), f.module.Name)
+	fmt.Fprintf(&source, "package %s\n\n", packageName(f.module.Name))
+	if len(f.imports) > 0 {
+		fmt.Fprintf(&source, "import (\n")
+		for _, importPath := range sortedKeys(f.imports) {
+			fmt.Fprintf(&source, "%q\n", importPath)
+		}
+		fmt.Fprintf(&source, ")\n\n")
+	}
+	fmt.Fprintf(&source, "const (\n// PackageID is the ID of the package called by the functions.\nPackageID = %q\n", packageID)
+	fmt.Fprintf(&source, "// ModuleName is the name of the module.\nModuleName = %q\n)\n\n", f.module.Name)
+	source.Write(f.body.Bytes())
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("can not format source: %v", err)
+	}
+	return formatted, nil
+}
+
+func (f *file) printf(format string, args ...any) {
+	fmt.Fprintf(&f.body, format, args...)
+}
+
+// declare reserves a package level name, a number is appended when the name is already declared.
+func (f *file) declare(names ...string) string {
+	var declared string
+	for _, name := range names {
+		declared = name
+		for i := 2; f.names[declared]; i++ {
+			declared = fmt.Sprintf("%s%d", name, i)
+		}
+		f.names[declared] = true
+	}
+	return declared
+}
+
+func (f *file) use(importPath string) string {
+	f.imports[importPath] = true
+	return path.Base(importPath)
+}
+
+func (f *file) generateStruct(name, goName string) error {
+	definition := f.module.Structs[name]
+
+	var params []string
+	typeParams := make(map[uint64]string)
+	for idx, param := range definition.TypeParameters {
+		if !param.IsPhantom {
+			typeParams[uint64(idx)] = fmt.Sprintf("T%d", idx)
+			params = append(params, typeParams[uint64(idx)])
+		}
+	}
+	declaration, receiver := goName, goName
+	if len(params) > 0 {
+		declaration = fmt.Sprintf("%s[%s any]", goName, strings.Join(params, ", "))
+		receiver = fmt.Sprintf("%s[%s]", goName, strings.Join(params, ", "))
+	}
+
+	f.printf("// %s is the Move struct `%s`, fields are in BCS order.\n", goName, f.formatType(f.structType(name, len(definition.TypeParameters))))
+	f.printf("type %s struct {\n", declaration)
+	fieldNames := make(map[string]bool, len(definition.Fields))
+	for _, field := range definition.Fields {
+		goType, err := f.goType(field.Type.SuiMoveNormalizedType, typeParams)
+		if err != nil {
+			return fmt.Errorf("invalid field [%s]: %v", field.Name, err)
+		}
+		fieldName := exportedName(field.Name)
+		for i := 2; fieldNames[fieldName]; i++ {
+			fieldName = fmt.Sprintf("%s%d", exportedName(field.Name), i)
+		}
+		fieldNames[fieldName] = true
+		f.printf("%s %s `json:%q`\n", fieldName, goType, field.Name)
+	}
+	f.printf("}\n\n")
+
+	f.printf("// UnmarshalJSON decodes the struct from the parsed JSON of an event or object content.\n")
+	f.printf("func (v *%s) UnmarshalJSON(data []byte) error {\nreturn %s.UnmarshalFields(data, v)\n}\n\n", receiver, f.use(bindgenImport))
+	return nil
+}
+
+func (f *file) generateFunction(name string, function *types.SuiMoveNormalizedFunction) {
+	parameters := function.Parameters
+	if n := len(parameters); n > 0 && isTxContext(parameters[n-1].SuiMoveNormalizedType) {
+		parameters = parameters[:n-1]
+	}
+
+	transactions := f.use(transactionsImport)
+	signature := []string{"tx *" + transactions + ".Transaction"}
+	if len(function.TypeParameters) > 0 {
+		signature = append(signature, fmt.Sprintf("typeArguments [%d]string", len(function.TypeParameters)))
+	}
+	moveTypes := make([]string, len(parameters))
+	arguments := make([]string, len(parameters))
+	for idx, parameter := range parameters {
+		argument := fmt.Sprintf("arg%d", idx)
+		moveTypes[idx] = "`" + f.formatType(parameter.SuiMoveNormalizedType) + "`"
+		if goType, ok := f.pureType(parameter.SuiMoveNormalizedType); ok {
+			signature = append(signature, argument+" "+goType)
+			arguments[idx] = fmt.Sprintf("%s.Pure(%s)", transactions, argument)
+		} else {
+			signature = append(signature, argument+" "+transactions+".Arg")
+			arguments[idx] = argument
+		}
+	}
+	typeArguments := "nil"
+	if len(function.TypeParameters) > 0 {
+		typeArguments = "typeArguments[:]"
+	}
+
+	goName := f.declare(exportedName(name))
+	f.printf("// %s adds a call to `%s::%s`", goName, f.module.Name, name)
+	if len(moveTypes) > 0 {
+		f.printf(" with arguments of type %s", strings.Join(moveTypes, ", "))
+	}
+	f.printf(".\nfunc %s(%s) (%s.Result, error) {\n", goName, strings.Join(signature, ", "), transactions)
+	f.printf("return tx.AddMoveCall(PackageID+%q, []%s.Arg{%s}, %s)\n}\n\n", "::"+f.module.Name+"::"+name, transactions, strings.Join(arguments, ", "), typeArguments)
+}
+
+// structType returns the normalized type of a struct of the module with its type parameters.
+func (f *file) structType(name string, typeParameters int) types.SuiMoveNormalizedType {
+	structType := types.SuiMoveNormalizedTypeStruct{Struct: types.SuiMoveNormalizedTypeStructStruct{Address: f.module.Address, Module: f.module.Name, Name: name}}
+	for idx := 0; idx < typeParameters; idx++ {
+		structType.Struct.TypeArguments = append(structType.Struct.TypeArguments, types.SuiMoveNormalizedTypeWrapper{SuiMoveNormalizedType: types.SuiMoveNormalizedTypeTypeParameter{TypeParameter: uint64(idx)}})
+	}
+	return structType
+}
+
+// goType returns the Go type of a field, typeParams maps the indexes of non-phantom type parameters to their names.
+func (f *file) goType(normalizedType types.SuiMoveNormalizedType, typeParams map[uint64]string) (string, error) {
+	switch t := normalizedType.(type) {
+	case types.SuiMoveNormalizedTypeString:
+		switch t {
+		case "Bool":
+			return "bool", nil
+		case "U8":
+			return "uint8", nil
+		case "U16":
+			return "uint16", nil
+		case "U32":
+			return "uint32", nil
+		case "U64":
+			return f.use(bindgenImport) + ".U64", nil
+		case "U128":
+			return f.use(bcsImport) + ".Uint128", nil
+		case "U256":
+			return f.use(bindgenImport) + ".U256", nil
+		case "Address":
+			return f.use(suiTypesImport) + ".SuiAddress", nil
+		default:
+			return "", fmt.Errorf("unsupported type [%s]", t)
+		}
+	case types.SuiMoveNormalizedTypeVector:
+		element, err := f.goType(t.Vector.SuiMoveNormalizedType, typeParams)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + element, nil
+	case types.SuiMoveNormalizedTypeTypeParameter:
+		name, ok := typeParams[t.TypeParameter]
+		if !ok {
+			return "", fmt.Errorf("phantom type parameter %d is used by a field", t.TypeParameter)
+		}
+		return name, nil
+	case types.SuiMoveNormalizedTypeStruct:
+		return f.structGoType(t.Struct, typeParams)
+	default:
+		return "", fmt.Errorf("unsupported type %T", normalizedType)
+	}
+}
+
+// frameworkStruct defines the Go type of a struct of the Move standard library or the Sui framework.
+type frameworkStruct struct {
+	name           string // name of the type in the bindgen package
+	typeParameters int    // number of type parameters of the Move struct
+	typeArguments  []int  // indexes of the type parameters that are type arguments of the Go type, phantom ones are dropped
+}
+
+// frameworkStructs defines the Go types of the framework structs that are used by fields of other packages.
+var frameworkStructs = map[string]frameworkStruct{
+	utils.MoveStdlibAddress + "::option::Option":              {name: "Option", typeParameters: 1, typeArguments: []int{0}},
+	utils.MoveStdlibAddress + "::type_name::TypeName":         {name: "TypeName"},
+	utils.SuiFrameworkAddress + "::object::UID":               {name: "UID"},
+	utils.SuiFrameworkAddress + "::balance::Balance":          {name: "Balance", typeParameters: 1},
+	utils.SuiFrameworkAddress + "::balance::Supply":           {name: "Supply", typeParameters: 1},
+	utils.SuiFrameworkAddress + "::coin::Coin":                {name: "Coin", typeParameters: 1},
+	utils.SuiFrameworkAddress + "::table::Table":              {name: "Table", typeParameters: 2},
+	utils.SuiFrameworkAddress + "::object_table::ObjectTable": {name: "Table", typeParameters: 2},
+	utils.SuiFrameworkAddress + "::bag::Bag":                  {name: "Table"},
+	utils.SuiFrameworkAddress + "::object_bag::ObjectBag":     {name: "Table"},
+	utils.SuiFrameworkAddress + "::table_vec::TableVec":       {name: "TableVec", typeParameters: 1},
+	utils.SuiFrameworkAddress + "::linked_table::LinkedTable": {name: "LinkedTable", typeParameters: 2, typeArguments: []int{0}},
+	utils.SuiFrameworkAddress + "::vec_map::VecMap":           {name: "VecMap", typeParameters: 2, typeArguments: []int{0, 1}},
+	utils.SuiFrameworkAddress + "::vec_map::Entry":            {name: "Entry", typeParameters: 2, typeArguments: []int{0, 1}},
+	utils.SuiFrameworkAddress + "::vec_set::VecSet":           {name: "VecSet", typeParameters: 1, typeArguments: []int{0}},
+}
+
+func (f *file) structGoType(s types.SuiMoveNormalizedTypeStructStruct, typeParams map[uint64]string) (string, error) {
+	name := fmt.Sprintf("%s::%s::%s", utils.NormalizeSuiAddress(s.Address), s.Module, s.Name)
+	switch name {
+	case utils.MoveStdlibAddress + "::string::String", utils.MoveStdlibAddress + "::ascii::String", utils.SuiFrameworkAddress + "::url::Url":
+		return "string", nil
+	case utils.SuiFrameworkAddress + "::object::ID":
+		return f.use(suiTypesImport) + ".ObjectID", nil
+	}
+	if framework, ok := frameworkStructs[name]; ok {
+		if len(s.TypeArguments) != framework.typeParameters {
+			return "", fmt.Errorf("struct [%s::%s] must have %d type arguments, got %d", s.Module, s.Name, framework.typeParameters, len(s.TypeArguments))
+		}
+		arguments := make([]string, len(framework.typeArguments))
+		for idx, param := range framework.typeArguments {
+			argument, err := f.goType(s.TypeArguments[param].SuiMoveNormalizedType, typeParams)
+			if err != nil {
+				return "", err
+			}
+			arguments[idx] = argument
+		}
+		goType := f.use(bindgenImport) + "." + framework.name
+		if len(arguments) > 0 {
+			goType += "[" + strings.Join(arguments, ", ") + "]"
+		}
+		return goType, nil
+	}
+
+	module, ok := f.modules[s.Module]
+	if !ok || utils.NormalizeSuiAddress(s.Address) != utils.NormalizeSuiAddress(f.module.Address) {
+		return f.use(bindgenImport) + ".Raw", nil
+	}
+	definition, ok := module.Structs[s.Name]
+	if !ok {
+		return "", fmt.Errorf("unknown struct [%s::%s]", s.Module, s.Name)
+	}
+
+	goType := exportedName(s.Name)
+	if s.Module != f.module.Name {
+		if f.options.ImportPath == "" {
+			return "", fmt.Errorf("missing import path for struct [%s::%s]", s.Module, s.Name)
+		}
+		goType = f.use(path.Join(f.options.ImportPath, packageName(s.Module))) + "." + goType
+	}
+	var arguments []string
+	for idx, param := range definition.TypeParameters {
+		if param.IsPhantom || idx >= len(s.TypeArguments) {
+			continue
+		}
+		argument, err := f.goType(s.TypeArguments[idx].SuiMoveNormalizedType, typeParams)
+		if err != nil {
+			return "", err
+		}
+		arguments = append(arguments, argument)
+	}
+	if len(arguments) > 0 {
+		goType += "[" + strings.Join(arguments, ", ") + "]"
+	}
+	return goType, nil
+}
+
+// pureType returns the Go type of a parameter passed as a pure value, objects and other values are passed as an Arg.
+func (f *file) pureType(normalizedType types.SuiMoveNormalizedType) (string, bool) {
+	switch t := normalizedType.(type) {
+	case types.SuiMoveNormalizedTypeString:
+		switch t {
+		case "Bool", "U8", "U16", "U32", "U64":
+			return strings.ToLower(strings.Replace(string(t), "U", "uint", 1)), true
+		case "U128", "U256":
+			return "*" + f.use(bcsImport) + ".Uint" + string(t[1:]), true
+		case "Address":
+			return f.use(suiTypesImport) + ".SuiAddress", true
+		}
+	case types.SuiMoveNormalizedTypeVector:
+		element, ok := f.pureType(t.Vector.SuiMoveNormalizedType)
+		if ok && !strings.HasPrefix(element, "*") && !strings.HasPrefix(element, "[]") {
+			return "[]" + element, true
+		}
+	case types.SuiMoveNormalizedTypeStruct:
+		switch fmt.Sprintf("%s::%s::%s", utils.NormalizeSuiAddress(t.Struct.Address), t.Struct.Module, t.Struct.Name) {
+		case utils.MoveStdlibAddress + "::string::String", utils.MoveStdlibAddress + "::ascii::String":
+			return "string", true
+		case utils.SuiFrameworkAddress + "::object::ID":
+			return f.use(suiTypesImport) + ".ObjectID", true
+		}
+	}
+	return "", false
+}
+
+// formatType formats a normalized type for doc comments, structs of the module are named without their address and module.
+func (f *file) formatType(normalizedType types.SuiMoveNormalizedType) string {
+	switch t := normalizedType.(type) {
+	case types.SuiMoveNormalizedTypeString:
+		return strings.ToLower(string(t))
+	case types.SuiMoveNormalizedTypeVector:
+		return "vector<" + f.formatType(t.Vector.SuiMoveNormalizedType) + ">"
+	case types.SuiMoveNormalizedTypeTypeParameter:
+		return fmt.Sprintf("T%d", t.TypeParameter)
+	case types.SuiMoveNormalizedTypeReference:
+		return "&" + f.formatType(t.Reference.SuiMoveNormalizedType)
+	case types.SuiMoveNormalizedTypeMutableReference:
+		return "&mut " + f.formatType(t.MutableReference.SuiMoveNormalizedType)
+	case types.SuiMoveNormalizedTypeStruct:
+		name := fmt.Sprintf("%s::%s::%s", utils.NormalizeShortSuiAddress(t.Struct.Address), t.Struct.Module, t.Struct.Name)
+		if utils.NormalizeSuiAddress(t.Struct.Address) == utils.NormalizeSuiAddress(f.module.Address) && t.Struct.Module == f.module.Name {
+			name = t.Struct.Name
+		}
+		if len(t.Struct.TypeArguments) == 0 {
+			return name
+		}
+		arguments := make([]string, len(t.Struct.TypeArguments))
+		for idx, argument := range t.Struct.TypeArguments {
+			arguments[idx] = f.formatType(argument.SuiMoveNormalizedType)
+		}
+		return name + "<" + strings.Join(arguments, ", ") + ">"
+	default:
+		return "unknown"
+	}
+}
+
+// isTxContext reports whether the parameter is a reference to `0x2::tx_context::TxContext`.
+func isTxContext(normalizedType types.SuiMoveNormalizedType) bool {
+	switch t := normalizedType.(type) {
+	case types.SuiMoveNormalizedTypeReference:
+		normalizedType = t.Reference.SuiMoveNormalizedType
+	case types.SuiMoveNormalizedTypeMutableReference:
+		normalizedType = t.MutableReference.SuiMoveNormalizedType
+	default:
+		return false
+	}
+	s, ok := normalizedType.(types.SuiMoveNormalizedTypeStruct)
+	return ok && utils.NormalizeSuiAddress(s.Struct.Address) == utils.SuiFrameworkAddress && s.Struct.Module == "tx_context" && s.Struct.Name == "TxContext"
+}
+
+// initialisms defines the words written in upper case in Go names.
+var initialisms = map[string]bool{"id": true, "uid": true, "url": true, "uri": true, "api": true, "nft": true}
+
+// exportedName converts a Move identifier to an exported Go name.
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	if b.Len() == 0 {
+		return "X" + name
+	}
+	return b.String()
+}
+
+// packageName converts a Move module name to a Go package name.
+func packageName(module string) string {
+	name := strings.ToLower(module)
+	if token.IsKeyword(name) || slices.Contains([]string{"bindgen", "transactions", "sui_types", "bcs"}, name) {
+		return name + "_move"
+	}
+	return name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
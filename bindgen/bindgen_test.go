@@ -0,0 +1,189 @@
+package bindgen_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/gosui/bindgen"
+	"github.com/W3Tools/gosui/types"
+)
+
+const testModules = `{
+	"pool": {"fileFormatVersion": 6, "address": "0xabc", "name": "pool", "friends": [], "structs": {
+		"Pool": {"abilities": {"abilities": ["Key"]}, "typeParameters": [{"constraints": {"abilities": []}, "isPhantom": true}, {"constraints": {"abilities": ["Store"]}, "isPhantom": false}], "fields": [
+			{"name": "id", "type": {"Struct": {"address": "0x2", "module": "object", "name": "UID", "typeArguments": []}}},
+			{"name": "balance", "type": {"Struct": {"address": "0x2", "module": "balance", "name": "Balance", "typeArguments": [{"TypeParameter": 0}]}}},
+			{"name": "value", "type": {"TypeParameter": 1}},
+			{"name": "fee_bps", "type": "U64"},
+			{"name": "owner", "type": {"Struct": {"address": "0x1", "module": "option", "name": "Option", "typeArguments": ["Address"]}}},
+			{"name": "config", "type": {"Struct": {"address": "0xabc", "module": "config", "name": "Config", "typeArguments": []}}},
+			{"name": "coin", "type": {"Struct": {"address": "0x2", "module": "coin", "name": "Coin", "typeArguments": [{"TypeParameter": 0}]}}},
+			{"name": "holders", "type": {"Struct": {"address": "0x2", "module": "table", "name": "Table", "typeArguments": ["Address", "U64"]}}},
+			{"name": "fees", "type": {"Struct": {"address": "0x2", "module": "vec_map", "name": "VecMap", "typeArguments": ["Address", "U64"]}}},
+			{"name": "queue", "type": {"Struct": {"address": "0x2", "module": "linked_table", "name": "LinkedTable", "typeArguments": ["U64", {"TypeParameter": 1}]}}},
+			{"name": "extra", "type": {"Struct": {"address": "0x2", "module": "bag", "name": "Bag", "typeArguments": []}}},
+			{"name": "external", "type": {"Struct": {"address": "0xdef", "module": "vault", "name": "Vault", "typeArguments": []}}}]},
+		"Deposited": {"abilities": {"abilities": ["Copy", "Drop"]}, "typeParameters": [], "fields": [
+			{"name": "pool_id", "type": {"Struct": {"address": "0x2", "module": "object", "name": "ID", "typeArguments": []}}},
+			{"name": "amount", "type": "U64"}]}
+	}, "exposedFunctions": {
+		"new": {"visibility": "Public", "isEntry": false, "typeParameters": [{"abilities": []}, {"abilities": ["Store"]}], "parameters": [
+			{"TypeParameter": 1}, "U64", {"MutableReference": {"Struct": {"address": "0x2", "module": "tx_context", "name": "TxContext", "typeArguments": []}}}],
+			"return": [{"Struct": {"address": "0xabc", "module": "pool", "name": "Pool", "typeArguments": [{"TypeParameter": 0}, {"TypeParameter": 1}]}}]},
+		"deposit": {"visibility": "Public", "isEntry": true, "typeParameters": [{"abilities": []}], "parameters": [
+			{"MutableReference": {"Struct": {"address": "0xabc", "module": "pool", "name": "Pool", "typeArguments": [{"TypeParameter": 0}, "U8"]}}},
+			{"Struct": {"address": "0x2", "module": "coin", "name": "Coin", "typeArguments": [{"TypeParameter": 0}]}},
+			{"Vector": "U8"}, {"Struct": {"address": "0x1", "module": "string", "name": "String", "typeArguments": []}}, "U128"], "return": []},
+		"fee": {"visibility": "Friend", "isEntry": false, "typeParameters": [], "parameters": [], "return": ["U64"]}
+	}},
+	"config": {"fileFormatVersion": 6, "address": "0xabc", "name": "config", "friends": [], "structs": {
+		"Config": {"abilities": {"abilities": ["Store"]}, "typeParameters": [], "fields": [
+			{"name": "rate", "type": "U128"},
+			{"name": "name", "type": {"Struct": {"address": "0x1", "module": "string", "name": "String", "typeArguments": []}}},
+			{"name": "limits", "type": {"Vector": "U256"}}]}
+	}, "exposedFunctions": {}},
+	"type": {"fileFormatVersion": 6, "address": "0xabc", "name": "type", "friends": [], "structs": {}, "exposedFunctions": {}}
+}`
+
+func TestGenerate(t *testing.T) {
+	var modules types.SuiMoveNormalizedModules
+	if err := json.Unmarshal([]byte(testModules), &modules); err != nil {
+		t.Fatalf("failed to unmarshal modules: %v", err)
+	}
+
+	files, err := bindgen.Generate(modules, bindgen.Options{PackageID: "0xdef", ImportPath: "example.com/bindings"})
+	if err != nil {
+		t.Fatalf("failed to generate bindings: %v", err)
+	}
+	if len(files) != 3 || files["pool/pool.go"] == nil || files["config/config.go"] == nil || files["type_move/type_move.go"] == nil {
+		t.Fatalf("expected a file per module, but got %v", len(files))
+	}
+	checkGeneratedFiles(t, files, "example.com/bindings")
+
+	tests := []struct {
+		name     string
+		file     string
+		expected []string
+	}{
+		{name: "constants", file: "pool/pool.go", expected: []string{
+			"package pool",
+			`PackageID = "0xdef"`,
+			`ModuleName = "pool"`,
+			`PoolType      = "0xabc::pool::Pool"`,
+		}},
+		{name: "struct", file: "pool/pool.go", expected: []string{
+			"type Pool[T1 any] struct {",
+			"ID       bindgen.UID ",
+			"Balance  bindgen.Balance ",
+			"Value    T1 ",
+			"FeeBps   bindgen.U64 ",
+			"Owner    bindgen.Option[sui_types.SuiAddress] ",
+			"Config   config.Config ",
+			"Coin     bindgen.Coin ",
+			"Holders  bindgen.Table ",
+			"Fees     bindgen.VecMap[sui_types.SuiAddress, bindgen.U64] ",
+			"Queue    bindgen.LinkedTable[bindgen.U64] ",
+			"Extra    bindgen.Table ",
+			"External bindgen.Raw ",
+			`"example.com/bindings/config"`,
+			"func (v *Pool[T1]) UnmarshalJSON(data []byte) error {",
+		}},
+		{name: "functions", file: "pool/pool.go", expected: []string{
+			"func New(tx *transactions.Transaction, typeArguments [2]string, arg0 transactions.Arg, arg1 uint64) (transactions.Result, error) {",
+			`return tx.AddMoveCall(PackageID+"::pool::new", []transactions.Arg{arg0, transactions.Pure(arg1)}, typeArguments[:])`,
+			"func Deposit(tx *transactions.Transaction, typeArguments [1]string, arg0 transactions.Arg, arg1 transactions.Arg, arg2 []uint8, arg3 string, arg4 *bcs.Uint128) (transactions.Result, error) {",
+			"with arguments of type `&mut Pool<T0, u8>`, `0x2::coin::Coin<T0>`, `vector<u8>`, `0x1::string::String`, `u128`",
+		}},
+		{name: "other module", file: "config/config.go", expected: []string{
+			"Rate   bcs.Uint128",
+			"Name   string",
+			"Limits []bindgen.U256",
+		}},
+		{name: "keyword", file: "type_move/type_move.go", expected: []string{"package type_move"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := string(files[tt.file])
+			for _, snippet := range tt.expected {
+				if !strings.Contains(source, snippet) {
+					t.Errorf("expected [%s] in %s, but got:\n%s", snippet, tt.file, source)
+				}
+			}
+		})
+	}
+	if strings.Contains(string(files["pool/pool.go"]), "func Fee(") {
+		t.Errorf("expected no binding for a friend function")
+	}
+
+	var invalid types.SuiMoveNormalizedModules
+	if err := json.Unmarshal([]byte(`{"map": {"fileFormatVersion": 6, "address": "0xabc", "name": "map", "friends": [], "structs": {
+		"Map": {"abilities": {"abilities": ["Store"]}, "typeParameters": [], "fields": [
+			{"name": "contents", "type": {"Struct": {"address": "0x2", "module": "vec_map", "name": "VecMap", "typeArguments": ["U8"]}}}]}
+	}, "exposedFunctions": {}}}`), &invalid); err != nil {
+		t.Fatalf("failed to unmarshal modules: %v", err)
+	}
+	if _, err := bindgen.Generate(invalid, bindgen.Options{}); err == nil {
+		t.Errorf("expected an error for a framework struct with missing type arguments, but got nil")
+	}
+	if _, err := bindgen.Generate(modules, bindgen.Options{}); err == nil {
+		t.Errorf("expected an error for a struct of another module without an import path, but got nil")
+	}
+}
+
+// checkGeneratedFiles type-checks the generated packages, they import each other by the import path of the output directory
+// and the other packages are imported from source.
+func checkGeneratedFiles(t *testing.T, files map[string][]byte, importPath string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	sources := importer.ForCompiler(fset, "source", nil).(gotypes.ImporterFrom)
+	generated := make(map[string]*gotypes.Package)
+
+	var check func(string) (*gotypes.Package, error)
+	imports := importerFunc(func(path string) (*gotypes.Package, error) {
+		if strings.HasPrefix(path, importPath+"/") {
+			return check(strings.TrimPrefix(path, importPath+"/"))
+		}
+		return sources.ImportFrom(path, ".", 0)
+	})
+	check = func(dir string) (*gotypes.Package, error) {
+		if pkg, ok := generated[dir]; ok {
+			return pkg, nil
+		}
+		name := path.Join(dir, path.Base(dir)+".go")
+		source, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("missing generated file [%s]", name)
+		}
+		file, err := parser.ParseFile(fset, name, source, parser.AllErrors)
+		if err != nil {
+			return nil, err
+		}
+		config := gotypes.Config{Importer: imports}
+		pkg, err := config.Check(path.Join(importPath, dir), fset, []*ast.File{file}, nil)
+		if err != nil {
+			return nil, err
+		}
+		generated[dir] = pkg
+		return pkg, nil
+	}
+
+	for name := range files {
+		if _, err := check(path.Dir(name)); err != nil {
+			t.Errorf("failed to type-check generated file [%s]: %v", name, err)
+		}
+	}
+}
+
+type importerFunc func(path string) (*gotypes.Package, error)
+
+func (f importerFunc) Import(path string) (*gotypes.Package, error) {
+	return f(path)
+}
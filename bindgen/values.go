@@ -0,0 +1,327 @@
+package bindgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+// U64 defines a Move u64, which is a string in JSON.
+type U64 uint64
+
+// MarshalJSON encodes the value as a string.
+func (v U64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(v), 10))
+}
+
+// UnmarshalJSON decodes the value from a string or a number.
+func (v *U64) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseUint(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid u64 %s: %v", data, err)
+	}
+	*v = U64(value)
+	return nil
+}
+
+// U256 defines a Move u256, which is a string in JSON.
+type U256 struct {
+	bcs.Uint256
+}
+
+// MarshalJSON encodes the value as a string.
+func (v U256) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Big().String())
+}
+
+// UnmarshalJSON decodes the value from a string or a number.
+func (v *U256) UnmarshalJSON(data []byte) error {
+	value, ok := new(big.Int).SetString(strings.Trim(string(data), `"`), 10)
+	if !ok || !v.SetBigInt(value) {
+		return fmt.Errorf("invalid u256 %s", data)
+	}
+	return nil
+}
+
+// MarshalBCS encodes the value as 32 little-endian bytes.
+func (v U256) MarshalBCS() ([]byte, error) {
+	return v.Uint256.MarshalBCS()
+}
+
+// Balance defines a `0x2::balance::Balance`, which is a string or an object with the value in JSON.
+type Balance U64
+
+// MarshalJSON encodes the balance as a string.
+func (v Balance) MarshalJSON() ([]byte, error) {
+	return U64(v).MarshalJSON()
+}
+
+// UnmarshalJSON decodes the balance from a string, a number or an object with the value.
+func (v *Balance) UnmarshalJSON(data []byte) error {
+	var balance struct {
+		Value U64 `json:"value"`
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		if err := UnmarshalFields(data, &balance); err != nil {
+			return err
+		}
+		*v = Balance(balance.Value)
+		return nil
+	}
+	return (*U64)(v).UnmarshalJSON(data)
+}
+
+// UID defines a `0x2::object::UID`.
+type UID struct {
+	ID sui_types.ObjectID `json:"id"`
+}
+
+// Option defines a `0x1::option::Option`, Value is nil for none.
+type Option[T any] struct {
+	Value *T
+}
+
+// MarshalJSON encodes the option as null or its value.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON decodes the option from null, its value or an object with a vector of the value, which may be wrapped
+// with its type as object content.
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	var vec struct {
+		Vec []T `json:"vec"`
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) == nil && len(fields) == 2 && fields["type"] != nil && fields["fields"] != nil {
+		return o.UnmarshalJSON(fields["fields"])
+	}
+	if len(fields) == 1 && fields["vec"] != nil {
+		if err := json.Unmarshal(data, &vec); err != nil {
+			return err
+		}
+		if len(vec.Vec) > 1 {
+			return fmt.Errorf("option has %d values", len(vec.Vec))
+		}
+		o.Value = nil
+		if len(vec.Vec) == 1 {
+			o.Value = &vec.Vec[0]
+		}
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// MarshalBCS encodes the option as a vector of zero or one value.
+func (o Option[T]) MarshalBCS() ([]byte, error) {
+	if o.Value == nil {
+		return bcs.Marshal([]T{})
+	}
+	return bcs.Marshal([]T{*o.Value})
+}
+
+// UnmarshalBCS decodes the option from a vector of zero or one value.
+func (o *Option[T]) UnmarshalBCS(r io.Reader) (int, error) {
+	var values []T
+	n, err := bcs.NewDecoder(r).Decode(&values)
+	if err != nil {
+		return n, err
+	}
+	if len(values) > 1 {
+		return n, fmt.Errorf("option has %d values", len(values))
+	}
+	o.Value = nil
+	if len(values) == 1 {
+		o.Value = &values[0]
+	}
+	return n, nil
+}
+
+// Coin defines a `0x2::coin::Coin`.
+type Coin struct {
+	ID      UID     `json:"id"`
+	Balance Balance `json:"balance"`
+}
+
+// UnmarshalJSON decodes the coin from the parsed JSON of an event or object content.
+func (v *Coin) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// Supply defines a `0x2::balance::Supply`.
+type Supply struct {
+	Value U64 `json:"value"`
+}
+
+// UnmarshalJSON decodes the supply from the parsed JSON of an event or object content.
+func (v *Supply) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// Table defines a `0x2::table::Table`, `0x2::bag::Bag`, `0x2::object_table::ObjectTable` or `0x2::object_bag::ObjectBag`,
+// the entries are dynamic fields of the table.
+type Table struct {
+	ID   UID `json:"id"`
+	Size U64 `json:"size"`
+}
+
+// UnmarshalJSON decodes the table from the parsed JSON of an event or object content.
+func (v *Table) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// TableVec defines a `0x2::table_vec::TableVec`.
+type TableVec struct {
+	Contents Table `json:"contents"`
+}
+
+// UnmarshalJSON decodes the table from the parsed JSON of an event or object content.
+func (v *TableVec) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// LinkedTable defines a `0x2::linked_table::LinkedTable`, the entries are dynamic fields of the table.
+type LinkedTable[K any] struct {
+	ID   UID       `json:"id"`
+	Size U64       `json:"size"`
+	Head Option[K] `json:"head"`
+	Tail Option[K] `json:"tail"`
+}
+
+// UnmarshalJSON decodes the table from the parsed JSON of an event or object content.
+func (v *LinkedTable[K]) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// VecMap defines a `0x2::vec_map::VecMap`.
+type VecMap[K, V any] struct {
+	Contents []Entry[K, V] `json:"contents"`
+}
+
+// UnmarshalJSON decodes the map from the parsed JSON of an event or object content.
+func (v *VecMap[K, V]) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// Entry defines a `0x2::vec_map::Entry`.
+type Entry[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// UnmarshalJSON decodes the entry from the parsed JSON of an event or object content.
+func (v *Entry[K, V]) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// VecSet defines a `0x2::vec_set::VecSet`.
+type VecSet[K any] struct {
+	Contents []K `json:"contents"`
+}
+
+// UnmarshalJSON decodes the set from the parsed JSON of an event or object content.
+func (v *VecSet[K]) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// TypeName defines a `0x1::type_name::TypeName`.
+type TypeName struct {
+	Name string `json:"name"`
+}
+
+// UnmarshalJSON decodes the type name from the parsed JSON of an event or object content.
+func (v *TypeName) UnmarshalJSON(data []byte) error {
+	return UnmarshalFields(data, v)
+}
+
+// Raw holds the JSON of a struct defined in another package without a known layout, it can not be encoded or decoded
+// with BCS.
+type Raw json.RawMessage
+
+// MarshalJSON returns the JSON of the struct.
+func (r Raw) MarshalJSON() ([]byte, error) {
+	if r == nil {
+		return []byte("null"), nil
+	}
+	return r, nil
+}
+
+// UnmarshalJSON keeps the JSON of the struct.
+func (r *Raw) UnmarshalJSON(data []byte) error {
+	*r = append((*r)[:0], data...)
+	return nil
+}
+
+// MarshalBCS returns an error, the layout of the struct is unknown.
+func (r Raw) MarshalBCS() ([]byte, error) {
+	return nil, fmt.Errorf("can not encode a struct of another package")
+}
+
+// UnmarshalBCS returns an error, the layout of the struct is unknown.
+func (r *Raw) UnmarshalBCS(io.Reader) (int, error) {
+	return 0, fmt.Errorf("can not decode a struct of another package")
+}
+
+// UnmarshalFields decodes the JSON of a struct into the struct pointed to by v, fields are matched by their JSON names.
+// Object content wraps structs with their type as `{"type": ..., "fields": ...}`, the fields are unwrapped.
+func UnmarshalFields(data []byte, v any) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if wrapped, ok := fields["fields"]; ok && len(fields) == 2 && fields["type"] != nil {
+		fields = nil
+		if err := json.Unmarshal(wrapped, &fields); err != nil {
+			return err
+		}
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("can not unmarshal fields into %T", v)
+	}
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		raw, ok := fields[name]
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+		if err := json.Unmarshal(raw, value.Field(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid field [%s]: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// DecodeBCS decodes BCS-encoded bytes of an object or event into T.
+func DecodeBCS[T any](bs []byte) (T, error) {
+	var value T
+	n, err := bcs.Unmarshal(bs, &value)
+	if err != nil {
+		return value, err
+	}
+	if n != len(bs) {
+		return value, fmt.Errorf("%d trailing bytes", len(bs)-n)
+	}
+	return value, nil
+}
+
+// DecodeJSON decodes the parsed JSON of an event or the fields of object content into T.
+func DecodeJSON[T any](parsed any) (T, error) {
+	var value T
+	bs, err := json.Marshal(parsed)
+	if err != nil {
+		return value, err
+	}
+	err = json.Unmarshal(bs, &value)
+	return value, err
+}
@@ -0,0 +1,117 @@
+package bindgen_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/bindgen"
+)
+
+type testPool struct {
+	ID      bindgen.UID                          `json:"id"`
+	Balance bindgen.Balance                      `json:"balance"`
+	FeeBps  bindgen.U64                          `json:"fee_bps"`
+	Owner   bindgen.Option[sui_types.SuiAddress] `json:"owner"`
+	Name    string                               `json:"name"`
+}
+
+func (v *testPool) UnmarshalJSON(data []byte) error {
+	return bindgen.UnmarshalFields(data, v)
+}
+
+func TestDecodeJSON(t *testing.T) {
+	id, err := sui_types.NewObjectIdFromHex("0x5")
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+	expected := testPool{ID: bindgen.UID{ID: *id}, Balance: 500, FeeBps: 30, Owner: bindgen.Option[sui_types.SuiAddress]{Value: id}, Name: "sui"}
+	none := expected
+	none.Owner.Value = nil
+
+	tests := []struct {
+		name     string
+		parsed   string
+		expected testPool
+	}{
+		{
+			name:     "event",
+			parsed:   `{"id": {"id": "0x5"}, "balance": "500", "fee_bps": "30", "owner": "0x5", "name": "sui"}`,
+			expected: expected,
+		},
+		{
+			name: "object content",
+			parsed: `{"type": "0xabc::pool::Pool", "fields": {"id": {"id": "0x5"}, "balance": {"type": "0x2::balance::Balance<0x2::sui::SUI>", "fields": {"value": "500"}},
+				"fee_bps": 30, "owner": {"type": "0x1::option::Option<address>", "fields": {"vec": []}}, "name": "sui"}}`,
+			expected: none,
+		},
+		{
+			name:     "option vector",
+			parsed:   `{"id": {"id": "0x5"}, "balance": 500, "fee_bps": "30", "owner": {"vec": ["0x5"]}, "name": "sui", "unknown": true}`,
+			expected: expected,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed any
+			if err := json.Unmarshal([]byte(tt.parsed), &parsed); err != nil {
+				t.Fatalf("failed to unmarshal json: %v", err)
+			}
+			got, err := bindgen.DecodeJSON[testPool](parsed)
+			if err != nil {
+				t.Fatalf("failed to decode pool: %v", err)
+			}
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %+v, but got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	var parsed any
+	if err := json.Unmarshal([]byte(`{"type": "0x2::vec_map::VecMap<u8, 0x2::coin::Coin<0x2::sui::SUI>>", "fields": {"contents": [{"type": "0x2::vec_map::Entry<u8, 0x2::coin::Coin<0x2::sui::SUI>>",
+		"fields": {"key": 7, "value": {"type": "0x2::coin::Coin<0x2::sui::SUI>", "fields": {"id": {"id": "0x5"}, "balance": "500"}}}}]}}`), &parsed); err != nil {
+		t.Fatalf("failed to unmarshal json: %v", err)
+	}
+	vecMap, err := bindgen.DecodeJSON[bindgen.VecMap[uint8, bindgen.Coin]](parsed)
+	if err != nil || !reflect.DeepEqual(vecMap.Contents, []bindgen.Entry[uint8, bindgen.Coin]{{Key: 7, Value: bindgen.Coin{ID: bindgen.UID{ID: *id}, Balance: 500}}}) {
+		t.Errorf("expected a map of 7 to a coin, but got %+v, %v", vecMap, err)
+	}
+
+	if _, err := bindgen.DecodeJSON[testPool](map[string]any{"fee_bps": "-1"}); err == nil {
+		t.Errorf("expected an error for an invalid u64, but got nil")
+	}
+}
+
+func TestDecodeBCS(t *testing.T) {
+	bs := append([]byte{5}, make([]byte, 31)...)
+	bs = append(bs, 0xf4, 1, 0, 0, 0, 0, 0, 0, 30, 0, 0, 0, 0, 0, 0, 0, 0, 3, 's', 'u', 'i')
+	got, err := bindgen.DecodeBCS[testPool](bs)
+	if err != nil {
+		t.Fatalf("failed to decode pool: %v", err)
+	}
+	if got.ID.ID[0] != 5 || got.Balance != 500 || got.FeeBps != 30 || got.Owner.Value != nil || got.Name != "sui" {
+		t.Errorf("unexpected pool %+v", got)
+	}
+
+	option, err := bindgen.DecodeBCS[bindgen.Option[uint16]]([]byte{1, 7, 0})
+	if err != nil || option.Value == nil || *option.Value != 7 {
+		t.Errorf("expected option with 7, but got %v, %v", option.Value, err)
+	}
+	for _, bs := range [][]byte{{2, 7, 0, 8, 0}, {1, 7}, {0, 1}} {
+		if _, err := bindgen.DecodeBCS[bindgen.Option[uint16]](bs); err == nil {
+			t.Errorf("expected an error for option bytes %v, but got nil", bs)
+		}
+	}
+	coin, err := bindgen.DecodeBCS[bindgen.Coin](append(append([]byte{7}, make([]byte, 31)...), 0xf4, 1, 0, 0, 0, 0, 0, 0))
+	if err != nil || coin.ID.ID[0] != 7 || coin.Balance != 500 {
+		t.Errorf("expected coin 0x7 with balance 500, but got %+v, %v", coin, err)
+	}
+	vecMap, err := bindgen.DecodeBCS[bindgen.VecMap[uint8, bindgen.U64]]([]byte{1, 7, 30, 0, 0, 0, 0, 0, 0, 0})
+	if err != nil || !reflect.DeepEqual(vecMap.Contents, []bindgen.Entry[uint8, bindgen.U64]{{Key: 7, Value: 30}}) {
+		t.Errorf("expected a map of 7 to 30, but got %+v, %v", vecMap, err)
+	}
+	if _, err := bindgen.DecodeBCS[bindgen.Raw]([]byte{0}); err == nil {
+		t.Errorf("expected an error for a struct of another package, but got nil")
+	}
+}
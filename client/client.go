@@ -689,6 +689,7 @@ func (client *SuiClient) ExecuteTransactionBlock(ctx context.Context, input type
 }
 
 // SignAndExecuteTransactionBlock signs and executes a transaction block using the provided signer.
+// Signatures of other signers, such as the gas sponsor of a sponsored transaction, are submitted together with it.
 func (client *SuiClient) SignAndExecuteTransactionBlock(ctx context.Context, input types.SignAndExecuteTransactionBlockParams) (response *types.SuiTransactionBlockResponse, err error) {
 	signatureData, err := input.Signer.SignTransactionBlock(input.TransactionBlock)
 	if err != nil {
@@ -699,7 +700,7 @@ func (client *SuiClient) SignAndExecuteTransactionBlock(ctx context.Context, inp
 		ctx,
 		types.ExecuteTransactionBlockParams{
 			TransactionBlock: input.TransactionBlock,
-			Signature:        []string{signatureData.Signature},
+			Signature:        append([]string{signatureData.Signature}, input.Signatures...),
 			Options:          input.Options,
 			RequestType:      input.RequestType,
 		},
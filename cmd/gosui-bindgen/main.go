@@ -0,0 +1,91 @@
+// Command gosui-bindgen generates Go bindings for the Move modules of a package.
+//
+// The normalized modules are fetched from a full node, or read from a JSON file with the result of
+// `sui_getNormalizedMoveModulesByPackage`:
+//
+//	gosui-bindgen -package 0x... -network mainnet -out ./bindings -import example.com/app/bindings
+//	gosui-bindgen -input modules.json -out ./bindings -import example.com/app/bindings
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/W3Tools/gosui/bindgen"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+func main() {
+	packageID := flag.String("package", "", "ID of the Move package")
+	network := flag.String("network", string(utils.Mainnet), "network of the full node: mainnet, testnet, devnet or localnet")
+	rpc := flag.String("rpc", "", "URL of the full node, overrides the network")
+	input := flag.String("input", "", "JSON file with the normalized modules, used instead of a full node")
+	out := flag.String("out", ".", "output directory, a Go package is generated in a directory per module")
+	importPath := flag.String("import", "", "Go import path of the output directory")
+	flag.Parse()
+
+	if err := run(*packageID, *network, *rpc, *input, *out, *importPath); err != nil {
+		fmt.Fprintf(os.Stderr, "gosui-bindgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(packageID, network, rpc, input, out, importPath string) error {
+	modules, err := loadModules(packageID, network, rpc, input)
+	if err != nil {
+		return err
+	}
+
+	files, err := bindgen.Generate(modules, bindgen.Options{PackageID: packageID, ImportPath: importPath})
+	if err != nil {
+		return err
+	}
+	for name, source := range files {
+		path := filepath.Join(out, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("can not create directory: %v", err)
+		}
+		if err := os.WriteFile(path, source, 0o644); err != nil {
+			return fmt.Errorf("can not write file: %v", err)
+		}
+	}
+	return nil
+}
+
+func loadModules(packageID, network, rpc, input string) (types.SuiMoveNormalizedModules, error) {
+	if input != "" {
+		bs, err := os.ReadFile(input)
+		if err != nil {
+			return nil, fmt.Errorf("can not read modules: %v", err)
+		}
+		var modules types.SuiMoveNormalizedModules
+		if err := json.Unmarshal(bs, &modules); err != nil {
+			return nil, fmt.Errorf("invalid modules in [%s]: %v", input, err)
+		}
+		return modules, nil
+	}
+
+	if packageID == "" {
+		return nil, fmt.Errorf("missing package id or input file")
+	}
+	if rpc == "" {
+		rpc = client.GetFullNodeURL(utils.Network(network))
+	}
+	suiClient, err := client.NewSuiClient(rpc)
+	if err != nil {
+		return nil, fmt.Errorf("can not create sui client: %v", err)
+	}
+	defer suiClient.Close()
+
+	modules, err := suiClient.GetNormalizedMoveModulesByPackage(context.Background(), types.GetNormalizedMoveModulesByPackageParams{Package: packageID})
+	if err != nil {
+		return nil, fmt.Errorf("can not get modules of package [%s]: %v", packageID, err)
+	}
+	return *modules, nil
+}
@@ -0,0 +1,187 @@
+package transactions
+
+import (
+	"fmt"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/utils"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+// Arg is a sealed interface implemented by every value accepted as a command argument.
+// Values are created with Transaction.Object, Transaction.SharedObject, Transaction.ObjectRef, Transaction.Gas, Pure,
+// or returned by a previous command as a Result.
+type Arg interface {
+	isArg()
+}
+
+// PureValue defines the Go types that can be encoded as pure inputs.
+type PureValue interface {
+	bool | uint8 | uint16 | uint32 | uint64 | *bcs.Uint128 | *bcs.Uint256 | string | sui_types.SuiAddress | *sui_types.SuiAddress |
+		[]bool | []uint8 | []uint16 | []uint32 | []uint64 | []string | []sui_types.SuiAddress
+}
+
+// Result defines the result of a command in the transaction.
+type Result struct {
+	Index uint16
+}
+
+// NestedResult defines one of the values returned by a command that has multiple results.
+type NestedResult struct {
+	Index       uint16
+	ResultIndex uint16
+}
+
+// pureArg defines a pure value that is encoded with BCS when the command is added.
+type pureArg struct {
+	value any
+}
+
+// objectArg defines an object input, either referenced by ID and resolved later or by a complete reference.
+type objectArg struct {
+	objectID             string
+	mutable              *bool
+	ref                  *sui_types.ObjectRef
+	initialSharedVersion *uint64 // shared object that does not need to be resolved
+	receiving            bool    // ref is encoded as a receiving object
+}
+
+// inputArg defines a reference to an input that already exists in the transaction.
+type inputArg struct {
+	index uint16
+}
+
+func (Result) isArg()                   {}
+func (NestedResult) isArg()             {}
+func (*pureArg) isArg()                 {}
+func (*objectArg) isArg()               {}
+func (inputArg) isArg()                 {}
+func (*TransactionInputGasCoin) isArg() {}
+
+// Nested returns the i-th value returned by the command.
+func (r Result) Nested(i uint16) NestedResult {
+	return NestedResult{Index: r.Index, ResultIndex: i}
+}
+
+// Pure creates a pure argument from a Go value, the value is encoded with BCS.
+func Pure[T PureValue](value T) Arg {
+	return &pureArg{value: value}
+}
+
+// Object creates an object argument from an object ID, the object is resolved using the SuiClient.
+// When used in a MoveCall, the mutability of shared objects is derived from the function signature.
+func (txb *Transaction) Object(id string) Arg {
+	return &objectArg{objectID: id}
+}
+
+// SharedObject creates a shared object argument with an explicit mutability.
+func (txb *Transaction) SharedObject(id string, mutable bool) Arg {
+	return &objectArg{objectID: id, mutable: &mutable}
+}
+
+// ObjectRef creates an immutable or owned object argument from a complete object reference, no lookup is needed.
+func (txb *Transaction) ObjectRef(ref *sui_types.ObjectRef) Arg {
+	return &objectArg{objectID: ref.ObjectId.String(), ref: ref}
+}
+
+// SharedObjectRef creates a shared object argument with its initial shared version and mutability, no lookup is needed.
+func (txb *Transaction) SharedObjectRef(id string, initialSharedVersion uint64, mutable bool) Arg {
+	return &objectArg{objectID: id, mutable: &mutable, initialSharedVersion: &initialSharedVersion}
+}
+
+// ReceivingRef creates an argument for an object sent to another object, which is received with `transfer::receive`.
+func (txb *Transaction) ReceivingRef(ref *sui_types.ObjectRef) Arg {
+	return &objectArg{objectID: ref.ObjectId.String(), ref: ref, receiving: true}
+}
+
+// argumentToArg converts a sui_types.Argument returned by a command into an Arg.
+func argumentToArg(argument *sui_types.Argument) (Arg, error) {
+	switch {
+	case argument == nil:
+		return nil, fmt.Errorf("nil argument")
+	case argument.GasCoin != nil:
+		return &TransactionInputGasCoin{GasCoin: true}, nil
+	case argument.Input != nil:
+		return inputArg{index: *argument.Input}, nil
+	case argument.Result != nil:
+		return Result{Index: *argument.Result}, nil
+	case argument.NestedResult != nil:
+		return NestedResult{Index: argument.NestedResult.Result1, ResultIndex: argument.NestedResult.Result2}, nil
+	default:
+		return nil, fmt.Errorf("empty argument")
+	}
+}
+
+// setArg sets an Arg at index idx of the unresolved parameter, mutable is used for objects without an explicit mutability.
+func (up *UnresolvedParameter) setArg(txb *Transaction, idx int, arg Arg, mutable bool) error {
+	switch arg := arg.(type) {
+	case *TransactionInputGasCoin:
+		up.Arguments[idx] = &UnresolvedArgument{Argument: &sui_types.Argument{GasCoin: &lib.EmptyEnum{}}}
+	case Result:
+		if int(arg.Index) >= len(txb.builder.Commands) {
+			return fmt.Errorf("result of command %d does not exist", arg.Index)
+		}
+		up.Arguments[idx] = &UnresolvedArgument{Argument: &sui_types.Argument{Result: &arg.Index}}
+	case NestedResult:
+		if int(arg.Index) >= len(txb.builder.Commands) {
+			return fmt.Errorf("result of command %d does not exist", arg.Index)
+		}
+		up.Arguments[idx] = &UnresolvedArgument{
+			Argument: &sui_types.Argument{
+				NestedResult: &struct {
+					Result1 uint16
+					Result2 uint16
+				}{
+					Result1: arg.Index,
+					Result2: arg.ResultIndex,
+				},
+			},
+		}
+	case inputArg:
+		if int(arg.index) >= len(txb.builder.InputsKeyOrder) {
+			return fmt.Errorf("input %d does not exist", arg.index)
+		}
+		up.Arguments[idx] = &UnresolvedArgument{Argument: &sui_types.Argument{Input: &arg.index}}
+	case *pureArg:
+		up.Arguments[idx] = &UnresolvedArgument{Pure: arg.value}
+	case *objectArg:
+		if arg.ref != nil {
+			up.Arguments[idx] = &UnresolvedArgument{Object: &sui_types.ObjectArg{ImmOrOwnedObject: arg.ref}, Receiving: arg.receiving}
+			return nil
+		}
+		if !utils.IsValidSuiObjectID(utils.NormalizeSuiObjectID(arg.objectID)) {
+			return fmt.Errorf("invalid object id [%s]", arg.objectID)
+		}
+		if arg.mutable != nil {
+			mutable = *arg.mutable
+		}
+		if arg.initialSharedVersion != nil {
+			id, err := sui_types.NewObjectIdFromHex(utils.NormalizeSuiObjectID(arg.objectID))
+			if err != nil {
+				return fmt.Errorf("invalid object id [%s]: %v", arg.objectID, err)
+			}
+			up.Arguments[idx] = &UnresolvedArgument{Object: sharedObjectArg(*id, *arg.initialSharedVersion, mutable)}
+			return nil
+		}
+		up.Objects[idx] = UnresolvedObject{ObjectID: arg.objectID, Mutable: mutable}
+	case nil:
+		return fmt.Errorf("nil argument")
+	default:
+		return fmt.Errorf("unsupported argument type %T", arg)
+	}
+
+	return nil
+}
+
+// isPure reports whether the Arg is a pure value.
+func isPure(arg Arg) bool {
+	_, ok := arg.(*pureArg)
+	return ok
+}
+
+// isObject reports whether the Arg is an object input.
+func isObject(arg Arg) bool {
+	_, ok := arg.(*objectArg)
+	return ok
+}
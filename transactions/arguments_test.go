@@ -0,0 +1,113 @@
+package transactions_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+const recipient = "0x0000000000000000000000000000000000000000000000000000000000000abc"
+
+func TestTypedArgumentsMatchUntypedAdapters(t *testing.T) {
+	ctx := context.Background()
+
+	typed := transactions.NewTransaction(nil)
+	result, err := typed.AddSplitCoins(typed.Gas(), []transactions.Arg{transactions.Pure(uint64(100)), transactions.Pure(uint64(200))})
+	if err != nil {
+		t.Fatalf("failed to add split coins: %v", err)
+	}
+	address, err := sui_types.NewAddressFromHex(recipient)
+	if err != nil {
+		t.Fatalf("failed to parse address: %v", err)
+	}
+	if err := typed.AddTransferObjects([]transactions.Arg{result.Nested(0), result.Nested(1)}, transactions.Pure(*address)); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+
+	untyped := transactions.NewTransaction(nil)
+	coins, err := untyped.SplitCoins(ctx, untyped.Gas(), []interface{}{uint64(100), uint64(200)})
+	if err != nil {
+		t.Fatalf("failed to split coins: %v", err)
+	}
+	if err := untyped.TransferObjects(ctx, []interface{}{coins[0], coins[1]}, recipient); err != nil {
+		t.Fatalf("failed to transfer objects: %v", err)
+	}
+
+	expected, err := bcs.Marshal(untyped.TransactionBuilder().Finish())
+	if err != nil {
+		t.Fatalf("failed to marshal untyped transaction: %v", err)
+	}
+	got, err := bcs.Marshal(typed.TransactionBuilder().Finish())
+	if err != nil {
+		t.Fatalf("failed to marshal typed transaction: %v", err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected typed transaction %v, but got %v", expected, got)
+	}
+}
+
+func TestTypedArgumentErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		add  func(tx *transactions.Transaction) error
+	}{
+		{
+			name: "result of a missing command",
+			add: func(tx *transactions.Transaction) error {
+				_, err := tx.AddSplitCoins(transactions.Result{Index: 3}, []transactions.Arg{transactions.Pure(uint64(1))})
+				return err
+			},
+		},
+		{
+			name: "pure value as coin",
+			add: func(tx *transactions.Transaction) error {
+				_, err := tx.AddSplitCoins(transactions.Pure(uint64(1)), []transactions.Arg{transactions.Pure(uint64(1))})
+				return err
+			},
+		},
+		{
+			name: "object as amount",
+			add: func(tx *transactions.Transaction) error {
+				_, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{tx.Object("0x1")})
+				return err
+			},
+		},
+		{
+			name: "object as recipient",
+			add: func(tx *transactions.Transaction) error {
+				return tx.AddTransferObjects([]transactions.Arg{tx.Gas()}, tx.Object("0x1"))
+			},
+		},
+		{
+			name: "invalid object id",
+			add: func(tx *transactions.Transaction) error {
+				return tx.AddMergeCoins(tx.Gas(), []transactions.Arg{tx.Object("not an object")})
+			},
+		},
+		{
+			name: "unsupported untyped amount",
+			add: func(tx *transactions.Transaction) error {
+				_, err := tx.SplitCoins(ctx, tx.Gas(), []interface{}{-1})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := transactions.NewTransaction(nil)
+			if err := tt.add(tx); err == nil {
+				t.Errorf("expected an error, but got nil")
+			}
+			if len(tx.TransactionBuilder().Commands) != 0 {
+				t.Errorf("expected no commands, but got %d", len(tx.TransactionBuilder().Commands))
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package transactions
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/utils"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+const (
+	atomicPackage = "0x0000000000000000000000000000000000000000000000000000000000028000"
+	resolvedID    = "0x0000000000000000000000000000000000000000000000000000000000028001"
+	unresolvedID  = "0x0000000000000000000000000000000000000000000000000000000000028002"
+)
+
+// newSeededTransaction creates a transaction with a resolved shared object, an immutable unresolved shared object and a command.
+func newSeededTransaction(t *testing.T) *Transaction {
+	tx := NewTransaction(nil)
+
+	id, err := sui_types.NewObjectIdFromHex(resolvedID)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+	if _, err := tx.objectInput(*sharedObjectArg(*id, 1, false)); err != nil {
+		t.Fatalf("failed to add resolved object: %v", err)
+	}
+	if _, err := tx.AddMoveCall(atomicPackage+"::pool::seed", []Arg{tx.SharedObject(unresolvedID, false), Pure(uint64(1))}, nil); err != nil {
+		t.Fatalf("failed to add seed command: %v", err)
+	}
+
+	return tx
+}
+
+// failingArgs returns arguments that fail after earlier arguments of the command were added as inputs.
+func failingArgs(t *testing.T, tx *Transaction) (pure Arg, object Arg) {
+	id, err := sui_types.NewObjectIdFromHex(resolvedID)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+
+	// int can not be encoded with BCS and an owned reference conflicts with the resolved shared object
+	return &pureArg{value: 1}, tx.ObjectRef(&sui_types.ObjectRef{ObjectId: *id, Version: 1, Digest: make([]byte, 32)})
+}
+
+func objectAt(n int) string {
+	return utils.NormalizeSuiObjectID(fmt.Sprintf("0x2801%d", n))
+}
+
+func TestAddCommandRollsBackOnFailure(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  func(tx *Transaction) []Arg
+		pures map[int]bool // indexes of arguments that must be pure values
+		add   func(tx *Transaction, args []Arg) error
+	}{
+		{
+			name:  "split coins",
+			args:  func(tx *Transaction) []Arg { return []Arg{tx.Object(unresolvedID), Pure(uint64(10)), Pure(uint64(20))} },
+			pures: map[int]bool{1: true, 2: true},
+			add: func(tx *Transaction, args []Arg) error {
+				_, err := tx.AddSplitCoins(args[0], args[1:])
+				return err
+			},
+		},
+		{
+			name: "transfer objects",
+			args: func(tx *Transaction) []Arg {
+				return []Arg{tx.Object(unresolvedID), tx.Object(objectAt(1)), Pure(sui_types.SuiAddress{})}
+			},
+			pures: map[int]bool{2: true},
+			add: func(tx *Transaction, args []Arg) error {
+				return tx.AddTransferObjects(args[:len(args)-1], args[len(args)-1])
+			},
+		},
+		{
+			name: "merge coins",
+			args: func(tx *Transaction) []Arg {
+				return []Arg{tx.Object(objectAt(2)), tx.Object(unresolvedID), tx.Object(objectAt(3))}
+			},
+			add: func(tx *Transaction, args []Arg) error {
+				return tx.AddMergeCoins(args[0], args[1:])
+			},
+		},
+		{
+			name: "make move vec",
+			args: func(tx *Transaction) []Arg { return []Arg{tx.Object(objectAt(4)), tx.Object(unresolvedID)} },
+			add: func(tx *Transaction, args []Arg) error {
+				_, err := tx.AddMakeMoveVec("", args)
+				return err
+			},
+		},
+		{
+			name: "move call",
+			args: func(tx *Transaction) []Arg {
+				return []Arg{tx.SharedObject(unresolvedID, true), Pure(uint64(3)), tx.Object(objectAt(5)), Pure([]uint8{1, 2})}
+			},
+			pures: map[int]bool{1: true, 3: true},
+			add: func(tx *Transaction, args []Arg) error {
+				_, err := tx.AddMoveCall(atomicPackage+"::pool::deposit", args, []string{"0x2::sui::SUI"})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for position := range tt.args(NewTransaction(nil)) {
+			t.Run(fmt.Sprintf("%s failing at argument %d", tt.name, position), func(t *testing.T) {
+				tx := newSeededTransaction(t)
+				expected := tx.snapshot()
+				expectedKeys := append([]sui_types.BuilderArg{}, tx.builder.InputsKeyOrder...)
+				expectedCommands := append([]sui_types.Command{}, tx.builder.Commands...)
+
+				args := tt.args(tx)
+				pure, object := failingArgs(t, tx)
+				if tt.pures[position] {
+					args[position] = pure
+				} else {
+					args[position] = object
+				}
+
+				if err := tt.add(tx, args); err == nil {
+					t.Fatalf("expected an error with a failing argument at index %d, but got nil", position)
+				}
+
+				if !reflect.DeepEqual(expected.inputs, tx.builder.Inputs) {
+					t.Errorf("expected inputs %v, but got %v", expected.inputs, tx.builder.Inputs)
+				}
+				if !reflect.DeepEqual(expectedKeys, tx.builder.InputsKeyOrder) {
+					t.Errorf("expected input keys %v, but got %v", expectedKeys, tx.builder.InputsKeyOrder)
+				}
+				if !reflect.DeepEqual(expectedCommands, tx.builder.Commands) {
+					t.Errorf("expected commands %v, but got %v", expectedCommands, tx.builder.Commands)
+				}
+				if !reflect.DeepEqual(expected.unresolvedObjects, tx.unresolvedObjects) {
+					t.Errorf("expected unresolved objects %v, but got %v", expected.unresolvedObjects, tx.unresolvedObjects)
+				}
+
+				// The same command succeeds afterwards and gives the same transaction as one that never failed
+				if err := tt.add(tx, tt.args(tx)); err != nil {
+					t.Fatalf("failed to add command after rollback: %v", err)
+				}
+				clean := newSeededTransaction(t)
+				if err := tt.add(clean, tt.args(clean)); err != nil {
+					t.Fatalf("failed to add command: %v", err)
+				}
+
+				got, err := bcs.Marshal(tx.builder.Finish())
+				if err != nil {
+					t.Fatalf("failed to marshal transaction: %v", err)
+				}
+				want, err := bcs.Marshal(clean.builder.Finish())
+				if err != nil {
+					t.Fatalf("failed to marshal transaction: %v", err)
+				}
+				if !reflect.DeepEqual(want, got) {
+					t.Errorf("expected transaction %v, but got %v", want, got)
+				}
+				if !reflect.DeepEqual(clean.unresolvedObjects, tx.unresolvedObjects) {
+					t.Errorf("expected unresolved objects %v, but got %v", clean.unresolvedObjects, tx.unresolvedObjects)
+				}
+			})
+		}
+	}
+}
@@ -1,134 +1,402 @@
 package transactions
 
 import (
+	"container/list"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/W3Tools/go-sui-sdk/v2/move_types"
 	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
 	"github.com/W3Tools/gosui/types"
 	"github.com/W3Tools/gosui/utils"
 )
 
-// cache defines a global instance of Cache for Move functions and shared or immutable objects.
-var cache = &Cache{
-	MoveFunction:            make(map[string]*MoveFunctionCacheEntry),
-	SharedOrImmutableObject: make(map[string]*SharedObjectCacheEntry),
+// DefaultCacheCapacity defines the number of entries kept by the default cache.
+const DefaultCacheCapacity = 10000
+
+// Cache defines a cache for Move functions and shared or immutable objects used to resolve transactions.
+// Entries never go stale on one network: a package ID is immutable, an upgrade publishes a new ID, and the initial shared version
+// of an object never changes. A cache that is reused after a network is reset must be cleared. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	GetMoveFunctionDefinition(pkg, mod, fn string) *MoveFunctionCacheEntry
+	AddMoveFunctionDefinition(entry *MoveFunctionCacheEntry)
+	DeleteMoveFunctionDefinition(pkg, mod, fn string)
+	GetSharedObject(id string) *SharedObjectCacheEntry
+	AddSharedObject(entry *SharedObjectCacheEntry)
+	DeleteSharedObject(id string)
+}
+
+// caches defines the default cache and the caches of SuiClients.
+var caches = struct {
+	mutex        sync.RWMutex
+	defaultCache Cache
+	clients      map[*client.SuiClient]Cache
+}{
+	defaultCache: NewLRUCache(DefaultCacheCapacity),
+	clients:      make(map[*client.SuiClient]Cache),
+}
+
+// DefaultCache returns the cache used by transactions without a cache of their own or of their SuiClient.
+func DefaultCache() Cache {
+	caches.mutex.RLock()
+	defer caches.mutex.RUnlock()
+
+	return caches.defaultCache
+}
+
+// SetDefaultCache sets the cache used by transactions without a cache of their own or of their SuiClient.
+func SetDefaultCache(cache Cache) {
+	caches.mutex.Lock()
+	defer caches.mutex.Unlock()
+
+	caches.defaultCache = cache
+}
+
+// SetClientCache sets the cache used by transactions of the SuiClient, a nil cache removes it.
+func SetClientCache(suiClient *client.SuiClient, cache Cache) {
+	caches.mutex.Lock()
+	defer caches.mutex.Unlock()
+
+	if cache == nil {
+		delete(caches.clients, suiClient)
+	} else {
+		caches.clients[suiClient] = cache
+	}
+}
+
+// ReleaseClient removes the cache and the cached protocol limits of the SuiClient, so they can be garbage collected
+// when the SuiClient is no longer used.
+func ReleaseClient(suiClient *client.SuiClient) {
+	SetClientCache(suiClient, nil)
+	releaseProtocolLimits(suiClient)
+}
+
+// SetCache sets the cache used by the transaction instead of the cache of its SuiClient or the default cache.
+func (txb *Transaction) SetCache(cache Cache) {
+	txb.cache = cache
 }
 
-// Cache defines a cache for Move functions and shared or immutable objects.
-type Cache struct {
-	mutex                   sync.RWMutex
-	MoveFunction            map[string]*MoveFunctionCacheEntry
-	SharedOrImmutableObject map[string]*SharedObjectCacheEntry
+// Cache returns the cache used by the transaction.
+func (txb *Transaction) Cache() Cache {
+	if txb.cache != nil {
+		return txb.cache
+	}
+
+	caches.mutex.RLock()
+	defer caches.mutex.RUnlock()
+
+	if cache, ok := caches.clients[txb.client]; ok && txb.client != nil {
+		return cache
+	}
+	return caches.defaultCache
 }
 
 // MoveFunctionCacheEntry defines a cache entry for a Move function.
 type MoveFunctionCacheEntry struct {
-	Package    string
-	Module     string
-	Function   string
-	Normalized *types.SuiMoveNormalizedFunction
+	Package    string                           `json:"package"`
+	Module     string                           `json:"module"`
+	Function   string                           `json:"function"`
+	Normalized *types.SuiMoveNormalizedFunction `json:"normalized"`
 }
 
 // SharedObjectCacheEntry defines a cache entry for a shared or immutable object.
 type SharedObjectCacheEntry struct {
-	ObjectID             *sui_types.ObjectID
-	InitialSharedVersion *uint64
+	ObjectID             *sui_types.ObjectID `json:"objectId"`
+	InitialSharedVersion *uint64             `json:"initialSharedVersion"`
+}
+
+// ToObjectArg encodes a SharedObjectCacheEntry as a Sui ObjectArg.
+func (entry *SharedObjectCacheEntry) ToObjectArg(mutable bool) *sui_types.ObjectArg {
+	objectArg := new(sui_types.ObjectArg)
+
+	objectArg.SharedObject = &struct {
+		Id                   move_types.AccountAddress
+		InitialSharedVersion uint64
+		Mutable              bool
+	}{
+		Id:                   *entry.ObjectID,
+		InitialSharedVersion: *entry.InitialSharedVersion,
+		Mutable:              mutable,
+	}
+
+	return objectArg
+}
+
+// LRUCache defines an in-memory Cache that evicts the least recently used entries beyond its capacity.
+type LRUCache struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  *list.List               // most recently used first, values are *lruEntry
+	index    map[string]*list.Element // map key is the key of the entry
+}
+
+// lruEntry defines an entry of the LRUCache, either a function or an object.
+type lruEntry struct {
+	key      string
+	function *MoveFunctionCacheEntry
+	object   *SharedObjectCacheEntry
+}
+
+// lruCacheFile defines the content of a persisted LRUCache, the entries are ordered from the least recently used.
+type lruCacheFile struct {
+	MoveFunctions []*MoveFunctionCacheEntry `json:"moveFunctions"`
+	SharedObjects []*SharedObjectCacheEntry `json:"sharedObjects"`
+	Order         []string                  `json:"order"`
+}
+
+// NewLRUCache creates a new LRUCache, a capacity that is not positive defaults to DefaultCacheCapacity.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+
+	return &LRUCache{
+		capacity: capacity,
+		entries:  list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+// LoadLRUCache creates a new LRUCache with the entries persisted by SaveFile, the cache is empty when the file does not exist.
+func LoadLRUCache(path string, capacity int) (*LRUCache, error) {
+	c := NewLRUCache(capacity)
+
+	bs, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file [%s], err: %v", path, err)
+	}
+
+	var file lruCacheFile
+	if err := json.Unmarshal(bs, &file); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cache file [%s], err: %v", path, err)
+	}
+
+	entries := make(map[string]*lruEntry, len(file.MoveFunctions)+len(file.SharedObjects))
+	for _, function := range file.MoveFunctions {
+		if function != nil && function.Normalized != nil {
+			entries[functionKey(function.Package, function.Module, function.Function)] = &lruEntry{function: function}
+		}
+	}
+	for _, object := range file.SharedObjects {
+		if object != nil && object.ObjectID != nil && object.InitialSharedVersion != nil {
+			entries[objectKey(object.ObjectID.String())] = &lruEntry{object: object}
+		}
+	}
+	for _, key := range file.Order {
+		if entry, ok := entries[key]; ok {
+			entry.key = key
+			c.add(entry)
+		}
+	}
+	return c, nil
+}
+
+// SaveFile persists the entries, so a later LoadLRUCache starts with a warm cache.
+func (c *LRUCache) SaveFile(path string) error {
+	c.mutex.Lock()
+	var file lruCacheFile
+	for element := c.entries.Back(); element != nil; element = element.Prev() {
+		entry := element.Value.(*lruEntry)
+		if entry.function != nil {
+			file.MoveFunctions = append(file.MoveFunctions, entry.function)
+		} else {
+			file.SharedObjects = append(file.SharedObjects, entry.object)
+		}
+		file.Order = append(file.Order, entry.key)
+	}
+	c.mutex.Unlock()
+
+	bs, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache, err: %v", err)
+	}
+
+	// the file is replaced at once, so a concurrent load never reads a partial file
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file, err: %v", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(bs); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write cache file, err: %v", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file, err: %v", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace cache file [%s], err: %v", path, err)
+	}
+	return nil
+}
+
+// Len returns the number of cached functions and objects.
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.entries.Len()
+}
+
+// Clear removes all entries.
+func (c *LRUCache) Clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries.Init()
+	c.index = make(map[string]*list.Element)
 }
 
 // GetMoveFunctionDefinition retrieves a cached Move function definition by package, module, and function name.
-func (c *Cache) GetMoveFunctionDefinition(pkg, mod, fn string) *MoveFunctionCacheEntry {
-	name := fmt.Sprintf("%s::%s::%s", utils.NormalizeSuiAddress(pkg), mod, fn)
+func (c *LRUCache) GetMoveFunctionDefinition(pkg, mod, fn string) *MoveFunctionCacheEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
-	return c.MoveFunction[name]
+	if entry := c.get(functionKey(pkg, mod, fn)); entry != nil {
+		return entry.function
+	}
+	return nil
 }
 
 // AddMoveFunctionDefinition adds a Move function definition to the cache.
-func (c *Cache) AddMoveFunctionDefinition(entry *MoveFunctionCacheEntry) {
-	name := fmt.Sprintf("%s::%s::%s", utils.NormalizeSuiAddress(entry.Package), entry.Module, entry.Function)
-
+func (c *LRUCache) AddMoveFunctionDefinition(entry *MoveFunctionCacheEntry) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.MoveFunction[name] = entry
+	c.add(&lruEntry{key: functionKey(entry.Package, entry.Module, entry.Function), function: entry})
 }
 
 // DeleteMoveFunctionDefinition removes a Move function definition from the cache by package, module, and function name.
-func (c *Cache) DeleteMoveFunctionDefinition(pkg, mod, fn string) {
-	name := fmt.Sprintf("%s::%s::%s", utils.NormalizeSuiAddress(pkg), mod, fn)
-
+func (c *LRUCache) DeleteMoveFunctionDefinition(pkg, mod, fn string) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	delete(c.MoveFunction, name)
+	c.remove(functionKey(pkg, mod, fn))
 }
 
 // GetSharedObject retrieves a shared or immutable object from the cache by its ID.
-func (c *Cache) GetSharedObject(id string) *SharedObjectCacheEntry {
-	return c.SharedOrImmutableObject[id]
-}
+func (c *LRUCache) GetSharedObject(id string) *SharedObjectCacheEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 
-// GetSharedObjects retrieves multiple shared or immutable objects from the cache by their IDs.
-func (c *Cache) GetSharedObjects(ids []string) []*SharedObjectCacheEntry {
-	entries := make([]*SharedObjectCacheEntry, 0)
+	if entry := c.get(objectKey(id)); entry != nil {
+		return entry.object
+	}
+	return nil
+}
 
+// GetSharedObjects retrieves multiple shared or immutable objects from the cache by their IDs, missing objects are nil.
+func (c *LRUCache) GetSharedObjects(ids []string) []*SharedObjectCacheEntry {
+	entries := make([]*SharedObjectCacheEntry, 0, len(ids))
 	for _, id := range ids {
-		entries = append(entries, c.SharedOrImmutableObject[id])
+		entries = append(entries, c.GetSharedObject(id))
 	}
 
 	return entries
 }
 
 // AddSharedObject adds a shared or immutable object to the cache.
-func (c *Cache) AddSharedObject(entry *SharedObjectCacheEntry) {
+func (c *LRUCache) AddSharedObject(entry *SharedObjectCacheEntry) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.SharedOrImmutableObject[entry.ObjectID.String()] = entry
+	c.add(&lruEntry{key: objectKey(entry.ObjectID.String()), object: entry})
 }
 
 // AddSharedObjects adds multiple shared or immutable objects to the cache.
-func (c *Cache) AddSharedObjects(entries []*SharedObjectCacheEntry) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
+func (c *LRUCache) AddSharedObjects(entries []*SharedObjectCacheEntry) {
 	for _, entry := range entries {
-		c.SharedOrImmutableObject[entry.ObjectID.String()] = entry
+		c.AddSharedObject(entry)
 	}
 }
 
 // DeleteSharedObject removes a shared or immutable object from the cache by its ID.
-func (c *Cache) DeleteSharedObject(id string) {
+func (c *LRUCache) DeleteSharedObject(id string) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	delete(c.SharedOrImmutableObject, id)
+	c.remove(objectKey(id))
 }
 
 // DeleteSharedObjects removes multiple shared or immutable objects from the cache by their IDs.
-func (c *Cache) DeleteSharedObjects(ids []string) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
+func (c *LRUCache) DeleteSharedObjects(ids []string) {
 	for _, id := range ids {
-		delete(c.SharedOrImmutableObject, id)
+		c.DeleteSharedObject(id)
 	}
 }
 
-// ToObjectArg encodes a SharedObjectCacheEntry as a Sui ObjectArg.
-func (entry *SharedObjectCacheEntry) ToObjectArg(mutable bool) *sui_types.ObjectArg {
-	objectArg := new(sui_types.ObjectArg)
+// get returns the entry and marks it as the most recently used, the caller must hold the mutex.
+func (c *LRUCache) get(key string) *lruEntry {
+	element, ok := c.index[key]
+	if !ok {
+		return nil
+	}
 
-	objectArg.SharedObject = &struct {
-		Id                   move_types.AccountAddress
-		InitialSharedVersion uint64
-		Mutable              bool
-	}{
-		Id:                   *entry.ObjectID,
-		InitialSharedVersion: *entry.InitialSharedVersion,
-		Mutable:              mutable,
+	c.entries.MoveToFront(element)
+	return element.Value.(*lruEntry)
+}
+
+// add adds or replaces the entry and evicts the least recently used entries beyond the capacity, the caller must hold the mutex.
+func (c *LRUCache) add(entry *lruEntry) {
+	if element, ok := c.index[entry.key]; ok {
+		element.Value = entry
+		c.entries.MoveToFront(element)
+		return
 	}
 
-	return objectArg
+	c.index[entry.key] = c.entries.PushFront(entry)
+	for c.entries.Len() > c.capacity {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.index, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// remove removes the entry, the caller must hold the mutex.
+func (c *LRUCache) remove(key string) {
+	if element, ok := c.index[key]; ok {
+		c.entries.Remove(element)
+		delete(c.index, key)
+	}
+}
+
+func functionKey(pkg, mod, fn string) string {
+	return fmt.Sprintf("function:%s::%s::%s", utils.NormalizeSuiAddress(pkg), mod, fn)
+}
+
+func objectKey(id string) string {
+	return "object:" + utils.NormalizeSuiObjectID(id)
+}
+
+// observeSharedObjects caches the initial shared versions of the shared objects in the effects of an executed transaction.
+func (txb *Transaction) observeSharedObjects(response *types.SuiTransactionBlockResponse) {
+	if response.Effects == nil {
+		return
+	}
+
+	cache := txb.Cache()
+	for _, objects := range [][]types.OwnedObjectRef{response.Effects.Created, response.Effects.Mutated, response.Effects.Unwrapped} {
+		for _, object := range objects {
+			shared, ok := object.Owner.ObjectOwner.(types.ObjectOwnerShared)
+			if !ok {
+				continue
+			}
+			id, err := sui_types.NewObjectIdFromHex(object.Reference.ObjectID)
+			if err != nil {
+				continue
+			}
+			cache.AddSharedObject(&SharedObjectCacheEntry{ObjectID: id, InitialSharedVersion: &shared.Shared.InitialSharedVersion})
+		}
+	}
 }
@@ -0,0 +1,159 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+)
+
+func sharedObjectEntry(t *testing.T, id string, initialSharedVersion uint64) *transactions.SharedObjectCacheEntry {
+	objectID, err := sui_types.NewObjectIdFromHex(id)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+	return &transactions.SharedObjectCacheEntry{ObjectID: objectID, InitialSharedVersion: &initialSharedVersion}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	pkg := objectID(0x38001)
+	cache := transactions.NewLRUCache(2)
+	cache.AddMoveFunctionDefinition(&transactions.MoveFunctionCacheEntry{Package: pkg, Module: "pool", Function: "deposit", Normalized: &types.SuiMoveNormalizedFunction{}})
+	cache.AddSharedObject(sharedObjectEntry(t, objectID(0x38002), 1))
+
+	if cache.GetMoveFunctionDefinition("0x38001", "pool", "deposit") == nil {
+		t.Fatalf("expected the function to be cached with a short package id")
+	}
+	cache.AddSharedObject(sharedObjectEntry(t, objectID(0x38003), 1))
+
+	if cache.Len() != 2 || cache.GetSharedObject(objectID(0x38002)) != nil {
+		t.Errorf("expected the least recently used object to be evicted")
+	}
+	if cache.GetMoveFunctionDefinition(pkg, "pool", "deposit") == nil || cache.GetSharedObject(objectID(0x38003)) == nil {
+		t.Errorf("expected the recently used entries to be kept")
+	}
+
+	cache.DeleteSharedObject(objectID(0x38003))
+	if cache.Len() != 1 {
+		t.Errorf("expected 1 entry after delete, but got %d", cache.Len())
+	}
+}
+
+func TestLRUCacheFilePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	pkg := objectID(0x38201)
+
+	empty, err := transactions.LoadLRUCache(path, 0)
+	if err != nil || empty.Len() != 0 {
+		t.Fatalf("expected an empty cache without a file, but got %d entries and error %v", empty.Len(), err)
+	}
+
+	function := depositFunction(t, pkg)
+	cache := transactions.NewLRUCache(0)
+	cache.AddMoveFunctionDefinition(&transactions.MoveFunctionCacheEntry{Package: pkg, Module: "pool", Function: "deposit", Normalized: function})
+	cache.AddSharedObject(sharedObjectEntry(t, objectID(0x38202), 7))
+	if err := cache.SaveFile(path); err != nil {
+		t.Fatalf("failed to save cache: %v", err)
+	}
+
+	loaded, err := transactions.LoadLRUCache(path, 1)
+	if err != nil {
+		t.Fatalf("failed to load cache: %v", err)
+	}
+	if loaded.Len() != 1 || loaded.GetMoveFunctionDefinition(pkg, "pool", "deposit") != nil {
+		t.Errorf("expected only the most recently used entry to be loaded into a cache of capacity 1")
+	}
+	if entry := loaded.GetSharedObject(objectID(0x38202)); entry == nil || *entry.InitialSharedVersion != 7 {
+		t.Errorf("expected shared object with initial shared version 7, but got %+v", entry)
+	}
+
+	loaded, err = transactions.LoadLRUCache(path, 0)
+	if err != nil {
+		t.Fatalf("failed to load cache: %v", err)
+	}
+	entry := loaded.GetMoveFunctionDefinition(pkg, "pool", "deposit")
+	if entry == nil || !reflect.DeepEqual(function, entry.Normalized) {
+		t.Errorf("expected function %+v, but got %+v", function, entry)
+	}
+}
+
+func TestLRUCacheConcurrentAccess(t *testing.T) {
+	cache := transactions.NewLRUCache(16)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := objectID(0x38300 + (i*100+j)%32)
+				cache.AddSharedObject(sharedObjectEntry(t, id, uint64(j)))
+				cache.GetSharedObject(id)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if cache.Len() > 16 {
+		t.Errorf("expected at most 16 entries, but got %d", cache.Len())
+	}
+}
+
+func TestTransactionCache(t *testing.T) {
+	pkg, pool := objectID(0x38401), objectID(0x38402)
+	target := pkg + "::pool::deposit"
+
+	newTransaction := func(cache transactions.Cache) *transactions.Transaction {
+		tx := transactions.NewTransaction(nil)
+		tx.SetCache(cache)
+		if _, err := tx.AddMoveCall(target, []transactions.Arg{tx.Object(pool), tx.ObjectRef(objectRef(t, objectID(0x38403), 1)), transactions.Pure(uint64(1))}, nil); err != nil {
+			t.Fatalf("failed to add move call: %v", err)
+		}
+		if err := tx.SupplySharedObject(pool, 1); err != nil {
+			t.Fatalf("failed to supply shared object: %v", err)
+		}
+		return tx
+	}
+
+	cache := transactions.NewLRUCache(0)
+	tx := newTransaction(cache)
+	if err := tx.SupplyMoveFunction(target, depositFunction(t, pkg)); err != nil {
+		t.Fatalf("failed to supply move function: %v", err)
+	}
+	if cache.GetMoveFunctionDefinition(pkg, "pool", "deposit") == nil || transactions.DefaultCache().GetMoveFunctionDefinition(pkg, "pool", "deposit") != nil {
+		t.Fatalf("expected the function to be supplied to the cache of the transaction only")
+	}
+	if _, err := newTransaction(cache).BuildKind(context.Background()); err != nil {
+		t.Errorf("failed to build transaction kind with the cached function: %v", err)
+	}
+
+	var missing *transactions.MissingDataError
+	if _, err := newTransaction(transactions.NewLRUCache(0)).BuildKind(context.Background()); !errors.As(err, &missing) {
+		t.Errorf("expected a missing data error with another cache, but got %v", err)
+	}
+}
+
+func TestClientCache(t *testing.T) {
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){})
+	cache := transactions.NewLRUCache(0)
+	transactions.SetClientCache(suiClient, cache)
+
+	tx := transactions.NewTransaction(suiClient)
+	if tx.Cache() != transactions.Cache(cache) {
+		t.Errorf("expected the cache of the client")
+	}
+	if transactions.NewTransaction(nil).Cache() != transactions.DefaultCache() {
+		t.Errorf("expected the default cache without a client")
+	}
+
+	transactions.ReleaseClient(suiClient)
+	if tx.Cache() != transactions.DefaultCache() {
+		t.Errorf("expected the default cache after the client is released")
+	}
+}
@@ -0,0 +1,191 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// CoinSelectionStrategy defines how coins are selected to cover an amount.
+type CoinSelectionStrategy int
+
+const (
+	// LargestFirst selects coins with the largest balances first.
+	LargestFirst CoinSelectionStrategy = iota
+	// SmallestSufficient selects the smallest single coin that covers the amount, and falls back to LargestFirst.
+	SmallestSufficient
+	// MinimizeInputs selects the fewest coins, using the smallest coin that completes the amount as the last one.
+	MinimizeInputs
+	// AllCoins selects all coins up to the coin limit, the largest first, and fails when they do not cover the amount.
+	AllCoins
+)
+
+// coinsPageLimit defines the number of coins fetched by one get coins request.
+const coinsPageLimit = 50
+
+// maxGasPaymentCoins defines the maximum number of gas coins, Sui nodes require fewer gas coins than max_gas_payment_objects.
+var maxGasPaymentCoins = utils.MaxGasObjects - 1
+
+// CoinSelectionOptions defines options for selecting coins.
+type CoinSelectionOptions struct {
+	Strategy CoinSelectionStrategy
+	MaxCoins int      // maximum number of selected coins, defaults to utils.MaxGasObjects - 1
+	Exclude  []string // object IDs that must not be selected
+}
+
+// InsufficientBalanceError defines an error returned when the selectable coins of an owner do not cover the required amount.
+type InsufficientBalanceError struct {
+	Owner     string
+	CoinType  string
+	Required  uint64
+	Available uint64 // total balance of the coins that can be selected within the coin limit
+}
+
+// Error implements the error interface.
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("insufficient balance of [%s] for owner [%s], required: %d, available: %d, shortfall: %d", e.CoinType, e.Owner, e.Required, e.Available, e.Shortfall())
+}
+
+// Shortfall returns the amount missing to cover the required amount.
+func (e *InsufficientBalanceError) Shortfall() uint64 {
+	if e.Available >= e.Required {
+		return 0
+	}
+	return e.Required - e.Available
+}
+
+// SelectCoins pages through the coins of the owner and selects coins of coinType that cover the amount.
+// An *InsufficientBalanceError is returned when the coins do not cover the amount.
+func SelectCoins(ctx context.Context, suiClient *client.SuiClient, owner, coinType string, amount uint64, options *CoinSelectionOptions) ([]types.CoinStruct, error) {
+	if options == nil {
+		options = new(CoinSelectionOptions)
+	}
+	if suiClient == nil {
+		return nil, fmt.Errorf("missing sui client to select coins of [%s]", coinType)
+	}
+
+	coins, err := ownedCoins(ctx, suiClient, owner, coinType, options.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectCoins(coins, amount, options)
+	if err != nil {
+		if insufficient, ok := err.(*InsufficientBalanceError); ok {
+			insufficient.Owner, insufficient.CoinType = owner, coinType
+		}
+		return nil, err
+	}
+	return selected, nil
+}
+
+// ownedCoins pages through the coins of coinType owned by the owner, skipping the excluded object IDs.
+func ownedCoins(ctx context.Context, suiClient *client.SuiClient, owner, coinType string, excludeIDs []string) ([]types.CoinStruct, error) {
+	exclude := make(map[string]bool, len(excludeIDs))
+	for _, id := range excludeIDs {
+		exclude[utils.NormalizeSuiObjectID(id)] = true
+	}
+
+	var coins []types.CoinStruct
+	var cursor *string
+	limit := coinsPageLimit
+	for {
+		page, err := suiClient.GetCoins(ctx, types.GetCoinsParams{Owner: owner, CoinType: &coinType, Cursor: cursor, Limit: &limit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get coins, err: %v", err)
+		}
+		for _, coin := range page.Data {
+			if !exclude[utils.NormalizeSuiObjectID(coin.CoinObjectID)] {
+				coins = append(coins, coin)
+			}
+		}
+		if !page.HasNextPage || page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	return coins, nil
+}
+
+// selectCoins selects coins that cover the amount with the strategy of the options.
+func selectCoins(coins []types.CoinStruct, amount uint64, options *CoinSelectionOptions) ([]types.CoinStruct, error) {
+	maxCoins := options.MaxCoins
+	if maxCoins <= 0 {
+		maxCoins = maxGasPaymentCoins
+	}
+
+	balances := make(map[string]uint64, len(coins))
+	for _, coin := range coins {
+		balance, err := strconv.ParseUint(coin.Balance, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid balance [%s] of coin [%s]: %v", coin.Balance, coin.CoinObjectID, err)
+		}
+		balances[coin.CoinObjectID] = balance
+	}
+
+	sorted := append([]types.CoinStruct{}, coins...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return balances[sorted[i].CoinObjectID] > balances[sorted[j].CoinObjectID]
+	})
+
+	if options.Strategy == SmallestSufficient {
+		for i := len(sorted) - 1; i >= 0; i-- {
+			if balances[sorted[i].CoinObjectID] >= amount {
+				return []types.CoinStruct{sorted[i]}, nil
+			}
+		}
+	}
+
+	if options.Strategy == AllCoins {
+		selected := sorted[:min(maxCoins, len(sorted))]
+		var total uint64
+		for _, coin := range selected {
+			total = saturatingAdd(total, balances[coin.CoinObjectID])
+		}
+		if total < amount || len(selected) == 0 {
+			return nil, &InsufficientBalanceError{Required: amount, Available: total}
+		}
+		return append([]types.CoinStruct{}, selected...), nil
+	}
+
+	var total uint64
+	for count, coin := range sorted {
+		if count == maxCoins {
+			break
+		}
+		total = saturatingAdd(total, balances[coin.CoinObjectID])
+		if total < amount {
+			continue
+		}
+
+		selected := sorted[:count+1]
+		if options.Strategy == MinimizeInputs {
+			// Replace the last coin with the smallest coin that still completes the amount
+			remaining := amount - (total - balances[coin.CoinObjectID])
+			for i := len(sorted) - 1; i > count; i-- {
+				if balances[sorted[i].CoinObjectID] >= remaining {
+					selected = append(sorted[:count:count], sorted[i])
+					break
+				}
+			}
+		}
+		return append([]types.CoinStruct{}, selected...), nil
+	}
+
+	return nil, &InsufficientBalanceError{Required: amount, Available: total}
+}
+
+// saturatingAdd returns a + b, or math.MaxUint64 when the sum overflows.
+func saturatingAdd(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+	return a + b
+}
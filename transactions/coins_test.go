@@ -0,0 +1,172 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// getCoins serves the coins in pages, the cursor is the index of the next coin.
+func getCoins(coins []types.CoinStruct) func(params []json.RawMessage) (any, error) {
+	return func(params []json.RawMessage) (any, error) {
+		start, limit := 0, len(coins)
+		if len(params) > 2 {
+			var cursor *string
+			if err := json.Unmarshal(params[2], &cursor); err != nil {
+				return nil, err
+			}
+			if cursor != nil {
+				start, _ = strconv.Atoi(*cursor)
+			}
+		}
+		if len(params) > 3 {
+			if err := json.Unmarshal(params[3], &limit); err != nil {
+				return nil, err
+			}
+		}
+
+		end := min(start+limit, len(coins))
+		page := types.PaginatedCoins{Data: coins[start:end], HasNextPage: end < len(coins)}
+		if page.HasNextPage {
+			next := strconv.Itoa(end)
+			page.NextCursor = &next
+		}
+		return page, nil
+	}
+}
+
+func newCoins(balances ...uint64) []types.CoinStruct {
+	coins := make([]types.CoinStruct, len(balances))
+	for idx, balance := range balances {
+		coins[idx] = types.CoinStruct{
+			Balance:      strconv.FormatUint(balance, 10),
+			CoinObjectID: objectID(0x30000 + idx),
+			CoinType:     utils.SuiTypeArg,
+			Digest:       zeroDigest,
+			Version:      "1",
+		}
+	}
+	return coins
+}
+
+func TestSelectCoins(t *testing.T) {
+	coins := newCoins(100, 50, 70, 200, 10)
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){"suix_getCoins": getCoins(coins)})
+
+	tests := []struct {
+		name     string
+		amount   uint64
+		options  *transactions.CoinSelectionOptions
+		expected []types.CoinStruct
+	}{
+		{name: "largest first with one coin", amount: 150, expected: []types.CoinStruct{coins[3]}},
+		{name: "largest first with two coins", amount: 250, expected: []types.CoinStruct{coins[3], coins[0]}},
+		{name: "smallest sufficient", amount: 60, options: &transactions.CoinSelectionOptions{Strategy: transactions.SmallestSufficient}, expected: []types.CoinStruct{coins[2]}},
+		{name: "smallest sufficient falls back to largest first", amount: 250, options: &transactions.CoinSelectionOptions{Strategy: transactions.SmallestSufficient}, expected: []types.CoinStruct{coins[3], coins[0]}},
+		{name: "minimize inputs", amount: 250, options: &transactions.CoinSelectionOptions{Strategy: transactions.MinimizeInputs}, expected: []types.CoinStruct{coins[3], coins[1]}},
+		{name: "all coins", amount: 10, options: &transactions.CoinSelectionOptions{Strategy: transactions.AllCoins, MaxCoins: 3}, expected: []types.CoinStruct{coins[3], coins[0], coins[2]}},
+		{name: "exclude objects", amount: 150, options: &transactions.CoinSelectionOptions{Exclude: []string{"0x30003"}}, expected: []types.CoinStruct{coins[0], coins[2]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := transactions.SelectCoins(context.Background(), suiClient, recipient, utils.SuiTypeArg, tt.amount, tt.options)
+			if err != nil {
+				t.Fatalf("failed to select coins: %v", err)
+			}
+			if !reflect.DeepEqual(tt.expected, selected) {
+				t.Errorf("expected coins %v, but got %v", tt.expected, selected)
+			}
+		})
+	}
+}
+
+func TestSelectCoinsInsufficientBalance(t *testing.T) {
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){"suix_getCoins": getCoins(newCoins(100, 50, 70, 200, 10))})
+
+	tests := []struct {
+		name      string
+		amount    uint64
+		options   *transactions.CoinSelectionOptions
+		available uint64
+		shortfall uint64
+	}{
+		{name: "total balance", amount: 1000, available: 430, shortfall: 570},
+		{name: "coin limit", amount: 310, options: &transactions.CoinSelectionOptions{MaxCoins: 2}, available: 300, shortfall: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := transactions.SelectCoins(context.Background(), suiClient, recipient, utils.SuiTypeArg, tt.amount, tt.options)
+
+			var insufficient *transactions.InsufficientBalanceError
+			if !errors.As(err, &insufficient) {
+				t.Fatalf("expected an insufficient balance error, but got %v", err)
+			}
+			if insufficient.Available != tt.available || insufficient.Shortfall() != tt.shortfall || insufficient.CoinType != utils.SuiTypeArg {
+				t.Errorf("expected available %d and shortfall %d, but got %+v", tt.available, tt.shortfall, insufficient)
+			}
+		})
+	}
+}
+
+func TestSelectCoinsPagesThroughCoins(t *testing.T) {
+	balances := make([]uint64, 120)
+	for idx := range balances {
+		balances[idx] = 1
+	}
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){"suix_getCoins": getCoins(newCoins(balances...))})
+
+	selected, err := transactions.SelectCoins(context.Background(), suiClient, recipient, utils.SuiTypeArg, 120, nil)
+	if err != nil {
+		t.Fatalf("failed to select coins: %v", err)
+	}
+	if len(selected) != 120 {
+		t.Errorf("expected 120 coins, but got %d", len(selected))
+	}
+	if got := node.count("suix_getCoins"); got != 3 {
+		t.Errorf("expected 3 get coins requests, but got %d", got)
+	}
+}
+
+func TestBuildExcludesInputCoinsFromGasPayment(t *testing.T) {
+	coins := newCoins(500, 100)
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){"suix_getCoins": getCoins(coins)})
+
+	coin, err := sui_types.NewObjectIdFromHex(coins[0].CoinObjectID)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+
+	tx := transactions.NewTransaction(suiClient)
+	if err := tx.AddTransferObjects([]transactions.Arg{tx.ObjectRef(&sui_types.ObjectRef{ObjectId: *coin, Version: 1, Digest: make([]byte, 32)})}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	tx.SetGasPrice(1000)
+	tx.SetGasBudget(100)
+
+	data, _, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	if payment := data.V1.GasData.Payment; len(payment) != 1 || payment[0].ObjectId.String() != coins[1].CoinObjectID {
+		t.Errorf("expected gas payment with coin %s, but got %v", coins[1].CoinObjectID, payment)
+	}
+
+	tx.SetGasPayment(nil)
+	tx.SetGasBudget(101)
+	_, _, err = tx.Build(context.Background(), recipient)
+
+	var insufficient *transactions.InsufficientBalanceError
+	if !errors.As(err, &insufficient) {
+		t.Errorf("expected an insufficient balance error, but got %v", err)
+	}
+}
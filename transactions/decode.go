@@ -0,0 +1,624 @@
+package transactions
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+)
+
+// bcsDecoder decodes BCS-encoded transactions, the enums of sui_types hold nested enums by pointer
+// which can not be decoded by the bcs package.
+type bcsDecoder struct {
+	data      []byte
+	pos       int
+	receiving []string // IDs of receiving objects, which are decoded as immutable or owned objects
+}
+
+// decodeTransactionKind decodes BCS-encoded transaction kind bytes and returns the IDs of receiving objects.
+func decodeTransactionKind(bs []byte) (*sui_types.TransactionKind, []string, error) {
+	d := &bcsDecoder{data: bs}
+	kind, err := d.transactionKind()
+	if err != nil {
+		return nil, nil, err
+	}
+	return kind, d.receiving, d.finish()
+}
+
+// decodeTransactionData decodes BCS-encoded transaction data bytes and returns the IDs of receiving objects.
+func decodeTransactionData(bs []byte) (*sui_types.TransactionData, []string, error) {
+	d := &bcsDecoder{data: bs}
+	data, err := d.transactionData()
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, d.receiving, d.finish()
+}
+
+func (d *bcsDecoder) finish() error {
+	if d.pos != len(d.data) {
+		return fmt.Errorf("%d trailing bytes after position %d", len(d.data)-d.pos, d.pos)
+	}
+	return nil
+}
+
+func (d *bcsDecoder) bytes(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, fmt.Errorf("unexpected end of bytes at position %d, want %d bytes", d.pos, n)
+	}
+	bs := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return bs, nil
+}
+
+func (d *bcsDecoder) u8() (uint8, error) {
+	bs, err := d.bytes(1)
+	if err != nil {
+		return 0, err
+	}
+	return bs[0], nil
+}
+
+func (d *bcsDecoder) bool() (bool, error) {
+	b, err := d.u8()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid bool %d at position %d", b, d.pos-1)
+	}
+}
+
+func (d *bcsDecoder) u16() (uint16, error) {
+	bs, err := d.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(bs), nil
+}
+
+func (d *bcsDecoder) u32() (uint32, error) {
+	bs, err := d.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(bs), nil
+}
+
+func (d *bcsDecoder) u64() (uint64, error) {
+	bs, err := d.bytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(bs), nil
+}
+
+// uleb128 decodes a length or an enum variant.
+func (d *bcsDecoder) uleb128() (int, error) {
+	var value uint64
+	for shift := 0; shift < 32; shift += 7 {
+		b, err := d.u8()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if value > 1<<31-1 {
+				return 0, fmt.Errorf("uleb128 value %d overflows at position %d", value, d.pos)
+			}
+			return int(value), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid uleb128 at position %d", d.pos)
+}
+
+// length decodes the length of a vector, each element is at least one byte so the length can not exceed the remaining bytes.
+func (d *bcsDecoder) length() (int, error) {
+	n, err := d.uleb128()
+	if err != nil {
+		return 0, err
+	}
+	if n > len(d.data)-d.pos {
+		return 0, fmt.Errorf("length %d exceeds the %d remaining bytes at position %d", n, len(d.data)-d.pos, d.pos)
+	}
+	return n, nil
+}
+
+func (d *bcsDecoder) vector() ([]byte, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	bs, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{}, bs...), nil
+}
+
+func (d *bcsDecoder) string() (string, error) {
+	bs, err := d.vector()
+	return string(bs), err
+}
+
+func (d *bcsDecoder) address() (move_types.AccountAddress, error) {
+	var address move_types.AccountAddress
+	bs, err := d.bytes(len(address))
+	if err != nil {
+		return address, err
+	}
+	copy(address[:], bs)
+	return address, nil
+}
+
+func (d *bcsDecoder) addresses() ([]move_types.AccountAddress, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]move_types.AccountAddress, n)
+	for i := range addresses {
+		if addresses[i], err = d.address(); err != nil {
+			return nil, err
+		}
+	}
+	return addresses, nil
+}
+
+func (d *bcsDecoder) objectRef() (*sui_types.ObjectRef, error) {
+	id, err := d.address()
+	if err != nil {
+		return nil, err
+	}
+	version, err := d.u64()
+	if err != nil {
+		return nil, err
+	}
+	digest, err := d.vector()
+	if err != nil {
+		return nil, err
+	}
+	return &sui_types.ObjectRef{ObjectId: id, Version: version, Digest: lib.Base58(digest)}, nil
+}
+
+func (d *bcsDecoder) transactionData() (*sui_types.TransactionData, error) {
+	variant, err := d.uleb128()
+	if err != nil {
+		return nil, err
+	}
+	if variant != 0 {
+		return nil, fmt.Errorf("unsupported transaction data version %d", variant)
+	}
+
+	kind, err := d.transactionKind()
+	if err != nil {
+		return nil, fmt.Errorf("can not decode transaction kind: %v", err)
+	}
+	sender, err := d.address()
+	if err != nil {
+		return nil, fmt.Errorf("can not decode sender: %v", err)
+	}
+
+	n, err := d.length()
+	if err != nil {
+		return nil, fmt.Errorf("can not decode gas payment: %v", err)
+	}
+	payment := make([]*sui_types.ObjectRef, n)
+	for i := range payment {
+		if payment[i], err = d.objectRef(); err != nil {
+			return nil, fmt.Errorf("can not decode gas payment: %v", err)
+		}
+	}
+	owner, err := d.address()
+	if err != nil {
+		return nil, fmt.Errorf("can not decode gas owner: %v", err)
+	}
+	price, err := d.u64()
+	if err != nil {
+		return nil, fmt.Errorf("can not decode gas price: %v", err)
+	}
+	budget, err := d.u64()
+	if err != nil {
+		return nil, fmt.Errorf("can not decode gas budget: %v", err)
+	}
+
+	var expiration sui_types.TransactionExpiration
+	variant, err = d.uleb128()
+	if err != nil {
+		return nil, fmt.Errorf("can not decode expiration: %v", err)
+	}
+	switch variant {
+	case 0:
+		expiration.None = &lib.EmptyEnum{}
+	case 1:
+		epoch, err := d.u64()
+		if err != nil {
+			return nil, fmt.Errorf("can not decode expiration: %v", err)
+		}
+		expiration.Epoch = &epoch
+	default:
+		return nil, fmt.Errorf("unsupported expiration %d", variant)
+	}
+
+	return &sui_types.TransactionData{
+		V1: &sui_types.TransactionDataV1{
+			Kind:       *kind,
+			Sender:     sender,
+			GasData:    sui_types.GasData{Payment: payment, Owner: owner, Price: price, Budget: budget},
+			Expiration: expiration,
+		},
+	}, nil
+}
+
+func (d *bcsDecoder) transactionKind() (*sui_types.TransactionKind, error) {
+	variant, err := d.uleb128()
+	if err != nil {
+		return nil, err
+	}
+	if variant != 0 {
+		return nil, fmt.Errorf("unsupported transaction kind %d, only programmable transactions are supported", variant)
+	}
+
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]sui_types.CallArg, n)
+	for i := range inputs {
+		if inputs[i], err = d.callArg(); err != nil {
+			return nil, fmt.Errorf("can not decode input %d: %v", i, err)
+		}
+	}
+
+	n, err = d.length()
+	if err != nil {
+		return nil, err
+	}
+	commands := make([]sui_types.Command, n)
+	for i := range commands {
+		if commands[i], err = d.command(); err != nil {
+			return nil, fmt.Errorf("can not decode command %d: %v", i, err)
+		}
+	}
+
+	return &sui_types.TransactionKind{ProgrammableTransaction: &sui_types.ProgrammableTransaction{Inputs: inputs, Commands: commands}}, nil
+}
+
+func (d *bcsDecoder) callArg() (sui_types.CallArg, error) {
+	variant, err := d.uleb128()
+	if err != nil {
+		return sui_types.CallArg{}, err
+	}
+
+	switch variant {
+	case 0:
+		pure, err := d.vector()
+		if err != nil {
+			return sui_types.CallArg{}, err
+		}
+		return sui_types.CallArg{Pure: &pure}, nil
+	case 1:
+		objectArg, err := d.objectArg()
+		if err != nil {
+			return sui_types.CallArg{}, err
+		}
+		return sui_types.CallArg{Object: objectArg}, nil
+	default:
+		return sui_types.CallArg{}, fmt.Errorf("unsupported call arg %d", variant)
+	}
+}
+
+func (d *bcsDecoder) objectArg() (*sui_types.ObjectArg, error) {
+	variant, err := d.uleb128()
+	if err != nil {
+		return nil, err
+	}
+
+	switch variant {
+	case 0:
+		ref, err := d.objectRef()
+		if err != nil {
+			return nil, err
+		}
+		return &sui_types.ObjectArg{ImmOrOwnedObject: ref}, nil
+	case 1:
+		id, err := d.address()
+		if err != nil {
+			return nil, err
+		}
+		version, err := d.u64()
+		if err != nil {
+			return nil, err
+		}
+		mutable, err := d.bool()
+		if err != nil {
+			return nil, err
+		}
+		return sharedObjectArg(id, version, mutable), nil
+	case receivingObjectArgVariant:
+		ref, err := d.objectRef()
+		if err != nil {
+			return nil, err
+		}
+		d.receiving = append(d.receiving, ref.ObjectId.String())
+		return &sui_types.ObjectArg{ImmOrOwnedObject: ref}, nil
+	default:
+		return nil, fmt.Errorf("unsupported object arg %d", variant)
+	}
+}
+
+func (d *bcsDecoder) argument() (sui_types.Argument, error) {
+	variant, err := d.uleb128()
+	if err != nil {
+		return sui_types.Argument{}, err
+	}
+
+	switch variant {
+	case 0:
+		return sui_types.Argument{GasCoin: &lib.EmptyEnum{}}, nil
+	case 1, 2:
+		index, err := d.u16()
+		if err != nil {
+			return sui_types.Argument{}, err
+		}
+		if variant == 1 {
+			return sui_types.Argument{Input: &index}, nil
+		}
+		return sui_types.Argument{Result: &index}, nil
+	case 3:
+		result1, err := d.u16()
+		if err != nil {
+			return sui_types.Argument{}, err
+		}
+		result2, err := d.u16()
+		if err != nil {
+			return sui_types.Argument{}, err
+		}
+		return sui_types.Argument{NestedResult: &struct {
+			Result1 uint16
+			Result2 uint16
+		}{Result1: result1, Result2: result2}}, nil
+	default:
+		return sui_types.Argument{}, fmt.Errorf("unsupported argument %d", variant)
+	}
+}
+
+func (d *bcsDecoder) arguments() ([]sui_types.Argument, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	arguments := make([]sui_types.Argument, n)
+	for i := range arguments {
+		if arguments[i], err = d.argument(); err != nil {
+			return nil, err
+		}
+	}
+	return arguments, nil
+}
+
+func (d *bcsDecoder) modules() ([][]uint8, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	modules := make([][]uint8, n)
+	for i := range modules {
+		if modules[i], err = d.vector(); err != nil {
+			return nil, err
+		}
+	}
+	return modules, nil
+}
+
+func (d *bcsDecoder) command() (sui_types.Command, error) {
+	variant, err := d.uleb128()
+	if err != nil {
+		return sui_types.Command{}, err
+	}
+
+	switch variant {
+	case 0:
+		pkg, err := d.address()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		module, err := d.string()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		function, err := d.string()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		n, err := d.length()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		typeArguments := make([]move_types.TypeTag, n)
+		for i := range typeArguments {
+			typeTag, err := d.typeTag(0)
+			if err != nil {
+				return sui_types.Command{}, err
+			}
+			typeArguments[i] = *typeTag
+		}
+		arguments, err := d.arguments()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		return sui_types.Command{MoveCall: &sui_types.ProgrammableMoveCall{
+			Package:       pkg,
+			Module:        move_types.Identifier(module),
+			Function:      move_types.Identifier(function),
+			TypeArguments: typeArguments,
+			Arguments:     arguments,
+		}}, nil
+	case 1:
+		objects, err := d.arguments()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		address, err := d.argument()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		return sui_types.Command{TransferObjects: &struct {
+			Arguments []sui_types.Argument
+			Argument  sui_types.Argument
+		}{Arguments: objects, Argument: address}}, nil
+	case 2, 3:
+		argument, err := d.argument()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		arguments, err := d.arguments()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		value := &struct {
+			Argument  sui_types.Argument
+			Arguments []sui_types.Argument
+		}{Argument: argument, Arguments: arguments}
+		if variant == 2 {
+			return sui_types.Command{SplitCoins: value}, nil
+		}
+		return sui_types.Command{MergeCoins: value}, nil
+	case 4:
+		modules, err := d.modules()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		dependencies, err := d.addresses()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		return sui_types.Command{Publish: &struct {
+			Bytes   [][]uint8
+			Objects []sui_types.ObjectID
+		}{Bytes: modules, Objects: dependencies}}, nil
+	case 5:
+		var typeTag *move_types.TypeTag
+		optional, err := d.u8()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		if optional == 1 {
+			if typeTag, err = d.typeTag(0); err != nil {
+				return sui_types.Command{}, err
+			}
+		} else if optional != 0 {
+			return sui_types.Command{}, fmt.Errorf("invalid option %d", optional)
+		}
+		arguments, err := d.arguments()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		return sui_types.Command{MakeMoveVec: &struct {
+			TypeTag   *move_types.TypeTag `bcs:"optional"`
+			Arguments []sui_types.Argument
+		}{TypeTag: typeTag, Arguments: arguments}}, nil
+	case 6:
+		modules, err := d.modules()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		dependencies, err := d.addresses()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		pkg, err := d.address()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		ticket, err := d.argument()
+		if err != nil {
+			return sui_types.Command{}, err
+		}
+		return sui_types.Command{Upgrade: &struct {
+			Bytes    [][]uint8
+			Objects  []sui_types.ObjectID
+			ObjectID sui_types.ObjectID
+			Argument sui_types.Argument
+		}{Bytes: modules, Objects: dependencies, ObjectID: pkg, Argument: ticket}}, nil
+	default:
+		return sui_types.Command{}, fmt.Errorf("unsupported command %d", variant)
+	}
+}
+
+// maxTypeTagDepth defines the maximum nesting of decoded type tags.
+const maxTypeTagDepth = 16
+
+func (d *bcsDecoder) typeTag(depth int) (*move_types.TypeTag, error) {
+	if depth > maxTypeTagDepth {
+		return nil, fmt.Errorf("type tag nested deeper than %d", maxTypeTagDepth)
+	}
+
+	variant, err := d.uleb128()
+	if err != nil {
+		return nil, err
+	}
+
+	typeTag := new(move_types.TypeTag)
+	switch variant {
+	case 0:
+		typeTag.Bool = &lib.EmptyEnum{}
+	case 1:
+		typeTag.U8 = &lib.EmptyEnum{}
+	case 2:
+		typeTag.U64 = &lib.EmptyEnum{}
+	case 3:
+		typeTag.U128 = &lib.EmptyEnum{}
+	case 4:
+		typeTag.Address = &lib.EmptyEnum{}
+	case 5:
+		typeTag.Signer = &lib.EmptyEnum{}
+	case 6:
+		if typeTag.Vector, err = d.typeTag(depth + 1); err != nil {
+			return nil, err
+		}
+	case 7:
+		address, err := d.address()
+		if err != nil {
+			return nil, err
+		}
+		module, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		name, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		typeParams := make([]move_types.TypeTag, n)
+		for i := range typeParams {
+			param, err := d.typeTag(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			typeParams[i] = *param
+		}
+		typeTag.Struct = &move_types.StructTag{Address: address, Module: move_types.Identifier(module), Name: move_types.Identifier(name), TypeParams: typeParams}
+	case 8:
+		typeTag.U16 = &lib.EmptyEnum{}
+	case 9:
+		typeTag.U32 = &lib.EmptyEnum{}
+	case 10:
+		typeTag.U256 = &lib.EmptyEnum{}
+	default:
+		return nil, fmt.Errorf("unsupported type tag %d", variant)
+	}
+
+	return typeTag, nil
+}
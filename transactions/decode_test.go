@@ -0,0 +1,98 @@
+package transactions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+func TestDecodeTransactionData(t *testing.T) {
+	id, err := sui_types.NewObjectIdFromHex(resolvedID)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+	ref := &sui_types.ObjectRef{ObjectId: *id, Version: 9, Digest: make([]byte, 32)}
+	pure := []byte{1, 2, 3}
+	input, result, epoch := uint16(1), uint16(0), uint64(42)
+	coinType := move_types.TypeTag{Struct: &move_types.StructTag{
+		Address:    *id,
+		Module:     "coin",
+		Name:       "Coin",
+		TypeParams: []move_types.TypeTag{{Vector: &move_types.TypeTag{U8: &lib.EmptyEnum{}}}, {U256: &lib.EmptyEnum{}}},
+	}}
+
+	pt := sui_types.ProgrammableTransaction{
+		Inputs: []sui_types.CallArg{
+			{Pure: &pure},
+			{Object: &sui_types.ObjectArg{ImmOrOwnedObject: ref}},
+			{Object: sharedObjectArg(*id, 7, true)},
+		},
+		Commands: []sui_types.Command{
+			{MoveCall: &sui_types.ProgrammableMoveCall{Package: *id, Module: "pool", Function: "deposit", TypeArguments: []move_types.TypeTag{coinType}, Arguments: []sui_types.Argument{{Input: &input}, {GasCoin: &lib.EmptyEnum{}}}}},
+			{SplitCoins: &struct {
+				Argument  sui_types.Argument
+				Arguments []sui_types.Argument
+			}{Argument: sui_types.Argument{GasCoin: &lib.EmptyEnum{}}, Arguments: []sui_types.Argument{{Input: &input}}}},
+			{MergeCoins: &struct {
+				Argument  sui_types.Argument
+				Arguments []sui_types.Argument
+			}{Argument: sui_types.Argument{Result: &result}, Arguments: []sui_types.Argument{{NestedResult: &struct {
+				Result1 uint16
+				Result2 uint16
+			}{Result1: 1, Result2: 0}}}}},
+			{TransferObjects: &struct {
+				Arguments []sui_types.Argument
+				Argument  sui_types.Argument
+			}{Arguments: []sui_types.Argument{{Result: &result}}, Argument: sui_types.Argument{Input: &input}}},
+			{MakeMoveVec: &struct {
+				TypeTag   *move_types.TypeTag `bcs:"optional"`
+				Arguments []sui_types.Argument
+			}{TypeTag: &coinType, Arguments: []sui_types.Argument{{Input: &input}}}},
+			{MakeMoveVec: &struct {
+				TypeTag   *move_types.TypeTag `bcs:"optional"`
+				Arguments []sui_types.Argument
+			}{Arguments: []sui_types.Argument{}}},
+			{Publish: &struct {
+				Bytes   [][]uint8
+				Objects []sui_types.ObjectID
+			}{Bytes: [][]uint8{{0xa1, 0x1c}}, Objects: []sui_types.ObjectID{*id}}},
+			{Upgrade: &struct {
+				Bytes    [][]uint8
+				Objects  []sui_types.ObjectID
+				ObjectID sui_types.ObjectID
+				Argument sui_types.Argument
+			}{Bytes: [][]uint8{{0xa1}}, Objects: []sui_types.ObjectID{}, ObjectID: *id, Argument: sui_types.Argument{Result: &result}}},
+		},
+	}
+	data := sui_types.NewProgrammableAllowSponsor(*id, []*sui_types.ObjectRef{ref}, pt, 100, 1000, sui_types.SuiAddress{})
+	data.V1.Expiration = sui_types.TransactionExpiration{Epoch: &epoch}
+
+	bs, err := bcs.Marshal(data)
+	if err != nil {
+		t.Fatalf("failed to marshal transaction data: %v", err)
+	}
+	decoded, _, err := decodeTransactionData(bs)
+	if err != nil {
+		t.Fatalf("failed to decode transaction data: %v", err)
+	}
+	got, err := bcs.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("failed to marshal decoded transaction data: %v", err)
+	}
+	if !reflect.DeepEqual(bs, got) {
+		t.Errorf("expected bytes %v, but got %v", bs, got)
+	}
+
+	for i := 0; i < len(bs); i++ {
+		if _, _, err := decodeTransactionData(bs[:i]); err == nil {
+			t.Fatalf("expected an error for %d truncated bytes, but got nil", len(bs)-i)
+		}
+	}
+	if _, _, err := decodeTransactionData(append(bs, 0)); err == nil {
+		t.Errorf("expected a trailing bytes error, but got nil")
+	}
+}
@@ -0,0 +1,65 @@
+package transactions
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+// receivingObjectArgVariant defines the BCS variant of ObjectArg::Receiving, which is missing from sui_types.ObjectArg.
+const receivingObjectArgVariant = 2
+
+// marshalTransactionData encodes the transaction data with BCS, receiving objects are encoded as ObjectArg::Receiving.
+func (txb *Transaction) marshalTransactionData(tx sui_types.TransactionData) ([]byte, error) {
+	if len(txb.receivingObjects) == 0 || tx.V1 == nil || tx.V1.Kind.ProgrammableTransaction == nil {
+		return bcs.Marshal(tx)
+	}
+
+	kind, err := txb.marshalProgrammableTransaction(*tx.V1.Kind.ProgrammableTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	// TransactionData::V1 followed by the fields of TransactionDataV1
+	buffer := bytes.NewBuffer([]byte{0})
+	buffer.Write(kind)
+	for _, value := range []any{tx.V1.Sender, tx.V1.GasData, tx.V1.Expiration} {
+		bs, err := bcs.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(bs)
+	}
+	return buffer.Bytes(), nil
+}
+
+// marshalProgrammableTransaction encodes a programmable transaction kind with BCS, receiving objects are encoded as ObjectArg::Receiving.
+func (txb *Transaction) marshalProgrammableTransaction(pt sui_types.ProgrammableTransaction) ([]byte, error) {
+	if len(txb.receivingObjects) == 0 {
+		return bcs.Marshal(sui_types.TransactionKind{ProgrammableTransaction: &pt})
+	}
+
+	// TransactionKind::ProgrammableTransaction followed by the inputs and commands
+	buffer := bytes.NewBuffer([]byte{0})
+	buffer.Write(bcs.ULEB128Encode(len(pt.Inputs)))
+	for idx, input := range pt.Inputs {
+		bs, err := bcs.Marshal(input)
+		if err != nil {
+			return nil, fmt.Errorf("can not marshal input %d: %v", idx, err)
+		}
+		if input.Object != nil && input.Object.ImmOrOwnedObject != nil && txb.receivingObjects[input.Object.ImmOrOwnedObject.ObjectId.String()] {
+			// CallArg::Object followed by ObjectArg::ImmOrOwnedObject
+			bs[1] = receivingObjectArgVariant
+		}
+		buffer.Write(bs)
+	}
+
+	commands, err := bcs.Marshal(pt.Commands)
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal commands: %v", err)
+	}
+	buffer.Write(commands)
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,265 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/cryptography"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// DefaultExecutorGasBudget defines the gas budget of transactions executed by an executor without a gas budget.
+const DefaultExecutorGasBudget uint64 = 50000000
+
+// SerialExecutor defines an executor that signs and executes the transactions of one signer strictly in order. Owned objects
+// and the gas coin are taken from the effects of executed transactions instead of being fetched again.
+type SerialExecutor struct {
+	client *client.SuiClient
+	signer cryptography.Signer
+	queue  chan struct{}
+	cache  *objectCache
+
+	GasBudget uint64 // gas budget of transactions without a gas budget, DefaultExecutorGasBudget when zero
+}
+
+// NewSerialExecutor creates a new SerialExecutor that executes transactions signed by the signer.
+func NewSerialExecutor(suiClient *client.SuiClient, signer cryptography.Signer) *SerialExecutor {
+	return &SerialExecutor{
+		client: suiClient,
+		signer: signer,
+		queue:  make(chan struct{}, 1),
+		cache:  newObjectCache(signer.ToSuiAddress()),
+	}
+}
+
+// ExecuteTransaction builds, signs and executes the transaction after the transactions queued before it. When an object version
+// is reported unavailable the cached objects are dropped, and the transaction is rebuilt and executed once more.
+func (e *SerialExecutor) ExecuteTransaction(ctx context.Context, tx *Transaction, options *types.SuiTransactionBlockResponseOptions) (*types.SuiTransactionBlockResponse, error) {
+	select {
+	case e.queue <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-e.queue }()
+
+	response, err := e.execute(ctx, tx, options)
+	if err != nil && isObjectVersionUnavailable(err) {
+		response, err = e.execute(ctx, tx, options)
+	}
+	return response, err
+}
+
+// ResetCache drops the cached objects and gas coin, they are fetched again by the next transaction. It is safe to call
+// while transactions are executed.
+func (e *SerialExecutor) ResetCache() {
+	e.cache.reset()
+}
+
+// execute builds and executes the transaction with the cached objects, the transaction is restored when it is not executed.
+func (e *SerialExecutor) execute(ctx context.Context, tx *Transaction, options *types.SuiTransactionBlockResponseOptions) (*types.SuiTransactionBlockResponse, error) {
+	restore := tx.snapshotForExecution()
+
+	if err := prepareForExecutor(tx, e.client, e.signer); err != nil {
+		return nil, err
+	}
+	tx.SetGasBudgetIfNotSet(executorGasBudget(e.GasBudget))
+	if gasCoin := e.cache.gas(); len(tx.GasConfig.Payment) == 0 && gasCoin != nil && !slices.Contains(tx.inputObjectIDs(), gasCoin.ObjectId.String()) {
+		tx.SetGasPayment([]*sui_types.ObjectRef{gasCoin})
+	}
+	e.cache.supply(tx)
+
+	_, bs, err := tx.Build(ctx, e.signer.ToSuiAddress())
+	if err != nil {
+		restore()
+		return nil, err
+	}
+	response, err := signAndExecute(ctx, e.client, e.signer, bs, options)
+	if err != nil {
+		restore()
+		e.ResetCache()
+		return nil, err
+	}
+
+	e.cache.applyEffects(response)
+	return response, executionError(response)
+}
+
+// snapshotForExecution returns a function that restores the builder state, gas configuration and supplied objects, so a
+// transaction that was not executed can be built again.
+func (txb *Transaction) snapshotForExecution() func() {
+	state, gasConfig, supplied := txb.snapshot(), *txb.GasConfig, maps.Clone(txb.suppliedObjects)
+	return func() {
+		txb.restore(state)
+		*txb.GasConfig = gasConfig
+		txb.suppliedObjects = supplied
+	}
+}
+
+// prepareForExecutor sets the client and sender of a transaction executed by an executor.
+func prepareForExecutor(tx *Transaction, suiClient *client.SuiClient, signer cryptography.Signer) error {
+	if tx.client == nil {
+		tx.client = suiClient
+	}
+	if tx.Sender != nil && tx.Sender.String() != utils.NormalizeSuiAddress(signer.ToSuiAddress()) {
+		return fmt.Errorf("transaction sender [%s] is not the signer [%s] of the executor", tx.Sender.String(), signer.ToSuiAddress())
+	}
+	tx.SetSenderIfNotSet(signer.ToSuiAddress())
+	return nil
+}
+
+// executorGasBudget returns the gas budget of an executor, DefaultExecutorGasBudget when it is not set.
+func executorGasBudget(budget uint64) uint64 {
+	if budget == 0 {
+		return DefaultExecutorGasBudget
+	}
+	return budget
+}
+
+// signAndExecute signs and executes the transaction bytes, the effects and object changes are always requested.
+func signAndExecute(ctx context.Context, suiClient *client.SuiClient, signer cryptography.Signer, bs []byte, options *types.SuiTransactionBlockResponseOptions) (*types.SuiTransactionBlockResponse, error) {
+	executeOptions := types.SuiTransactionBlockResponseOptions{ShowEffects: true, ShowObjectChanges: true}
+	if options != nil {
+		executeOptions.ShowInput, executeOptions.ShowEvents = options.ShowInput, options.ShowEvents
+		executeOptions.ShowBalanceChanges, executeOptions.ShowRawInput = options.ShowBalanceChanges, options.ShowRawInput
+	}
+	response, err := suiClient.SignAndExecuteTransactionBlock(ctx, types.SignAndExecuteTransactionBlockParams{TransactionBlock: bs, Signer: signer, Options: &executeOptions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute transaction, err: %w", err)
+	}
+	return response, nil
+}
+
+// executionError returns an error when the transaction was executed but failed.
+func executionError(response *types.SuiTransactionBlockResponse) error {
+	if err := ExecutionResponseError(response); err != nil {
+		return fmt.Errorf("transaction [%s] failed: %w", response.Digest, err)
+	}
+	return nil
+}
+
+// isObjectVersionUnavailable reports whether the error is caused by an input object whose version was already consumed.
+func isObjectVersionUnavailable(err error) bool {
+	var unavailable *ObjectVersionUnavailableError
+	return errors.As(ParseExecutionError(err.Error()), &unavailable)
+}
+
+// objectCache defines the objects owned by an address as observed in the effects of its transactions.
+type objectCache struct {
+	mutex   sync.Mutex
+	owner   string
+	objects map[string]*sui_types.ObjectRef // map key is normalized object id
+	gasCoin *sui_types.ObjectRef            // gas coin returned by the last transaction
+}
+
+func newObjectCache(owner string) *objectCache {
+	return &objectCache{owner: utils.NormalizeSuiAddress(owner), objects: make(map[string]*sui_types.ObjectRef)}
+}
+
+func (c *objectCache) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.objects = make(map[string]*sui_types.ObjectRef)
+	c.gasCoin = nil
+}
+
+// gas returns the gas coin returned by the last transaction, nil when it is unknown.
+func (c *objectCache) gas() *sui_types.ObjectRef {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.gasCoin
+}
+
+// invalidate drops the cached objects, they are fetched again by the next transaction using them.
+func (c *objectCache) invalidate(ids ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, id := range ids {
+		delete(c.objects, utils.NormalizeSuiObjectID(id))
+	}
+}
+
+// supply supplies the cached references of the objects referenced by ID, objects supplied by the caller are kept.
+func (c *objectCache) supply(tx *Transaction) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for id := range tx.unresolvedObjects {
+		if _, ok := tx.suppliedObjects[id]; ok {
+			continue
+		}
+		if ref, ok := c.objects[id]; ok {
+			tx.SupplyObjectRef(ref)
+		}
+	}
+}
+
+// applyEffects updates the cached objects from the effects and object changes of an executed transaction,
+// and keeps and returns the gas coin when it is still owned by the owner.
+func (c *objectCache) applyEffects(response *types.SuiTransactionBlockResponse) *sui_types.ObjectRef {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.gasCoin = nil
+	if effects := response.Effects; effects != nil {
+		for _, objects := range [][]types.OwnedObjectRef{effects.Created, effects.Mutated, effects.Unwrapped} {
+			for _, object := range objects {
+				c.update(object.Reference.ObjectID, strconv.FormatUint(object.Reference.Version, 10), object.Reference.Digest, &object.Owner)
+			}
+		}
+		for _, objects := range [][]types.SuiObjectRef{effects.Deleted, effects.Wrapped, effects.UnwrappedThenDeleted} {
+			for _, object := range objects {
+				delete(c.objects, utils.NormalizeSuiObjectID(object.ObjectID))
+			}
+		}
+		c.gasCoin = c.update(effects.GasObject.Reference.ObjectID, strconv.FormatUint(effects.GasObject.Reference.Version, 10), effects.GasObject.Reference.Digest, &effects.GasObject.Owner)
+	}
+
+	for _, change := range response.ObjectChanges {
+		if change == nil {
+			continue
+		}
+		switch change := change.SuiObjectChange.(type) {
+		case types.SuiObjectChangeCreated:
+			c.update(change.ObjectID, change.Version, change.Digest, change.Owner)
+		case types.SuiObjectChangeMutated:
+			c.update(change.ObjectID, change.Version, change.Digest, change.Owner)
+		case types.SuiObjectChangeTransferred:
+			c.update(change.ObjectID, change.Version, change.Digest, change.Recipient)
+		case types.SuiObjectChangeDeleted:
+			delete(c.objects, utils.NormalizeSuiObjectID(change.ObjectID))
+		case types.SuiObjectChangeWrapped:
+			delete(c.objects, utils.NormalizeSuiObjectID(change.ObjectID))
+		}
+	}
+	return c.gasCoin
+}
+
+// update caches the object when it is owned by the owner and drops it otherwise, it returns the cached reference.
+func (c *objectCache) update(id, version, digest string, owner *types.ObjectOwnerWrapper) *sui_types.ObjectRef {
+	id = utils.NormalizeSuiObjectID(id)
+	if owner == nil {
+		delete(c.objects, id)
+		return nil
+	}
+	addressOwner, ok := owner.ObjectOwner.(types.ObjectOwnerAddressOwner)
+	if !ok || utils.NormalizeSuiAddress(addressOwner.AddressOwner) != c.owner {
+		delete(c.objects, id)
+		return nil
+	}
+
+	ref, err := ObjectStringRef{ObjectID: id, Version: version, Digest: digest}.ToObjectRef()
+	if err != nil {
+		delete(c.objects, id)
+		return nil
+	}
+	c.objects[id] = ref
+	return ref
+}
@@ -0,0 +1,179 @@
+package transactions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/keypairs/ed25519"
+	"github.com/W3Tools/gosui/transactions"
+)
+
+// mockExecution executes transactions by bumping the versions of the given objects, which stay owned by the owner.
+// The first object is the gas coin, the executed transaction bytes are recorded.
+type mockExecution struct {
+	mutex    sync.Mutex
+	owner    string
+	versions map[string]uint64
+	executed [][]byte
+	failures []string // errors returned by the next executions
+}
+
+func (m *mockExecution) execute(objects ...string) func(params []json.RawMessage) (any, error) {
+	return func(params []json.RawMessage) (any, error) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		if len(m.failures) > 0 {
+			failure := m.failures[0]
+			m.failures = m.failures[1:]
+			return nil, fmt.Errorf("%s", failure)
+		}
+
+		var encoded string
+		if err := json.Unmarshal(params[0], &encoded); err != nil {
+			return nil, err
+		}
+		bs, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		m.executed = append(m.executed, bs)
+
+		owned := func(id string) map[string]any {
+			m.versions[id]++
+			return map[string]any{"owner": map[string]any{"AddressOwner": m.owner}, "reference": map[string]any{"objectId": id, "version": m.versions[id], "digest": zeroDigest}}
+		}
+		var mutated []any
+		for _, id := range objects[1:] {
+			mutated = append(mutated, owned(id))
+		}
+		gasObject := owned(objects[0])
+		effects := map[string]any{"status": map[string]any{"status": "success"}, "mutated": append(mutated, gasObject), "gasObject": gasObject}
+		return map[string]any{"digest": zeroDigest, "effects": effects}, nil
+	}
+}
+
+// containsRef reports whether the transaction bytes contain the object with the version.
+func containsRef(t *testing.T, bs []byte, id string, version uint64) bool {
+	objectID, err := sui_types.NewObjectIdFromHex(id)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+	return bytes.Contains(bs, binary.LittleEndian.AppendUint64(objectID[:], version))
+}
+
+func TestSerialExecutorReusesObjectsFromEffects(t *testing.T) {
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+	coins, object := newCoins(1000000000), objectID(0x36001)
+	execution := &mockExecution{owner: signer.ToSuiAddress(), versions: map[string]uint64{coins[0].CoinObjectID: 1, object: 1}}
+
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getCoins":               getCoins(coins),
+		"sui_multiGetObjects":         multiGetObjects(nil),
+		"sui_executeTransactionBlock": execution.execute(coins[0].CoinObjectID, object),
+	})
+	executor := transactions.NewSerialExecutor(suiClient, signer)
+
+	execute := func() error {
+		tx := transactions.NewTransaction(suiClient)
+		if err := tx.AddTransferObjects([]transactions.Arg{tx.Object(object)}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+			t.Fatalf("failed to add transfer objects: %v", err)
+		}
+		tx.SetGasPrice(1000)
+		_, err := executor.ExecuteTransaction(context.Background(), tx, nil)
+		return err
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := execute(); err != nil {
+			t.Fatalf("failed to execute transaction %d: %v", i, err)
+		}
+	}
+	if node.count("sui_multiGetObjects") != 1 || node.count("suix_getCoins") != 1 {
+		t.Errorf("expected objects and coins to be fetched once, but got %v", node.calls)
+	}
+	if bs := execution.executed[1]; !containsRef(t, bs, object, 2) || !containsRef(t, bs, coins[0].CoinObjectID, 2) {
+		t.Errorf("expected the second transaction to use the object and gas coin versions from the effects")
+	}
+
+	execution.failures = []string{"Transaction validator signing failed due to issues with transaction inputs: Object ID " + object + " is not available for consumption"}
+	if err := execute(); err != nil {
+		t.Fatalf("failed to execute transaction after retry: %v", err)
+	}
+	if node.count("sui_executeTransactionBlock") != 4 || node.count("sui_multiGetObjects") != 2 || node.count("suix_getCoins") != 2 {
+		t.Errorf("expected the transaction to be rebuilt with fetched objects and executed again, but got %v", node.calls)
+	}
+
+	execution.failures = []string{"ObjectVersionUnavailableForConsumption", "ObjectVersionUnavailableForConsumption"}
+	if err := execute(); err == nil || !strings.Contains(err.Error(), "ObjectVersionUnavailableForConsumption") {
+		t.Errorf("expected the transaction to be retried once, but got %v", err)
+	}
+	if got := node.count("sui_executeTransactionBlock"); got != 6 {
+		t.Errorf("expected 6 execute requests, but got %d", got)
+	}
+}
+
+func TestSerialExecutorResetCacheWhileExecuting(t *testing.T) {
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+	coins, object := newCoins(1000000000), objectID(0x36101)
+	execution := &mockExecution{owner: signer.ToSuiAddress(), versions: map[string]uint64{coins[0].CoinObjectID: 1, object: 1}}
+
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getCoins":               getCoins(coins),
+		"sui_multiGetObjects":         multiGetObjects(nil),
+		"sui_executeTransactionBlock": execution.execute(coins[0].CoinObjectID, object),
+	})
+	executor := transactions.NewSerialExecutor(suiClient, signer)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			tx := transactions.NewTransaction(suiClient)
+			if err := tx.AddTransferObjects([]transactions.Arg{tx.Object(object)}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+				t.Errorf("failed to add transfer objects: %v", err)
+				return
+			}
+			tx.SetGasPrice(1000)
+			if _, err := executor.ExecuteTransaction(context.Background(), tx, nil); err != nil {
+				t.Errorf("failed to execute transaction %d: %v", i, err)
+				return
+			}
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			executor.ResetCache()
+		}
+	}
+}
+
+func TestSerialExecutorRejectsOtherSenders(t *testing.T) {
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+
+	tx := transactions.NewTransaction(nil)
+	tx.SetSender(recipient)
+	if _, err := transactions.NewSerialExecutor(nil, signer).ExecuteTransaction(context.Background(), tx, nil); err == nil {
+		t.Errorf("expected an error for a transaction of another sender, but got nil")
+	}
+}
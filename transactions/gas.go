@@ -0,0 +1,267 @@
+package transactions
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// GasEstimate defines the gas used by a dry run of the transaction and the budget chosen from it.
+type GasEstimate struct {
+	Budget                  uint64
+	ComputationCost         uint64
+	StorageCost             uint64
+	StorageRebate           uint64
+	NonRefundableStorageFee uint64
+}
+
+// GasEstimator defines how the gas budget is chosen from the gas used by a dry run, Budget of the estimate is not set.
+type GasEstimator interface {
+	EstimateBudget(estimate GasEstimate, price uint64) (uint64, error)
+}
+
+// GasPolicy defines a GasEstimator that scales the dry run costs and adds an overhead to the computation cost.
+type GasPolicy struct {
+	ComputationMultiplier float64 // values below 1 are treated as 1
+	StorageMultiplier     float64 // values below 1 are treated as 1
+	OverheadUnits         uint64  // gas units added to the computation cost, charged at the gas price
+	Overhead              uint64  // MIST added to the computation cost
+	MaxBudget             uint64  // maximum budget, utils.MaxGas when zero
+}
+
+// NewGasPolicy returns the default gas policy, which adds utils.GasSafeOverhead gas units to the computation cost.
+func NewGasPolicy() *GasPolicy {
+	return &GasPolicy{ComputationMultiplier: 1, StorageMultiplier: 1, OverheadUnits: utils.GasSafeOverhead, MaxBudget: utils.MaxGas}
+}
+
+// EstimateBudget implements the GasEstimator interface. The budget covers the computation cost with overhead and the storage cost
+// minus the storage rebate, it is capped at MaxBudget and an error is returned when the dry run costs alone exceed MaxBudget.
+func (policy *GasPolicy) EstimateBudget(estimate GasEstimate, price uint64) (uint64, error) {
+	maxBudget := policy.MaxBudget
+	if maxBudget == 0 {
+		maxBudget = utils.MaxGas
+	}
+
+	computation := saturatingAdd(scale(estimate.ComputationCost, policy.ComputationMultiplier), saturatingAdd(saturatingMul(policy.OverheadUnits, price), policy.Overhead))
+	budget := computation
+	if cost := saturatingAdd(computation, scale(estimate.StorageCost, policy.StorageMultiplier)); cost > estimate.StorageRebate {
+		budget = max(computation, cost-estimate.StorageRebate)
+	}
+	if budget <= maxBudget {
+		return budget, nil
+	}
+
+	required := estimate.ComputationCost
+	if cost := saturatingAdd(estimate.ComputationCost, estimate.StorageCost); cost > estimate.StorageRebate {
+		required = max(required, cost-estimate.StorageRebate)
+	}
+	if required > maxBudget {
+		return 0, fmt.Errorf("gas used by the dry run %d exceeds the maximum budget %d", required, maxBudget)
+	}
+	return maxBudget, nil
+}
+
+// SetGasEstimator sets the estimator that chooses the gas budget when it is not set, the default is NewGasPolicy().
+func (txb *Transaction) SetGasEstimator(estimator GasEstimator) {
+	txb.gasEstimator = estimator
+}
+
+// GasEstimate returns the estimate of the gas budget chosen by the last Build, or nil when the budget was set.
+func (txb *Transaction) GasEstimate() *GasEstimate {
+	return txb.gasEstimate
+}
+
+// EstimateGas dry runs the transaction with its gas payment, or with the coins of the gas owner that can be used as
+// gas payment, and returns the gas used with the budget chosen by the gas estimator. The gas budget is not changed.
+func (txb *Transaction) EstimateGas(ctx context.Context) (*GasEstimate, error) {
+	if txb.Sender == nil {
+		return nil, fmt.Errorf("missing transaction sender")
+	}
+	if txb.client == nil {
+		return nil, fmt.Errorf("missing sui client to estimate gas")
+	}
+	if err := txb.prepare(ctx); err != nil {
+		return nil, fmt.Errorf("failed to resolve inputs, err: %w", err)
+	}
+	if err := setGasPrice(ctx, txb); err != nil {
+		return nil, fmt.Errorf("failed to set gas price, err: %v", err)
+	}
+
+	estimate, _, err := txb.estimateGas(ctx)
+	return estimate, err
+}
+
+// estimateGas dry runs the transaction and returns the gas estimate, with the candidate gas coins when the gas payment is not set.
+func (txb *Transaction) estimateGas(ctx context.Context) (*GasEstimate, []types.CoinStruct, error) {
+	payment, budget := txb.GasConfig.Payment, utils.MaxGas
+
+	var candidates []types.CoinStruct
+	if len(payment) == 0 {
+		coins, err := SelectCoins(ctx, txb.client, txb.gasOwner(), utils.SuiTypeArg, 0, &CoinSelectionOptions{Strategy: AllCoins, Exclude: txb.inputObjectIDs()})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to select gas coins for dry run, err: %w", err)
+		}
+
+		var balance uint64
+		for _, coin := range coins {
+			value, err := strconv.ParseUint(coin.Balance, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid balance [%s] of coin [%s]: %v", coin.Balance, coin.CoinObjectID, err)
+			}
+			balance = saturatingAdd(balance, value)
+
+			ref, err := coinStructToObjectRef(coin)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to create object reference, err: %v", err)
+			}
+			payment = append(payment, ref)
+		}
+		// the amounts split from the gas coin are not available to pay the gas
+		split := txb.gasCoinSplitAmount()
+		if split >= balance {
+			return nil, nil, &InsufficientBalanceError{Owner: txb.gasOwner(), CoinType: utils.SuiTypeArg, Required: split, Available: balance}
+		}
+		budget, candidates = min(budget, balance-split), coins
+	}
+
+	tx, err := txb.transactionData(payment, budget, txb.GasConfig.Price)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can not create transaction data, err: %v", err)
+	}
+	bs, err := txb.marshalTransactionData(tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can not marshal transaction, err: %v", err)
+	}
+	dryRunResult, err := txb.client.DryRunTransactionBlock(ctx, types.DryRunTransactionBlockParams{TransactionBlock: bs})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to dry run transaction block, err: %v", err)
+	}
+	if err := DryRunError(dryRunResult); err != nil {
+		return nil, nil, fmt.Errorf("dry run failed, could not automatically determine a budget: %w", err)
+	}
+
+	estimate, err := parseGasCostSummary(dryRunResult.Effects.GasUsed)
+	if err != nil {
+		return nil, nil, err
+	}
+	estimator := txb.gasEstimator
+	if estimator == nil {
+		estimator = NewGasPolicy()
+	}
+	if estimate.Budget, err = estimator.EstimateBudget(*estimate, txb.GasConfig.Price); err != nil {
+		return nil, nil, fmt.Errorf("failed to estimate gas budget, err: %v", err)
+	}
+	return estimate, candidates, nil
+}
+
+// setGasBudget estimates the gas budget when it is not set, the gas payment is selected from the coins used by the dry run
+// for the budget and the amounts split from the gas coin.
+func setGasBudget(ctx context.Context, txb *Transaction) error {
+	txb.gasEstimate = nil
+	if txb.GasConfig.Budget != 0 {
+		return nil
+	}
+
+	estimate, candidates, err := txb.estimateGas(ctx)
+	if err != nil {
+		return err
+	}
+	txb.GasConfig.Budget, txb.gasEstimate = estimate.Budget, estimate
+
+	if len(candidates) > 0 {
+		coins, err := selectCoins(candidates, saturatingAdd(estimate.Budget, txb.gasCoinSplitAmount()), &CoinSelectionOptions{Strategy: txb.coinSelectionStrategy})
+		if err != nil {
+			if insufficient, ok := err.(*InsufficientBalanceError); ok {
+				insufficient.Owner, insufficient.CoinType = txb.gasOwner(), utils.SuiTypeArg
+			}
+			return fmt.Errorf("failed to select gas coins, err: %w", err)
+		}
+
+		payment := make([]*sui_types.ObjectRef, len(coins))
+		for idx, coin := range coins {
+			if payment[idx], err = coinStructToObjectRef(coin); err != nil {
+				return fmt.Errorf("failed to create object reference, err: %v", err)
+			}
+		}
+		txb.GasConfig.Payment = payment
+	}
+
+	return nil
+}
+
+// gasCoinSplitAmount returns the total of the pure amounts split from the gas coin, which the gas payment must cover with the
+// gas budget. Amounts that are results of other commands are unknown before execution and are not counted.
+func (txb *Transaction) gasCoinSplitAmount() uint64 {
+	var total uint64
+	for _, command := range txb.builder.Commands {
+		if command.SplitCoins == nil || command.SplitCoins.Argument.GasCoin == nil {
+			continue
+		}
+		for _, argument := range command.SplitCoins.Arguments {
+			if argument.Input == nil || int(*argument.Input) >= len(txb.builder.InputsKeyOrder) {
+				continue
+			}
+			input := txb.builder.Inputs[txb.builder.InputsKeyOrder[*argument.Input].String()]
+			if input.Pure != nil && len(*input.Pure) == 8 {
+				total = saturatingAdd(total, binary.LittleEndian.Uint64(*input.Pure))
+			}
+		}
+	}
+	return total
+}
+
+// gasOwner returns the owner of the gas coins, which is the sender unless the transaction is sponsored.
+func (txb *Transaction) gasOwner() string {
+	if txb.GasConfig.Owner != "" {
+		return txb.GasConfig.Owner
+	}
+	return txb.Sender.String()
+}
+
+// parseGasCostSummary parses the gas used by a transaction.
+func parseGasCostSummary(summary types.GasCostSummary) (*GasEstimate, error) {
+	estimate := new(GasEstimate)
+	for _, field := range []struct {
+		name   string
+		value  string
+		target *uint64
+	}{
+		{"computation cost", summary.ComputationCost, &estimate.ComputationCost},
+		{"storage cost", summary.StorageCost, &estimate.StorageCost},
+		{"storage rebate", summary.StorageRebate, &estimate.StorageRebate},
+		{"non-refundable storage fee", summary.NonRefundableStorageFee, &estimate.NonRefundableStorageFee},
+	} {
+		value, err := strconv.ParseUint(field.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s, err: %v", field.name, err)
+		}
+		*field.target = value
+	}
+	return estimate, nil
+}
+
+// scale returns value multiplied by multiplier rounded up, multipliers below 1 are treated as 1.
+func scale(value uint64, multiplier float64) uint64 {
+	if multiplier <= 1 {
+		return value
+	}
+	scaled := math.Ceil(float64(value) * multiplier)
+	if scaled >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(scaled)
+}
+
+// saturatingMul returns a * b, or math.MaxUint64 when the product overflows.
+func saturatingMul(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+	return a * b
+}
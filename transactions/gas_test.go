@@ -0,0 +1,204 @@
+package transactions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// dryRunGasUsed serves a successful dry run with the gas used and records the budget, price and gas coins of the transaction.
+func dryRunGasUsed(t *testing.T, gasUsed types.GasCostSummary, budgets, prices *[]uint64, coinIDs []string, payments *[][]string) func(params []json.RawMessage) (any, error) {
+	return func(params []json.RawMessage) (any, error) {
+		var encoded string
+		if err := json.Unmarshal(params[0], &encoded); err != nil {
+			return nil, err
+		}
+		bs, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) < 17 {
+			return nil, fmt.Errorf("invalid transaction data")
+		}
+
+		// the transaction data ends with the gas price, the gas budget and TransactionExpiration::None
+		*prices = append(*prices, binary.LittleEndian.Uint64(bs[len(bs)-17:len(bs)-9]))
+		*budgets = append(*budgets, binary.LittleEndian.Uint64(bs[len(bs)-9:len(bs)-1]))
+
+		var payment []string
+		for _, id := range coinIDs {
+			objectID, err := sui_types.NewObjectIdFromHex(id)
+			if err != nil {
+				t.Fatalf("failed to parse object id: %v", err)
+			}
+			if bytes.Contains(bs, objectID[:]) {
+				payment = append(payment, id)
+			}
+		}
+		*payments = append(*payments, payment)
+
+		return map[string]any{"effects": map[string]any{"status": map[string]any{"status": "success"}, "gasUsed": gasUsed}}, nil
+	}
+}
+
+func TestGasPolicyEstimateBudget(t *testing.T) {
+	used := transactions.GasEstimate{ComputationCost: 1000000, StorageCost: 2000000, StorageRebate: 500000}
+
+	tests := []struct {
+		name     string
+		policy   *transactions.GasPolicy
+		estimate transactions.GasEstimate
+		price    uint64
+		expected uint64
+		err      bool
+	}{
+		{"default policy", transactions.NewGasPolicy(), used, 1000, 3500000, false},
+		{"multipliers", &transactions.GasPolicy{ComputationMultiplier: 1.5, StorageMultiplier: 2, Overhead: 100}, used, 1000, 5000100, false},
+		{"multipliers below one", &transactions.GasPolicy{ComputationMultiplier: 0.5, StorageMultiplier: 0.1}, used, 1000, 2500000, false},
+		{"rebate exceeds cost", transactions.NewGasPolicy(), transactions.GasEstimate{ComputationCost: 1000000, StorageCost: 100, StorageRebate: 5000000}, 1000, 2000000, false},
+		{"budget capped", &transactions.GasPolicy{OverheadUnits: 1000000, MaxBudget: 4000000}, used, 1000, 4000000, false},
+		{"dry run exceeds cap", &transactions.GasPolicy{MaxBudget: 2000000}, used, 1000, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, err := tt.policy.EstimateBudget(tt.estimate, tt.price)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, but got %v", tt.err, err)
+			}
+			if budget != tt.expected {
+				t.Errorf("expected budget %d, but got %d", tt.expected, budget)
+			}
+		})
+	}
+}
+
+type fixedEstimator uint64
+
+func (budget fixedEstimator) EstimateBudget(transactions.GasEstimate, uint64) (uint64, error) {
+	return uint64(budget), nil
+}
+
+func TestBuildDryRunsWithGasPayment(t *testing.T) {
+	coins := newCoins(5000000, 100, 3000000)
+	coinIDs := []string{coins[0].CoinObjectID, coins[1].CoinObjectID, coins[2].CoinObjectID}
+	gasUsed := types.GasCostSummary{ComputationCost: "1000000", StorageCost: "2000000", StorageRebate: "500000", NonRefundableStorageFee: "5000"}
+
+	var budgets, prices []uint64
+	var payments [][]string
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getCoins":              getCoins(coins),
+		"sui_dryRunTransactionBlock": dryRunGasUsed(t, gasUsed, &budgets, &prices, coinIDs, &payments),
+	})
+
+	tx := transactions.NewTransaction(suiClient)
+	if err := tx.AddTransferObjects([]transactions.Arg{tx.Gas()}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	tx.SetGasPrice(1000)
+
+	data, _, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	if !reflect.DeepEqual([]uint64{8000100}, budgets) || !reflect.DeepEqual([]uint64{1000}, prices) {
+		t.Errorf("expected a dry run with budget 8000100 and price 1000, but got budgets %v and prices %v", budgets, prices)
+	}
+	if len(payments) != 1 || len(payments[0]) != len(coins) {
+		t.Errorf("expected a dry run with all gas coins, but got %v", payments)
+	}
+
+	expected := &transactions.GasEstimate{Budget: 3500000, ComputationCost: 1000000, StorageCost: 2000000, StorageRebate: 500000, NonRefundableStorageFee: 5000}
+	if !reflect.DeepEqual(expected, tx.GasEstimate()) {
+		t.Errorf("expected gas estimate %+v, but got %+v", expected, tx.GasEstimate())
+	}
+	if data.V1.GasData.Budget != 3500000 {
+		t.Errorf("expected gas budget 3500000, but got %d", data.V1.GasData.Budget)
+	}
+	if payment := data.V1.GasData.Payment; len(payment) != 1 || payment[0].ObjectId.String() != coins[0].CoinObjectID {
+		t.Errorf("expected gas payment with coin %s, but got %v", coins[0].CoinObjectID, payment)
+	}
+
+	tx.SetGasBudget(0)
+	tx.SetGasEstimator(fixedEstimator(utils.MaxGas))
+	estimate, err := tx.EstimateGas(context.Background())
+	if err != nil {
+		t.Fatalf("failed to estimate gas: %v", err)
+	}
+	if estimate.Budget != utils.MaxGas || tx.GasConfig.Budget != 0 {
+		t.Errorf("expected estimated budget %d with the gas budget unchanged, but got %d and %d", utils.MaxGas, estimate.Budget, tx.GasConfig.Budget)
+	}
+	if len(budgets) != 2 || budgets[1] != utils.MaxGas || !reflect.DeepEqual([]string{coins[0].CoinObjectID}, payments[1]) {
+		t.Errorf("expected a dry run with the gas payment and budget %d, but got budgets %v and payments %v", utils.MaxGas, budgets, payments)
+	}
+}
+
+func TestGasPaymentCoversGasCoinSplits(t *testing.T) {
+	coins := newCoins(5000000, 100, 3000000)
+	coinIDs := []string{coins[0].CoinObjectID, coins[1].CoinObjectID, coins[2].CoinObjectID}
+	gasUsed := types.GasCostSummary{ComputationCost: "1000000", StorageCost: "2000000", StorageRebate: "500000", NonRefundableStorageFee: "5000"}
+
+	tests := []struct {
+		name    string
+		budget  uint64
+		amount  uint64
+		dryRuns []uint64 // budgets of the dry runs
+		payment []string
+	}{
+		{name: "gas budget set", budget: 1000000, amount: 4500000, payment: []string{coinIDs[0], coinIDs[2]}},
+		{name: "gas budget estimated", amount: 2000000, dryRuns: []uint64{6000100}, payment: []string{coinIDs[0], coinIDs[2]}},
+		{name: "split amount exceeds balance", amount: 9000000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var budgets, prices []uint64
+			var payments [][]string
+			suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+				"suix_getCoins":              getCoins(coins),
+				"sui_dryRunTransactionBlock": dryRunGasUsed(t, gasUsed, &budgets, &prices, coinIDs, &payments),
+			})
+
+			tx := transactions.NewTransaction(suiClient)
+			if err := tx.PaySui([]string{recipient}, []uint64{tt.amount}); err != nil {
+				t.Fatalf("failed to pay sui: %v", err)
+			}
+			tx.SetGasPrice(1000)
+			tx.SetGasBudget(tt.budget)
+
+			data, _, err := tx.Build(context.Background(), recipient)
+			if tt.payment == nil {
+				var insufficient *transactions.InsufficientBalanceError
+				if !errors.As(err, &insufficient) || insufficient.Required != tt.amount {
+					t.Errorf("expected an insufficient balance error for %d, but got %v", tt.amount, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to build transaction: %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.dryRuns, budgets) {
+				t.Errorf("expected dry runs with budgets %v, but got %v", tt.dryRuns, budgets)
+			}
+			var payment []string
+			for _, ref := range data.V1.GasData.Payment {
+				payment = append(payment, ref.ObjectId.String())
+			}
+			if !reflect.DeepEqual(tt.payment, payment) {
+				t.Errorf("expected gas payment %v, but got %v", tt.payment, payment)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// coinIntent defines a coin with a balance that is selected from the coins of the sender when the transaction is built.
+type coinIntent struct {
+	CoinType   string
+	Amount     uint64
+	UseGasCoin bool // SUI is split from the gas coin unless the gas is owned by a sponsor
+}
+
+// CoinWithBalance returns a coin of coinType with the exact balance, which can be used as an argument right away.
+// SUI is split from the gas coin, other coin types are selected from the coins of the sender and merged when the transaction is built,
+// with one coin lookup per coin type for all intents. Build expands the intents into commands, which shifts the indexes of later commands,
+// so results returned before Build must not be used to add commands after Build. When the gas is owned by a sponsor, SUI is selected
+// from the coins of the sender as well; use CoinWithBalanceFromSender for transaction kinds that are sent to a sponsor.
+func (txb *Transaction) CoinWithBalance(coinType string, amount uint64) (Result, error) {
+	return txb.coinWithBalance(coinType, amount, true)
+}
+
+// CoinWithBalanceFromSender returns a coin of coinType with the exact balance like CoinWithBalance, but SUI is never split from
+// the gas coin, it is selected from the coins of the sender.
+func (txb *Transaction) CoinWithBalanceFromSender(coinType string, amount uint64) (Result, error) {
+	return txb.coinWithBalance(coinType, amount, false)
+}
+
+// coinWithBalance adds a coin intent, useGasCoin only applies to SUI.
+func (txb *Transaction) coinWithBalance(coinType string, amount uint64, useGasCoin bool) (result Result, err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	coinType = utils.NormalizeSuiCoinType(coinType)
+	if _, err := txb.resolveFunctionTypeArguments([]string{coinType}); err != nil {
+		return Result{}, fmt.Errorf("invalid coin type [%s] in command %d: %v", coinType, len(txb.builder.Commands), err)
+	}
+
+	// the placeholder keeps the indexes of later commands until the intent is expanded
+	result = txb.command(sui_types.Command{MakeMoveVec: &struct {
+		TypeTag   *move_types.TypeTag `bcs:"optional"`
+		Arguments []sui_types.Argument
+	}{}})
+	txb.intents[result.Index] = coinIntent{CoinType: coinType, Amount: amount, UseGasCoin: useGasCoin && coinType == utils.SuiTypeArg}
+	return result, nil
+}
+
+// splitsFromGasCoin reports whether the coin of an intent is split from the gas coin, which is not done when the gas is owned by
+// a sponsor, so the sponsor does not pay the coins of the sender.
+func (txb *Transaction) splitsFromGasCoin(intent coinIntent) bool {
+	if !intent.UseGasCoin || txb.GasConfig.Owner == "" {
+		return intent.UseGasCoin
+	}
+	owner, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(txb.GasConfig.Owner))
+	return err == nil && txb.Sender != nil && *owner == *txb.Sender
+}
+
+// resolveIntents selects the coins of all coin intents and replaces each placeholder with the commands that create the coin.
+// The builder may be partially changed when an error is returned, it is restored by prepare.
+func (txb *Transaction) resolveIntents(ctx context.Context) error {
+	_, err := txb.resolveIntentsExcluding(ctx, nil)
+	return err
+}
+
+// resolveIntentsExcluding resolves the coin intents without selecting the excluded coins, and returns the index of each
+// command before the intents were expanded mapped to its index afterwards, nil when there are no intents.
+func (txb *Transaction) resolveIntentsExcluding(ctx context.Context, excluded []string) (mapping []uint16, err error) {
+	if len(txb.intents) == 0 {
+		return nil, nil
+	}
+
+	indexes := make([]uint16, 0, len(txb.intents))
+	totals := make(map[string]uint64)
+	for index, intent := range txb.intents {
+		indexes = append(indexes, index)
+		if txb.splitsFromGasCoin(intent) {
+			continue
+		}
+		if totals[intent.CoinType] > math.MaxUint64-intent.Amount {
+			return nil, fmt.Errorf("total amount of [%s] overflows u64", intent.CoinType)
+		}
+		totals[intent.CoinType] += intent.Amount
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	coins := make(map[string][]*sui_types.ObjectRef, len(totals))
+	if len(totals) > 0 && txb.Sender == nil {
+		return nil, fmt.Errorf("missing transaction sender to select coins")
+	}
+	exclude := append(txb.inputObjectIDs(), excluded...)
+	for _, index := range indexes {
+		coinType := txb.intents[index].CoinType
+		if _, ok := totals[coinType]; !ok || coins[coinType] != nil {
+			continue
+		}
+
+		selected, err := SelectCoins(ctx, txb.client, txb.Sender.String(), coinType, totals[coinType], &CoinSelectionOptions{Strategy: txb.coinSelectionStrategy, Exclude: exclude})
+		if err != nil {
+			return nil, fmt.Errorf("failed to select coins of [%s] for command %d, err: %w", coinType, index, err)
+		}
+		for _, coin := range selected {
+			ref, err := coinStructToObjectRef(coin)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create object reference, err: %v", err)
+			}
+			coins[coinType] = append(coins[coinType], ref)
+		}
+	}
+
+	commands := txb.builder.Commands
+	txb.builder.Commands = make([]sui_types.Command, 0, len(commands)+len(indexes))
+	mapping = make([]uint16, len(commands))
+	sources := make(map[string]sui_types.Argument, len(coins))
+	for index, command := range commands {
+		intent, ok := txb.intents[uint16(index)]
+		if !ok {
+			txb.builder.Commands = append(txb.builder.Commands, remapCommand(command, func(argument sui_types.Argument) sui_types.Argument {
+				return remapArgument(argument, mapping, txb.intents)
+			}))
+			mapping[index] = uint16(len(txb.builder.Commands) - 1)
+			continue
+		}
+
+		source, ok := sources[intent.CoinType]
+		if txb.splitsFromGasCoin(intent) {
+			source = sui_types.Argument{GasCoin: &lib.EmptyEnum{}}
+		} else if !ok {
+			if source, err = txb.mergeIntentCoins(coins[intent.CoinType]); err != nil {
+				return nil, fmt.Errorf("can not merge coins of [%s] for command %d: %v", intent.CoinType, index, err)
+			}
+			sources[intent.CoinType] = source
+		}
+
+		amount, err := txb.builder.Pure(intent.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("can not encode amount for command %d: %v", index, err)
+		}
+		txb.builder.Commands = append(txb.builder.Commands, sui_types.Command{SplitCoins: &struct {
+			Argument  sui_types.Argument
+			Arguments []sui_types.Argument
+		}{Argument: source, Arguments: []sui_types.Argument{amount}}})
+		mapping[index] = uint16(len(txb.builder.Commands) - 1)
+	}
+
+	txb.intents = make(map[uint16]coinIntent)
+	return mapping, nil
+}
+
+// mergeIntentCoins adds the selected coins as inputs and merges them into the first coin.
+func (txb *Transaction) mergeIntentCoins(refs []*sui_types.ObjectRef) (sui_types.Argument, error) {
+	arguments := make([]sui_types.Argument, len(refs))
+	for idx, ref := range refs {
+		argument, err := txb.objectInput(sui_types.ObjectArg{ImmOrOwnedObject: ref})
+		if err != nil {
+			return sui_types.Argument{}, err
+		}
+		arguments[idx] = argument
+	}
+	if len(arguments) > 1 {
+		txb.builder.Commands = append(txb.builder.Commands, sui_types.Command{MergeCoins: &struct {
+			Argument  sui_types.Argument
+			Arguments []sui_types.Argument
+		}{Argument: arguments[0], Arguments: arguments[1:]}})
+	}
+	return arguments[0], nil
+}
+
+// remapArgument returns the argument with the result index of the expanded commands, the result of an intent is the split coin.
+func remapArgument(argument sui_types.Argument, mapping []uint16, intents map[uint16]coinIntent) sui_types.Argument {
+	switch {
+	case argument.Result != nil:
+		index := mapping[*argument.Result]
+		if _, ok := intents[*argument.Result]; ok {
+			return sui_types.Argument{NestedResult: &struct {
+				Result1 uint16
+				Result2 uint16
+			}{Result1: index, Result2: 0}}
+		}
+		return sui_types.Argument{Result: &index}
+	case argument.NestedResult != nil:
+		return sui_types.Argument{NestedResult: &struct {
+			Result1 uint16
+			Result2 uint16
+		}{Result1: mapping[argument.NestedResult.Result1], Result2: argument.NestedResult.Result2}}
+	default:
+		return argument
+	}
+}
+
+// remapCommand returns a copy of the command with every argument replaced by fn.
+func remapCommand(command sui_types.Command, fn func(sui_types.Argument) sui_types.Argument) sui_types.Command {
+	remap := func(arguments []sui_types.Argument) []sui_types.Argument {
+		remapped := make([]sui_types.Argument, len(arguments))
+		for idx, argument := range arguments {
+			remapped[idx] = fn(argument)
+		}
+		return remapped
+	}
+
+	switch {
+	case command.MoveCall != nil:
+		moveCall := *command.MoveCall
+		moveCall.Arguments = remap(moveCall.Arguments)
+		return sui_types.Command{MoveCall: &moveCall}
+	case command.TransferObjects != nil:
+		transferObjects := *command.TransferObjects
+		transferObjects.Arguments, transferObjects.Argument = remap(transferObjects.Arguments), fn(transferObjects.Argument)
+		return sui_types.Command{TransferObjects: &transferObjects}
+	case command.SplitCoins != nil:
+		splitCoins := *command.SplitCoins
+		splitCoins.Argument, splitCoins.Arguments = fn(splitCoins.Argument), remap(splitCoins.Arguments)
+		return sui_types.Command{SplitCoins: &splitCoins}
+	case command.MergeCoins != nil:
+		mergeCoins := *command.MergeCoins
+		mergeCoins.Argument, mergeCoins.Arguments = fn(mergeCoins.Argument), remap(mergeCoins.Arguments)
+		return sui_types.Command{MergeCoins: &mergeCoins}
+	case command.MakeMoveVec != nil:
+		makeMoveVec := *command.MakeMoveVec
+		makeMoveVec.Arguments = remap(makeMoveVec.Arguments)
+		return sui_types.Command{MakeMoveVec: &makeMoveVec}
+	case command.Upgrade != nil:
+		upgrade := *command.Upgrade
+		upgrade.Argument = fn(upgrade.Argument)
+		return sui_types.Command{Upgrade: &upgrade}
+	default:
+		return command
+	}
+}
+
+// prepare expands the coin intents, resolves the pure values and objects of the transaction and checks the commands with the cached
+// function signatures, the transaction is unchanged when an error is returned.
+func (txb *Transaction) prepare(ctx context.Context) (err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	if err := txb.resolveIntents(ctx); err != nil {
+		return fmt.Errorf("can not resolve coin intents: %w", err)
+	}
+	if err := txb.injectSystemObjects(ctx); err != nil {
+		return err
+	}
+	if err := txb.resolvePureValues(ctx); err != nil {
+		return err
+	}
+	if err := txb.resolveInputs(ctx); err != nil {
+		return err
+	}
+	return txb.checkTypes(DefaultTypeRegistry())
+}
@@ -0,0 +1,193 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+)
+
+const usdc = "0x0000000000000000000000000000000000000000000000000000000000031000::usdc::USDC"
+
+// getCoinsByType serves the coins of the requested coin type.
+func getCoinsByType(coins map[string][]types.CoinStruct) func(params []json.RawMessage) (any, error) {
+	return func(params []json.RawMessage) (any, error) {
+		var coinType string
+		if err := json.Unmarshal(params[1], &coinType); err != nil {
+			return nil, err
+		}
+		return getCoins(coins[coinType])(params)
+	}
+}
+
+func newTypedCoins(coinType string, balances ...uint64) []types.CoinStruct {
+	coins := newCoins(balances...)
+	for idx := range coins {
+		coins[idx].CoinType = coinType
+		coins[idx].CoinObjectID = objectID(0x31000 + idx + 1)
+	}
+	return coins
+}
+
+func nestedResult(argument sui_types.Argument) (uint16, uint16, bool) {
+	if argument.NestedResult == nil {
+		return 0, 0, false
+	}
+	return argument.NestedResult.Result1, argument.NestedResult.Result2, true
+}
+
+func TestCoinWithBalanceSplitsSuiFromGas(t *testing.T) {
+	tx := transactions.NewTransaction(nil)
+	first, err := tx.CoinWithBalance("0x2::sui::SUI", 100)
+	if err != nil {
+		t.Fatalf("failed to add coin with balance: %v", err)
+	}
+	second, err := tx.CoinWithBalance("0x2::sui::SUI", 200)
+	if err != nil {
+		t.Fatalf("failed to add coin with balance: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{first, second}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+
+	if _, err := tx.BuildKind(context.Background()); err != nil {
+		t.Fatalf("failed to build transaction kind: %v", err)
+	}
+
+	commands := tx.TransactionBuilder().Commands
+	if len(commands) != 3 {
+		t.Fatalf("expected 3 commands, but got %d", len(commands))
+	}
+	for _, command := range commands[:2] {
+		if command.SplitCoins == nil || command.SplitCoins.Argument.GasCoin == nil {
+			t.Errorf("expected split coins from gas, but got %+v", command)
+		}
+	}
+	for idx, argument := range commands[2].TransferObjects.Arguments {
+		if index, nested, ok := nestedResult(argument); !ok || index != uint16(idx) || nested != 0 {
+			t.Errorf("expected nested result (%d, 0), but got %+v", idx, argument)
+		}
+	}
+}
+
+func TestCoinWithBalanceMergesCoinsOfSender(t *testing.T) {
+	coins := newTypedCoins(usdc, 30, 50, 40)
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getCoins": getCoinsByType(map[string][]types.CoinStruct{usdc: coins}),
+		// deposit(vector<Coin<USDC>>)
+		"sui_getNormalizedMoveModulesByPackage": func([]json.RawMessage) (any, error) {
+			coinType := `{"Struct": {"address": "0x2", "module": "coin", "name": "Coin", "typeArguments": [{"Struct": {"address": "0x31000", "module": "usdc", "name": "USDC", "typeArguments": []}}]}}`
+			return json.RawMessage(`{"vault": {"fileFormatVersion": 6, "address": "0x31100", "name": "vault", "friends": [], "structs": {},
+				"exposedFunctions": {"deposit": {"visibility": "Public", "isEntry": true, "typeParameters": [], "parameters": [{"Vector": ` + coinType + `}], "return": []}}}}`), nil
+		},
+	})
+
+	tx := transactions.NewTransaction(suiClient)
+	first, err := tx.CoinWithBalance("0x31000::usdc::USDC", 60)
+	if err != nil {
+		t.Fatalf("failed to add coin with balance: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{first}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	second, err := tx.CoinWithBalance(usdc, 20)
+	if err != nil {
+		t.Fatalf("failed to add coin with balance: %v", err)
+	}
+	vec, err := tx.AddMakeMoveVec("", []transactions.Arg{second})
+	if err != nil {
+		t.Fatalf("failed to add make move vec: %v", err)
+	}
+	if _, err := tx.AddMoveCall(objectID(0x31100)+"::vault::deposit", []transactions.Arg{vec}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+
+	tx.SetGasPrice(1000)
+	tx.SetGasBudget(1000000)
+	tx.SetGasPayment([]*sui_types.ObjectRef{{Digest: make([]byte, 32)}})
+	data, _, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	if got := node.count("suix_getCoins"); got != 1 {
+		t.Errorf("expected 1 get coins request, but got %d", got)
+	}
+
+	// merge, split, transfer, split, make move vec, move call
+	commands := data.V1.Kind.ProgrammableTransaction.Commands
+	if len(commands) != 6 {
+		t.Fatalf("expected 6 commands, but got %d", len(commands))
+	}
+	merge := commands[0].MergeCoins
+	if merge == nil || merge.Argument.Input == nil || len(merge.Arguments) != 1 {
+		t.Fatalf("expected merge of two coins, but got %+v", commands[0])
+	}
+	inputs := data.V1.Kind.ProgrammableTransaction.Inputs
+	if ref := inputs[*merge.Argument.Input].Object.ImmOrOwnedObject; ref == nil || ref.ObjectId.String() != coins[1].CoinObjectID {
+		t.Errorf("expected merge into coin %s, but got %+v", coins[1].CoinObjectID, inputs[*merge.Argument.Input])
+	}
+	for _, idx := range []int{1, 3} {
+		split := commands[idx].SplitCoins
+		if split == nil || split.Argument.Input == nil || *split.Argument.Input != *merge.Argument.Input {
+			t.Errorf("expected split from the merged coin in command %d, but got %+v", idx, commands[idx])
+		}
+	}
+	if index, _, ok := nestedResult(commands[2].TransferObjects.Arguments[0]); !ok || index != 1 {
+		t.Errorf("expected transfer of the first split coin, but got %+v", commands[2].TransferObjects.Arguments[0])
+	}
+	if index, _, ok := nestedResult(commands[4].MakeMoveVec.Arguments[0]); !ok || index != 3 {
+		t.Errorf("expected make move vec of the second split coin, but got %+v", commands[4].MakeMoveVec.Arguments[0])
+	}
+	if argument := commands[5].MoveCall.Arguments[0]; argument.Result == nil || *argument.Result != 4 {
+		t.Errorf("expected move call with the result of command 4, but got %+v", argument)
+	}
+}
+
+func TestCoinWithBalanceFailureKeepsTransaction(t *testing.T) {
+	coins := map[string][]types.CoinStruct{usdc: newTypedCoins(usdc, 30)}
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){"suix_getCoins": getCoinsByType(coins)})
+
+	tx := transactions.NewTransaction(suiClient)
+	coin, err := tx.CoinWithBalance(usdc, 50)
+	if err != nil {
+		t.Fatalf("failed to add coin with balance: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{coin}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	if _, err := tx.BuildKind(context.Background()); err == nil {
+		t.Fatalf("expected a missing sender error, but got nil")
+	}
+
+	tx.SetSender(recipient)
+	_, err = tx.BuildKind(context.Background())
+	var insufficient *transactions.InsufficientBalanceError
+	if !errors.As(err, &insufficient) || insufficient.Shortfall() != 20 {
+		t.Fatalf("expected an insufficient balance error with shortfall 20, but got %v", err)
+	}
+	if builder := tx.TransactionBuilder(); len(builder.Commands) != 2 || len(builder.InputsKeyOrder) != 1 {
+		t.Errorf("expected unchanged transaction, but got %d commands and %d inputs", len(builder.Commands), len(builder.InputsKeyOrder))
+	}
+
+	coins[usdc] = newTypedCoins(usdc, 30, 20)
+	if _, err := tx.BuildKind(context.Background()); err != nil {
+		t.Fatalf("failed to build transaction kind: %v", err)
+	}
+	if commands := tx.TransactionBuilder().Commands; len(commands) != 3 || commands[0].MergeCoins == nil || commands[1].SplitCoins == nil {
+		t.Errorf("expected merge, split and transfer commands, but got %+v", commands)
+	}
+}
+
+func TestCoinWithBalanceInvalidCoinType(t *testing.T) {
+	tx := transactions.NewTransaction(nil)
+	if _, err := tx.CoinWithBalance("usdc", 1); err == nil {
+		t.Errorf("expected an invalid coin type error, but got nil")
+	}
+	if commands := tx.TransactionBuilder().Commands; len(commands) != 0 {
+		t.Errorf("expected no commands, but got %d", len(commands))
+	}
+}
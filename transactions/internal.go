@@ -2,8 +2,9 @@ package transactions
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"reflect"
+	"maps"
 	"strconv"
 	"strings"
 
@@ -34,6 +35,72 @@ func (txb *Transaction) createTransactionResult(count int) []*sui_types.Argument
 	return returnArguments
 }
 
+// command appends a command to the transaction and returns its result.
+func (txb *Transaction) command(command sui_types.Command) Result {
+	result := txb.builder.Command(command)
+	return Result{Index: *result.Result}
+}
+
+// builderState defines a snapshot of the inputs and commands of a transaction.
+type builderState struct {
+	inputs            map[string]sui_types.CallArg
+	inputsKeyOrder    []sui_types.BuilderArg
+	commands          []sui_types.Command
+	unresolvedObjects map[string]UnresolvedObject
+	intents           map[uint16]coinIntent
+	receivingObjects  map[string]bool
+	unresolvedPures   map[uint16]json.RawMessage
+}
+
+// snapshot returns the current builder state, inputs and commands are replaced or appended and never modified in place
+// so a shallow copy is enough.
+func (txb *Transaction) snapshot() builderState {
+	state := builderState{
+		inputs:            make(map[string]sui_types.CallArg, len(txb.builder.Inputs)),
+		inputsKeyOrder:    txb.builder.InputsKeyOrder,
+		commands:          txb.builder.Commands,
+		unresolvedObjects: make(map[string]UnresolvedObject, len(txb.unresolvedObjects)),
+		intents:           make(map[uint16]coinIntent, len(txb.intents)),
+		receivingObjects:  make(map[string]bool, len(txb.receivingObjects)),
+		unresolvedPures:   maps.Clone(txb.unresolvedPures),
+	}
+	for key, value := range txb.builder.Inputs {
+		state.inputs[key] = value
+	}
+	for key, value := range txb.unresolvedObjects {
+		state.unresolvedObjects[key] = value
+	}
+	for key, value := range txb.intents {
+		state.intents[key] = value
+	}
+	for key, value := range txb.receivingObjects {
+		state.receivingObjects[key] = value
+	}
+
+	return state
+}
+
+// restore rolls the builder back to a snapshot, it is used when adding a command or building fails after the builder was changed.
+func (txb *Transaction) restore(state builderState) {
+	txb.builder.Inputs = state.inputs
+	txb.builder.InputsKeyOrder = state.inputsKeyOrder
+	txb.builder.Commands = state.commands
+	txb.unresolvedObjects = state.unresolvedObjects
+	txb.intents = state.intents
+	txb.receivingObjects = state.receivingObjects
+	txb.unresolvedPures = state.unresolvedPures
+}
+
+// parseMoveCallTarget splits a Move call target into its normalized package, module and function.
+func parseMoveCallTarget(target string) (pkg, mod, fn string, err error) {
+	entry := strings.Split(target, "::")
+	if len(entry) != 3 {
+		return "", "", "", fmt.Errorf("invalid target [%s]", target)
+	}
+
+	return utils.NormalizeSuiObjectID(entry[0]), entry[1], entry[2], nil
+}
+
 func setGasPrice(ctx context.Context, txb *Transaction) error {
 	if txb.GasConfig.Price == 0 {
 		referenceGasPrice, err := txb.client.GetReferenceGasPrice(ctx)
@@ -47,47 +114,22 @@ func setGasPrice(ctx context.Context, txb *Transaction) error {
 	return nil
 }
 
-func setGasBudget(ctx context.Context, txb *Transaction) error {
-	if txb.GasConfig.Budget == 0 {
-		tx := *txb
-
-		dryRunResult, err := tx.DryRunTransactionBlock(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to dry run transaction block, err: %v", err)
-		}
-
-		if dryRunResult.Effects.Status.Status != "success" {
-			return fmt.Errorf("dry run failed, could not automatically determine a budget: %v", dryRunResult.Effects.Status.Error)
-		}
-
-		safeOverhead := utils.GasSafeOverhead * tx.GasConfig.Price
-
-		computationCost, err := strconv.ParseUint(dryRunResult.Effects.GasUsed.ComputationCost, 10, 64)
-		if err != nil {
-			return fmt.Errorf("failed to parse computation cost, err: %v", err)
-		}
-		baseComputationCostWithOverhead := computationCost + safeOverhead
-
-		storageCost, err := strconv.ParseUint(dryRunResult.Effects.GasUsed.StorageCost, 10, 64)
-		if err != nil {
-			return fmt.Errorf("failed to parse storage cost, err: %v", err)
-		}
-		storageRebate, err := strconv.ParseUint(dryRunResult.Effects.GasUsed.StorageRebate, 10, 64)
-		if err != nil {
-			return fmt.Errorf("failed to parse storage rebate, err: %v", err)
-		}
+// checkExpiration verifies that the expiration epoch has not passed, it is not checked without a SuiClient.
+func checkExpiration(ctx context.Context, txb *Transaction) error {
+	if txb.expiration == nil || txb.client == nil {
+		return nil
+	}
 
-		cost := baseComputationCostWithOverhead + storageCost
-		if storageRebate > cost {
-			txb.GasConfig.Budget = baseComputationCostWithOverhead
-		} else {
-			gasBudget := baseComputationCostWithOverhead + storageCost - storageRebate
-			if gasBudget > baseComputationCostWithOverhead {
-				txb.GasConfig.Budget = gasBudget
-			} else {
-				txb.GasConfig.Budget = baseComputationCostWithOverhead
-			}
-		}
+	systemState, err := txb.client.GetLatestSuiSystemState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest sui system state, err: %v", err)
+	}
+	epoch, err := strconv.ParseUint(systemState.Epoch, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid epoch [%s], err: %v", systemState.Epoch, err)
+	}
+	if *txb.expiration < epoch {
+		return fmt.Errorf("transaction expired in epoch %d, the current epoch is %d", *txb.expiration, epoch)
 	}
 
 	return nil
@@ -99,13 +141,15 @@ func setGasPayment(ctx context.Context, txb *Transaction) error {
 		if owner == "" {
 			owner = txb.Sender.String()
 		}
-		coins, err := txb.client.GetCoins(ctx, types.GetCoinsParams{Owner: owner, CoinType: &utils.SuiTypeArg})
+		// the gas coins pay the gas budget and the amounts split from the gas coin
+		amount := saturatingAdd(txb.GasConfig.Budget, txb.gasCoinSplitAmount())
+		coins, err := SelectCoins(ctx, txb.client, owner, utils.SuiTypeArg, amount, &CoinSelectionOptions{Strategy: txb.coinSelectionStrategy, Exclude: txb.inputObjectIDs()})
 		if err != nil {
-			return fmt.Errorf("failed to get coins, err: %v", err)
+			return fmt.Errorf("failed to select gas coins, err: %w", err)
 		}
 
 		paymentCoins := make([]*sui_types.ObjectRef, 0)
-		for _, coin := range coins.Data {
+		for _, coin := range coins {
 			objectRef, err := coinStructToObjectRef(coin)
 			if err != nil {
 				return fmt.Errorf("failed to create object reference, err: %v", err)
@@ -114,16 +158,23 @@ func setGasPayment(ctx context.Context, txb *Transaction) error {
 			paymentCoins = append(paymentCoins, objectRef)
 		}
 
-		if len(paymentCoins) == 0 {
-			return fmt.Errorf("no valid gas coins found for the transaction")
-		}
-
 		txb.GasConfig.Payment = paymentCoins
 	}
 
 	return nil
 }
 
+// inputObjectIDs returns the IDs of all objects used as inputs, these objects can not be used as gas payment.
+func (txb *Transaction) inputObjectIDs() []string {
+	var ids []string
+	for _, key := range txb.builder.InputsKeyOrder {
+		if key.Object != nil {
+			ids = append(ids, key.Object.String())
+		}
+	}
+	return ids
+}
+
 // Check if the param is tx_context.TxContext
 func isTxContext(param types.SuiMoveNormalizedType) bool {
 	structType := extractStructTag(param)
@@ -131,7 +182,28 @@ func isTxContext(param types.SuiMoveNormalizedType) bool {
 		return false
 	}
 
-	return structType.Struct.Address == "0x2" && structType.Struct.Module == "tx_context" && structType.Struct.Name == "TxContext"
+	return utils.NormalizeSuiAddress(structType.Struct.Address) == utils.SuiFrameworkAddress && structType.Struct.Module == "tx_context" && structType.Struct.Name == "TxContext"
+}
+
+// moveFunctionParameters returns the parameters of a Move function that must be provided by the caller, a trailing
+// TxContext is provided by the runtime.
+func moveFunctionParameters(normalized *types.SuiMoveNormalizedFunction) []*types.SuiMoveNormalizedTypeWrapper {
+	parameters := normalized.Parameters
+	if n := len(parameters); n > 0 && isTxContext(parameters[n-1].SuiMoveNormalizedType) {
+		return parameters[:n-1]
+	}
+
+	return parameters
+}
+
+// Check if the param is passed by mutable reference or by value, shared objects used this way must be mutable
+func isMutableParameter(param types.SuiMoveNormalizedType) bool {
+	switch param.(type) {
+	case types.SuiMoveNormalizedTypeMutableReference, types.SuiMoveNormalizedTypeStruct:
+		return true
+	default:
+		return false
+	}
 }
 
 // Extract NormalizedMoveFunction Type
@@ -173,125 +245,127 @@ func extractMutableReference(normalizedType types.SuiMoveNormalizedType) types.S
 
 // Resolve Parameter
 
-// Allowed types are sui_types.Argument, string -> object id, *TransactionInputGasCoin
-func (txb *Transaction) resolveSplitCoinsCoin(coin any) (*UnresolvedParameter, error) {
-	unresolvedParameter := NewUnresolvedParameter(1)
-
-	reflectValue := reflect.ValueOf(coin)
-	switch reflectValue.Type() {
-	case reflect.TypeOf((*sui_types.Argument)(nil)): // nest result
-		unresolvedParameter.Arguments[0] = &UnresolvedArgument{Argument: reflectValue.Interface().(*sui_types.Argument)}
-	case reflect.TypeOf((*TransactionInputGasCoin)(nil)): // gas coin
-		unresolvedParameter.Arguments[0] = &UnresolvedArgument{Argument: &sui_types.Argument{GasCoin: &lib.EmptyEnum{}}}
-	case reflect.TypeOf(""): // object id
-		unresolvedParameter.Objects[0] = UnresolvedObject{Mutable: false, ObjectID: reflectValue.String()}
+// Allowed types are sui_types.Argument, string -> object id, Arg
+func (txb *Transaction) resolveSplitCoinsCoin(coin any) (Arg, error) {
+	switch v := coin.(type) {
+	case *sui_types.Argument: // nest result
+		return argumentToArg(v)
+	case string: // object id
+		return txb.Object(v), nil
+	case Arg: // typed argument, includes *TransactionInputGasCoin
+		return v, nil
 	default:
-		return nil, fmt.Errorf("input coin should one of address(string), sui_types.Argument or *TransactionInputGasCoin, got %v", reflectValue.Type().String())
+		return nil, fmt.Errorf("input coin should one of address(string), sui_types.Argument or *TransactionInputGasCoin, got %T", coin)
 	}
-
-	return unresolvedParameter, nil
 }
 
-// Allowed types are uint, uint8, uint16, uint32, uint64, sui_types.Argument
-func (txb *Transaction) resolveSplitCoinsAmounts(amounts []any) (*UnresolvedParameter, error) {
-	unresolvedParameter := NewUnresolvedParameter(len(amounts))
+// Allowed types are uint, uint8, uint16, uint32, uint64, sui_types.Argument, Arg
+func (txb *Transaction) resolveSplitCoinsAmounts(amounts []any) ([]Arg, error) {
+	args := make([]Arg, len(amounts))
 
 	for idx, amount := range amounts {
-		reflectValue := reflect.ValueOf(amount)
-		switch reflectValue.Type() {
-		case reflect.TypeOf((*sui_types.Argument)(nil)): // nest result
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Argument: reflectValue.Interface().(*sui_types.Argument)}
-		case reflect.TypeOf(uint(0)), reflect.TypeOf(uint8(0)), reflect.TypeOf(uint16(0)), reflect.TypeOf(uint32(0)), reflect.TypeOf(uint64(0)):
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Pure: amount}
+		switch v := amount.(type) {
+		case *sui_types.Argument: // nest result
+			arg, err := argumentToArg(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid argument at index %d: %v", idx, err)
+			}
+			args[idx] = arg
+		case uint, uint8, uint16, uint32, uint64:
+			args[idx] = &pureArg{value: v}
+		case Arg:
+			args[idx] = v
 		default:
-			return nil, fmt.Errorf("input amount should be uint or sui_types.Argument at index %d, got %v", idx, reflectValue.Type().String())
+			return nil, fmt.Errorf("input amount should be uint or sui_types.Argument at index %d, got %T", idx, amount)
 		}
 	}
 
-	return unresolvedParameter, nil
+	return args, nil
 }
 
 // Parse TransferObjects Params
 
-// Allowed types are sui_types.Argument, string -> object id
-func (txb *Transaction) resolveTransferObjectsObjects(objects []any) (*UnresolvedParameter, error) {
-	unresolvedParameter := NewUnresolvedParameter(len(objects))
+// Allowed types are sui_types.Argument, string -> object id, Arg
+func (txb *Transaction) resolveTransferObjectsObjects(objects []any) ([]Arg, error) {
+	args := make([]Arg, len(objects))
 
 	for idx, object := range objects {
-		reflectValue := reflect.ValueOf(object)
-		switch reflectValue.Type() {
-		case reflect.TypeOf((*sui_types.Argument)(nil)): // nest result
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Argument: reflectValue.Interface().(*sui_types.Argument)}
-		case reflect.TypeOf((*TransactionInputGasCoin)(nil)): // gas coin
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Argument: &sui_types.Argument{GasCoin: &lib.EmptyEnum{}}}
-		case reflect.TypeOf(""): // object id
-			unresolvedParameter.Objects[idx] = UnresolvedObject{Mutable: false, ObjectID: reflectValue.String()}
+		switch v := object.(type) {
+		case *sui_types.Argument: // nest result
+			arg, err := argumentToArg(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid argument at index %d: %v", idx, err)
+			}
+			args[idx] = arg
+		case string: // object id
+			args[idx] = txb.Object(v)
+		case Arg: // typed argument, includes *TransactionInputGasCoin
+			args[idx] = v
 		default:
-			return nil, fmt.Errorf("input object should one of address(string), sui_types.Argument or *TransactionInputGasCoin at index %d, got %v", idx, reflectValue.Type().String())
+			return nil, fmt.Errorf("input object should one of address(string), sui_types.Argument or *TransactionInputGasCoin at index %d, got %T", idx, object)
 		}
 	}
 
-	return unresolvedParameter, nil
+	return args, nil
 }
 
-// Allowed types are sui_types.Argument, string -> address
-func (txb *Transaction) resolveTransferObjectsAddress(address any) (*UnresolvedParameter, error) {
-	unresolvedParameter := NewUnresolvedParameter(1)
-
-	reflectValue := reflect.ValueOf(address)
-	switch reflectValue.Type() {
-	case reflect.TypeOf((*sui_types.Argument)(nil)): // nest result
-		unresolvedParameter.Arguments[0] = &UnresolvedArgument{Argument: reflectValue.Interface().(*sui_types.Argument)}
-	case reflect.TypeOf(""): // address
-		suiAddress, err := sui_types.NewAddressFromHex(reflectValue.String())
+// Allowed types are sui_types.Argument, string -> address, Arg
+func (txb *Transaction) resolveTransferObjectsAddress(address any) (Arg, error) {
+	switch v := address.(type) {
+	case *sui_types.Argument: // nest result
+		return argumentToArg(v)
+	case string: // address
+		suiAddress, err := sui_types.NewAddressFromHex(v)
 		if err != nil {
-			return nil, fmt.Errorf("input address must conform to the address(string), got %v", reflectValue.String())
+			return nil, fmt.Errorf("input address must conform to the address(string), got %v", v)
 		}
 
-		unresolvedParameter.Arguments[0] = &UnresolvedArgument{Pure: suiAddress}
+		return Pure(suiAddress), nil
+	case Arg:
+		return v, nil
 	default:
-		return nil, fmt.Errorf("input address should be address(string) or sui_types.Argument, got %v", reflectValue.Type().String())
+		return nil, fmt.Errorf("input address should be address(string) or sui_types.Argument, got %T", address)
 	}
-
-	return unresolvedParameter, nil
 }
 
 // Parse MergeCoins Params
 
-// Allowed types are sui_types.Argument, string -> object id
-func (txb *Transaction) resolveMergeCoinsDestination(destination any) (*UnresolvedParameter, error) {
-	unresolvedParameter := NewUnresolvedParameter(1)
-
-	reflectValue := reflect.ValueOf(destination)
-	switch reflectValue.Type() {
-	case reflect.TypeOf((*sui_types.Argument)(nil)): // nest result
-		unresolvedParameter.Arguments[0] = &UnresolvedArgument{Argument: reflectValue.Interface().(*sui_types.Argument)}
-	case reflect.TypeOf(""): // address
-		unresolvedParameter.Objects[0] = UnresolvedObject{Mutable: false, ObjectID: reflectValue.String()}
+// Allowed types are sui_types.Argument, string -> object id, Arg
+func (txb *Transaction) resolveMergeCoinsDestination(destination any) (Arg, error) {
+	switch v := destination.(type) {
+	case *sui_types.Argument: // nest result
+		return argumentToArg(v)
+	case string: // object id
+		return txb.Object(v), nil
+	case Arg:
+		return v, nil
 	default:
-		return nil, fmt.Errorf("input destination should be address(string) or sui_types.Argument, got %v", reflectValue.Type().String())
+		return nil, fmt.Errorf("input destination should be address(string) or sui_types.Argument, got %T", destination)
 	}
-
-	return unresolvedParameter, nil
 }
 
-// Allowed types are sui_types.Argument, string -> object id
-func (txb *Transaction) resolveMergeCoinsSources(sources []any) (*UnresolvedParameter, error) {
-	unresolvedParameter := NewUnresolvedParameter(len(sources))
+// Allowed types are sui_types.Argument, string -> object id, Arg
+func (txb *Transaction) resolveMergeCoinsSources(sources []any) ([]Arg, error) {
+	args := make([]Arg, len(sources))
 
 	for idx, source := range sources {
-		reflectValue := reflect.ValueOf(source)
-		switch reflectValue.Type() {
-		case reflect.TypeOf((*sui_types.Argument)(nil)): // nest result
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Argument: reflectValue.Interface().(*sui_types.Argument)}
-		case reflect.TypeOf(""): // object id
-			unresolvedParameter.Objects[idx] = UnresolvedObject{Mutable: false, ObjectID: reflectValue.String()}
+		switch v := source.(type) {
+		case *sui_types.Argument: // nest result
+			arg, err := argumentToArg(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid argument at index %d: %v", idx, err)
+			}
+			args[idx] = arg
+		case string: // object id
+			args[idx] = txb.Object(v)
+		case Arg:
+			args[idx] = v
 		default:
-			return nil, fmt.Errorf("input source should be address(string) or sui_types.Argument at index %d, got %v", idx, reflectValue.Type().String())
+			return nil, fmt.Errorf("input source should be address(string) or sui_types.Argument at index %d, got %T", idx, source)
 		}
 	}
 
-	return unresolvedParameter, nil
+	return args, nil
 }
 
 func (txb *Transaction) resolveMakeMoveVecType(vecType string) *move_types.TypeTag {
@@ -325,130 +399,117 @@ func (txb *Transaction) resolveMakeMoveVecType(vecType string) *move_types.TypeT
 	}
 }
 
-func (txb *Transaction) resolveMakeMoveElement(eles []interface{}) (*UnresolvedParameter, error) {
-	unresolvedParameter := NewUnresolvedParameter(len(eles))
+func (txb *Transaction) resolveMakeMoveElement(eles []interface{}) ([]Arg, error) {
+	args := make([]Arg, len(eles))
 
 	for idx, element := range eles {
-		reflectValue := reflect.ValueOf(element)
-		switch reflectValue.Type() {
-		case reflect.TypeOf((*sui_types.Argument)(nil)): // nest result
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Argument: reflectValue.Interface().(*sui_types.Argument)}
-		case reflect.TypeOf(uint(0)), reflect.TypeOf(uint8(0)), reflect.TypeOf(uint16(0)), reflect.TypeOf(uint32(0)), reflect.TypeOf(uint64(0)), reflect.TypeOf(&bcs.Uint128{}), reflect.TypeOf(&bcs.Uint256{}):
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Pure: element}
-		case reflect.TypeOf(""): // object id
-			unresolvedParameter.Objects[idx] = UnresolvedObject{Mutable: false, ObjectID: reflectValue.String()}
+		switch v := element.(type) {
+		case *sui_types.Argument: // nest result
+			arg, err := argumentToArg(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid argument at index %d: %v", idx, err)
+			}
+			args[idx] = arg
+		case uint, uint8, uint16, uint32, uint64, *bcs.Uint128, *bcs.Uint256:
+			args[idx] = &pureArg{value: v}
+		case string: // object id
+			args[idx] = txb.Object(v)
+		case Arg:
+			args[idx] = v
 		default:
-			return nil, fmt.Errorf("input amount should be uint or sui_types.Argument at index %d, got %v", idx, reflectValue.Type().String())
+			return nil, fmt.Errorf("input amount should be uint or sui_types.Argument at index %d, got %T", idx, element)
 		}
 	}
 
-	return unresolvedParameter, nil
+	return args, nil
 }
 
-// Resolve Function
-func (txb *Transaction) resolveMoveFunction(ctx context.Context, pkg, mod, fn string, arguments []interface{}, typeArguments []string) (inputArguments []sui_types.Argument, inputTypeArguments []move_types.TypeTag, returnsCount int, err error) {
-	normalized, err := getNormalizedMoveFunctionFromCache(ctx, txb.client, pkg, mod, fn)
-	if err != nil {
-		return nil, nil, 0, fmt.Errorf("can not get normalized move function in command %d: %v", len(txb.builder.Commands), err)
-	}
-
-	if len(normalized.Parameters) > 0 && isTxContext(normalized.Parameters[len(normalized.Parameters)-1].SuiMoveNormalizedType) {
-		normalized.Parameters = normalized.Parameters[:len(arguments)]
-	}
-
-	if len(arguments) != len(normalized.Parameters) || len(typeArguments) != len(normalized.TypeParameters) {
-		return nil, nil, 0, fmt.Errorf("incorrect number of arguments or type arguments in command %d, required arguments: %d, type arguments: %d", len(txb.builder.Commands), len(normalized.Parameters), len(normalized.TypeParameters))
-	}
-
+// Resolve Function, objects are resolved and the signature is checked when the transaction is built
+func (txb *Transaction) resolveMoveFunction(arguments []Arg, typeArguments []string) (inputArguments []sui_types.Argument, inputTypeArguments []move_types.TypeTag, err error) {
 	inputTypeArguments, err = txb.resolveFunctionTypeArguments(typeArguments)
 	if err != nil {
-		return nil, nil, 0, fmt.Errorf("can not resolve function type arguments in command %d: %v", len(txb.builder.Commands), err)
+		return nil, nil, fmt.Errorf("can not resolve function type arguments in command %d: %v", len(txb.builder.Commands), err)
 	}
 
-	unresolvedParameter, err := txb.resolveFunctionArguments(arguments, normalized.Parameters)
-	if err != nil {
-		return nil, nil, 0, fmt.Errorf("can not resolve function arguments in command %d: %v", len(txb.builder.Commands), err)
+	unresolvedParameter := NewUnresolvedParameter(len(arguments))
+	for idx, argument := range arguments {
+		if err := unresolvedParameter.setArg(txb, idx, argument, false); err != nil {
+			return nil, nil, fmt.Errorf("can not resolve function argument at index %d in command %d: %v", idx, len(txb.builder.Commands), err)
+		}
 	}
 
-	inputArguments, err = unresolvedParameter.resolveAndParseToArguments(ctx, txb.client, txb)
+	inputArguments, err = unresolvedParameter.toArguments(txb)
 	if err != nil {
-		return nil, nil, 0, fmt.Errorf("can not parse unresolved parameter to arguments in command %d: %v", len(txb.builder.Commands), err)
+		return nil, nil, fmt.Errorf("can not parse unresolved parameter to arguments in command %d: %v", len(txb.builder.Commands), err)
 	}
-	return inputArguments, inputTypeArguments, len(normalized.Return), nil
+	return inputArguments, inputTypeArguments, nil
 }
 
-func (txb *Transaction) resolveFunctionArguments(inputArguments []interface{}, requiredArguments []*types.SuiMoveNormalizedTypeWrapper) (*UnresolvedParameter, error) {
-	unresolvedParameter := NewUnresolvedParameter(len(requiredArguments))
+// resolveFunctionArguments converts untyped arguments to Args using the Move function parameters.
+func (txb *Transaction) resolveFunctionArguments(inputArguments []interface{}, requiredArguments []*types.SuiMoveNormalizedTypeWrapper) ([]Arg, error) {
+	args := make([]Arg, len(requiredArguments))
 	for idx, parameter := range requiredArguments {
-		reflecetInput := reflect.ValueOf(inputArguments[idx])
-		if reflecetInput.Type() == reflect.TypeOf((*sui_types.Argument)(nil)) {
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Argument: reflecetInput.Interface().(*sui_types.Argument)}
+		switch input := inputArguments[idx].(type) {
+		case *sui_types.Argument:
+			arg, err := argumentToArg(input)
+			if err != nil {
+				return nil, fmt.Errorf("invalid argument at index %d: %v", idx, err)
+			}
+			args[idx] = arg
+			continue
+		case Arg:
+			args[idx] = input
 			continue
+		case nil:
+			return nil, fmt.Errorf("input parameter must not be nil at index %d", idx)
 		}
 
-		reflectParameter := reflect.ValueOf(parameter.SuiMoveNormalizedType)
-
-		switch reflectParameter.Type() {
-		case reflect.TypeOf(types.SuiMoveNormalizedTypeVector{}):
-			unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Pure: inputArguments[idx]}
-		case reflect.TypeOf(types.SuiMoveNormalizedTypeString("")):
+		switch parameterType := parameter.SuiMoveNormalizedType.(type) {
+		case types.SuiMoveNormalizedTypeVector:
+			args[idx] = &pureArg{value: inputArguments[idx]}
+		case types.SuiMoveNormalizedTypeString:
 			// Here we are only supporting pure types
-			switch reflectParameter.String() {
+			switch parameterType {
 			case "Bool", "U8", "U16", "U32", "U64", "U128", "U256":
-				if reflecetInput.Kind() == reflect.String {
-					return nil, fmt.Errorf("input parameter must be bool or unsigned integer at index %d, got %v", idx, reflecetInput.Type())
+				if _, ok := inputArguments[idx].(string); ok {
+					return nil, fmt.Errorf("input parameter must be bool or unsigned integer at index %d, got %T", idx, inputArguments[idx])
 				}
-				unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Pure: inputArguments[idx]}
+				args[idx] = &pureArg{value: inputArguments[idx]}
 			case "Address":
-				address, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(inputArguments[idx].(string)))
+				input, ok := inputArguments[idx].(string)
+				if !ok {
+					return nil, fmt.Errorf("input parameter must conform to the address(string) at index %d, got %T", idx, inputArguments[idx])
+				}
+				address, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(input))
 				if err != nil {
-					return nil, fmt.Errorf("input parameter must conform to the address(string) at index %d, got %v", idx, inputArguments[idx])
+					return nil, fmt.Errorf("input parameter must conform to the address(string) at index %d, got %v", idx, input)
 				}
-				unresolvedParameter.Arguments[idx] = &UnresolvedArgument{Pure: address}
+				args[idx] = Pure(address)
 			default:
-				return nil, fmt.Errorf("function string parameter [%v] is not supported at index %d", reflectParameter.String(), idx)
-			}
-		default:
-			if reflecetInput.Type().Kind() != reflect.String {
-				return nil, fmt.Errorf("input parameter must be address(string) at index %d, got %v", idx, reflecetInput.Type())
+				return nil, fmt.Errorf("function string parameter [%v] is not supported at index %d", parameterType, idx)
 			}
-			switch reflectParameter.Type() {
-			case reflect.TypeOf(types.SuiMoveNormalizedTypeReference{}):
-				unresolvedParameter.Objects[idx] = UnresolvedObject{Mutable: false, ObjectID: inputArguments[idx].(string)}
-			case reflect.TypeOf(types.SuiMoveNormalizedTypeMutableReference{}):
-				unresolvedParameter.Objects[idx] = UnresolvedObject{Mutable: true, ObjectID: inputArguments[idx].(string)}
-			case reflect.TypeOf(types.SuiMoveNormalizedTypeStruct{}):
-				unresolvedParameter.Objects[idx] = UnresolvedObject{Mutable: false, ObjectID: inputArguments[idx].(string)}
-			default:
-				return nil, fmt.Errorf("function parameter [%v] is not supported at index %d", reflectParameter.Type(), idx)
+		case types.SuiMoveNormalizedTypeReference, types.SuiMoveNormalizedTypeMutableReference, types.SuiMoveNormalizedTypeStruct:
+			input, ok := inputArguments[idx].(string)
+			if !ok {
+				return nil, fmt.Errorf("input parameter must be address(string) at index %d, got %T", idx, inputArguments[idx])
 			}
+			args[idx] = txb.Object(input)
+		default:
+			return nil, fmt.Errorf("function parameter [%T] is not supported at index %d", parameterType, idx)
 		}
 	}
-	return unresolvedParameter, nil
+	return args, nil
 }
 
 func (txb *Transaction) resolveFunctionTypeArguments(typeArguments []string) (inputTypeArguments []move_types.TypeTag, err error) {
 	inputTypeArguments = []move_types.TypeTag{}
 
 	for idx, arg := range typeArguments {
-		entry := strings.Split(arg, "::")
-		if len(entry) != 3 {
-			return nil, fmt.Errorf("input type arguments at index %d must be in the format 'address::module::name', got [%v]", idx, arg)
-		}
-
-		object, err := sui_types.NewObjectIdFromHex(entry[0])
+		typeTag, err := ParseTypeTag(arg)
 		if err != nil {
-			return nil, fmt.Errorf("input type arguments at index %d must be in the format 'address::module::name', got [%v]: %v", idx, entry[0], err)
-		}
-
-		typeTag := move_types.TypeTag{
-			Struct: &move_types.StructTag{
-				Address: *object,
-				Module:  move_types.Identifier(entry[1]),
-				Name:    move_types.Identifier(entry[2]),
-			},
+			return nil, fmt.Errorf("input type argument at index %d is invalid: %v", idx, err)
 		}
-		inputTypeArguments = append(inputTypeArguments, typeTag)
+		inputTypeArguments = append(inputTypeArguments, *typeTag)
 	}
 	return
 }
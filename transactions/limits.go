@@ -0,0 +1,333 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/types"
+)
+
+// Names of the protocol config attributes checked when a transaction is built.
+const (
+	LimitMaxTxSizeBytes            = "max_tx_size_bytes"
+	LimitMaxInputObjects           = "max_input_objects"
+	LimitMaxArguments              = "max_arguments"
+	LimitMaxProgrammableTxCommands = "max_programmable_tx_commands"
+	LimitMaxPureArgumentSize       = "max_pure_argument_size"
+	LimitMaxTypeArguments          = "max_type_arguments"
+	LimitMaxTypeArgumentDepth      = "max_type_argument_depth"
+	LimitMaxGasPaymentObjects      = "max_gas_payment_objects"
+)
+
+// ProtocolLimits defines the limits of a protocol version that transactions are checked against, zero means no limit.
+// As checked by Sui nodes, the size of the transaction data and the number of object inputs may equal their limit and the
+// other values must be less than their limit.
+type ProtocolLimits struct {
+	ProtocolVersion           string
+	MaxTxSizeBytes            uint64 // size of the transaction data
+	MaxInputObjects           uint64 // number of object inputs
+	MaxArguments              uint64 // number of arguments of each command, the number of inputs is not limited by it
+	MaxProgrammableTxCommands uint64 // number of commands
+	MaxPureArgumentSize       uint64 // size of each pure input
+	MaxTypeArguments          uint64 // number of types in the type arguments of each Move call, including nested types
+	MaxTypeArgumentDepth      uint64 // nesting depth of each type argument
+	MaxGasPaymentObjects      uint64 // number of gas coins
+}
+
+// LimitError defines an error returned when a transaction exceeds a limit of the protocol config.
+type LimitError struct {
+	Limit   string // name of the protocol config attribute, one of the Limit constants
+	Input   int    // index of the input, -1 when the limit does not apply to one input
+	Command int    // index of the command, -1 when the limit does not apply to one command
+	Value   uint64
+	Max     uint64
+	Strict  bool // the value must be less than Max
+}
+
+// Error implements the error interface.
+func (e *LimitError) Error() string {
+	location := ""
+	switch {
+	case e.Input >= 0:
+		location = fmt.Sprintf(" in input %d", e.Input)
+	case e.Command >= 0:
+		location = fmt.Sprintf(" in command %d", e.Command)
+	}
+	if e.Strict {
+		return fmt.Sprintf("transaction exceeds protocol limit %s%s: %d is not less than %d", e.Limit, location, e.Value, e.Max)
+	}
+	return fmt.Sprintf("transaction exceeds protocol limit %s%s: %d is greater than %d", e.Limit, location, e.Value, e.Max)
+}
+
+// NewProtocolLimits reads the limits from a protocol config, attributes that are not set are not limited.
+func NewProtocolLimits(config *types.ProtocolConfig) (*ProtocolLimits, error) {
+	limits := &ProtocolLimits{ProtocolVersion: config.ProtocolVersion}
+	for name, field := range map[string]*uint64{
+		LimitMaxTxSizeBytes:            &limits.MaxTxSizeBytes,
+		LimitMaxInputObjects:           &limits.MaxInputObjects,
+		LimitMaxArguments:              &limits.MaxArguments,
+		LimitMaxProgrammableTxCommands: &limits.MaxProgrammableTxCommands,
+		LimitMaxPureArgumentSize:       &limits.MaxPureArgumentSize,
+		LimitMaxTypeArguments:          &limits.MaxTypeArguments,
+		LimitMaxTypeArgumentDepth:      &limits.MaxTypeArgumentDepth,
+		LimitMaxGasPaymentObjects:      &limits.MaxGasPaymentObjects,
+	} {
+		attribute := config.Attributes[name]
+		if attribute == nil {
+			continue
+		}
+
+		var value string
+		switch v := attribute.ProtocolConfigValue.(type) {
+		case types.ProtocolConfigValueU16:
+			value = v.U16
+		case types.ProtocolConfigValueU32:
+			value = v.U32
+		case types.ProtocolConfigValueU64:
+			value = v.U64
+		default:
+			return nil, fmt.Errorf("invalid protocol config attribute [%s], expected an integer", name)
+		}
+
+		var err error
+		if *field, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid protocol config attribute [%s], err: %v", name, err)
+		}
+	}
+	return limits, nil
+}
+
+// protocolLimitsCache defines the limits of each protocol version and the current protocol version of SuiClients.
+var protocolLimitsCache = struct {
+	mutex   sync.Mutex
+	clients map[*client.SuiClient]*clientProtocolLimits
+}{
+	clients: make(map[*client.SuiClient]*clientProtocolLimits),
+}
+
+type clientProtocolLimits struct {
+	version  string
+	epochEnd time.Time // the current protocol version is not fetched again before the end of the epoch
+	versions map[string]*ProtocolLimits
+}
+
+// releaseProtocolLimits removes the cached protocol limits of the SuiClient.
+func releaseProtocolLimits(suiClient *client.SuiClient) {
+	protocolLimitsCache.mutex.Lock()
+	defer protocolLimitsCache.mutex.Unlock()
+
+	delete(protocolLimitsCache.clients, suiClient)
+}
+
+// GetProtocolLimits returns the limits of the current protocol version. The protocol version is cached until the end of
+// the epoch and the limits of each protocol version are fetched once for each SuiClient.
+func GetProtocolLimits(ctx context.Context, suiClient *client.SuiClient) (*ProtocolLimits, error) {
+	protocolLimitsCache.mutex.Lock()
+	cached, ok := protocolLimitsCache.clients[suiClient]
+	if ok && time.Now().Before(cached.epochEnd) {
+		limits := cached.versions[cached.version]
+		protocolLimitsCache.mutex.Unlock()
+		return limits, nil
+	}
+	protocolLimitsCache.mutex.Unlock()
+
+	systemState, err := suiClient.GetLatestSuiSystemState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest sui system state, err: %v", err)
+	}
+	if _, err := strconv.ParseUint(systemState.ProtocolVersion, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid protocol version [%s], err: %v", systemState.ProtocolVersion, err)
+	}
+	// the protocol version is fetched for each transaction when the end of the epoch is unknown
+	var epochEnd time.Time
+	start, startErr := strconv.ParseInt(systemState.EpochStartTimestampMs, 10, 64)
+	duration, durationErr := strconv.ParseInt(systemState.EpochDurationMs, 10, 64)
+	if startErr == nil && durationErr == nil {
+		epochEnd = time.UnixMilli(start + duration)
+	}
+
+	protocolLimitsCache.mutex.Lock()
+	cached, ok = protocolLimitsCache.clients[suiClient]
+	if !ok {
+		cached = &clientProtocolLimits{versions: make(map[string]*ProtocolLimits)}
+		protocolLimitsCache.clients[suiClient] = cached
+	}
+	limits, ok := cached.versions[systemState.ProtocolVersion]
+	if ok {
+		cached.version, cached.epochEnd = systemState.ProtocolVersion, epochEnd
+	}
+	protocolLimitsCache.mutex.Unlock()
+	if ok {
+		return limits, nil
+	}
+
+	config, err := suiClient.GetProtocolConfig(ctx, types.GetProtocolConfigParams{Version: &systemState.ProtocolVersion})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get protocol config of version %s, err: %v", systemState.ProtocolVersion, err)
+	}
+	if limits, err = NewProtocolLimits(config); err != nil {
+		return nil, err
+	}
+
+	protocolLimitsCache.mutex.Lock()
+	cached.versions[systemState.ProtocolVersion] = limits
+	cached.version, cached.epochEnd = systemState.ProtocolVersion, epochEnd
+	protocolLimitsCache.mutex.Unlock()
+	return limits, nil
+}
+
+// SetProtocolLimits sets the limits the transaction is checked against when it is built instead of the limits of the
+// current protocol version. Transactions built without a SuiClient are only checked when limits are set.
+func (txb *Transaction) SetProtocolLimits(limits *ProtocolLimits) {
+	txb.protocolLimits = limits
+}
+
+// getProtocolLimits returns the limits the transaction is checked against, nil when the transaction is not checked.
+// The transaction is not checked when the limits of the current protocol version can not be fetched, the node still rejects
+// a transaction that exceeds them.
+func (txb *Transaction) getProtocolLimits(ctx context.Context) *ProtocolLimits {
+	if txb.protocolLimits != nil || txb.client == nil {
+		return txb.protocolLimits
+	}
+	limits, err := GetProtocolLimits(ctx, txb.client)
+	if err != nil {
+		return nil
+	}
+	return limits
+}
+
+// CheckProgrammableTransaction checks the inputs and commands of a programmable transaction against the limits.
+func (limits *ProtocolLimits) CheckProgrammableTransaction(pt sui_types.ProgrammableTransaction) error {
+	if err := checkLimit(LimitMaxProgrammableTxCommands, -1, -1, len(pt.Commands), limits.MaxProgrammableTxCommands); err != nil {
+		return err
+	}
+
+	objects := 0
+	for idx, input := range pt.Inputs {
+		if input.Object != nil {
+			objects++
+		}
+		if input.Pure != nil {
+			if err := checkLimit(LimitMaxPureArgumentSize, idx, -1, len(*input.Pure), limits.MaxPureArgumentSize); err != nil {
+				return err
+			}
+		}
+	}
+	if err := checkMaxLimit(LimitMaxInputObjects, -1, -1, objects, limits.MaxInputObjects); err != nil {
+		return err
+	}
+
+	for idx, command := range pt.Commands {
+		if err := limits.checkCommand(idx, command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckTransactionData checks the programmable transaction, the gas payment and the size of BCS-encoded transaction data against the limits.
+func (limits *ProtocolLimits) CheckTransactionData(tx *sui_types.TransactionData, bs []byte) error {
+	if tx.V1 == nil {
+		return fmt.Errorf("unsupported transaction data version")
+	}
+	if pt := tx.V1.Kind.ProgrammableTransaction; pt != nil {
+		if err := limits.CheckProgrammableTransaction(*pt); err != nil {
+			return err
+		}
+	}
+	if err := checkLimit(LimitMaxGasPaymentObjects, -1, -1, len(tx.V1.GasData.Payment), limits.MaxGasPaymentObjects); err != nil {
+		return err
+	}
+	return checkMaxLimit(LimitMaxTxSizeBytes, -1, -1, len(bs), limits.MaxTxSizeBytes)
+}
+
+func (limits *ProtocolLimits) checkCommand(idx int, command sui_types.Command) error {
+	var arguments int
+	var typeArguments []move_types.TypeTag
+	switch {
+	case command.MoveCall != nil:
+		arguments, typeArguments = len(command.MoveCall.Arguments), command.MoveCall.TypeArguments
+	case command.TransferObjects != nil:
+		arguments = len(command.TransferObjects.Arguments)
+	case command.SplitCoins != nil:
+		arguments = len(command.SplitCoins.Arguments)
+	case command.MergeCoins != nil:
+		arguments = len(command.MergeCoins.Arguments)
+	case command.MakeMoveVec != nil:
+		arguments = len(command.MakeMoveVec.Arguments)
+		if command.MakeMoveVec.TypeTag != nil {
+			typeArguments = []move_types.TypeTag{*command.MakeMoveVec.TypeTag}
+		}
+	}
+
+	if err := checkLimit(LimitMaxArguments, -1, idx, arguments, limits.MaxArguments); err != nil {
+		return err
+	}
+	count := 0
+	for i := range typeArguments {
+		count += typeTagCount(&typeArguments[i])
+	}
+	if err := checkLimit(LimitMaxTypeArguments, -1, idx, count, limits.MaxTypeArguments); err != nil {
+		return err
+	}
+	for i := range typeArguments {
+		if err := checkLimit(LimitMaxTypeArgumentDepth, -1, idx, typeTagDepth(&typeArguments[i]), limits.MaxTypeArgumentDepth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLimit checks a limit the value must be less than.
+func checkLimit(name string, input, command, value int, limit uint64) error {
+	if limit != 0 && uint64(value) >= limit {
+		return &LimitError{Limit: name, Input: input, Command: command, Value: uint64(value), Max: limit, Strict: true}
+	}
+	return nil
+}
+
+// checkMaxLimit checks a limit the value may equal.
+func checkMaxLimit(name string, input, command, value int, limit uint64) error {
+	if limit != 0 && uint64(value) > limit {
+		return &LimitError{Limit: name, Input: input, Command: command, Value: uint64(value), Max: limit}
+	}
+	return nil
+}
+
+// typeTagDepth returns the nesting depth of a type, the depth of a primitive type is 1.
+func typeTagDepth(tag *move_types.TypeTag) int {
+	switch {
+	case tag.Vector != nil:
+		return 1 + typeTagDepth(tag.Vector)
+	case tag.Struct != nil:
+		depth := 0
+		for i := range tag.Struct.TypeParams {
+			depth = max(depth, typeTagDepth(&tag.Struct.TypeParams[i]))
+		}
+		return 1 + depth
+	default:
+		return 1
+	}
+}
+
+// typeTagCount returns the number of types in a type, including the type itself and its nested types.
+func typeTagCount(tag *move_types.TypeTag) int {
+	switch {
+	case tag.Vector != nil:
+		return 1 + typeTagCount(tag.Vector)
+	case tag.Struct != nil:
+		count := 1
+		for i := range tag.Struct.TypeParams {
+			count += typeTagCount(&tag.Struct.TypeParams[i])
+		}
+		return count
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,334 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+)
+
+func TestProtocolLimits(t *testing.T) {
+	mainnet := transactions.ProtocolLimits{
+		MaxTxSizeBytes:            131072,
+		MaxInputObjects:           2048,
+		MaxArguments:              512,
+		MaxProgrammableTxCommands: 1024,
+		MaxPureArgumentSize:       16384,
+		MaxTypeArguments:          16,
+		MaxTypeArgumentDepth:      16,
+		MaxGasPaymentObjects:      256,
+	}
+
+	tests := []struct {
+		name    string
+		limits  func(limits *transactions.ProtocolLimits)
+		build   func(tx *transactions.Transaction) error
+		limit   string
+		input   int
+		command int
+	}{
+		{
+			name:   "within limits",
+			limits: func(*transactions.ProtocolLimits) {},
+			build: func(tx *transactions.Transaction) error {
+				_, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(1))})
+				return err
+			},
+		},
+		{
+			name:   "inputs are not limited by max_arguments",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxArguments = 3 },
+			build: func(tx *transactions.Transaction) error {
+				for i := 0; i < 2; i++ {
+					if _, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(i)), transactions.Pure(uint64(i + 2))}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name:   "payment to many recipients",
+			limits: func(*transactions.ProtocolLimits) {},
+			build: func(tx *transactions.Transaction) error {
+				recipients, amounts := make([]string, 300), make([]uint64, 300)
+				for i := range recipients {
+					recipients[i], amounts[i] = objectID(0x49100+i), uint64(i+1)
+				}
+				return tx.PaySui(recipients, amounts)
+			},
+		},
+		{
+			name:   "arguments of command",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxArguments = 2 },
+			build: func(tx *transactions.Transaction) error {
+				if _, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(1))}); err != nil {
+					return err
+				}
+				_, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(1)), transactions.Pure(uint64(2))})
+				return err
+			},
+			limit:   transactions.LimitMaxArguments,
+			input:   -1,
+			command: 1,
+		},
+		{
+			name:   "commands",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxProgrammableTxCommands = 2 },
+			build: func(tx *transactions.Transaction) error {
+				for i := 0; i < 2; i++ {
+					if _, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(1))}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			limit:   transactions.LimitMaxProgrammableTxCommands,
+			input:   -1,
+			command: -1,
+		},
+		{
+			name:   "pure argument size",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxPureArgumentSize = 17 },
+			build: func(tx *transactions.Transaction) error {
+				_, err := tx.AddMakeMoveVec("vector<u8>", []transactions.Arg{transactions.Pure([]byte("a")), transactions.Pure(make([]byte, 16))})
+				return err
+			},
+			limit:   transactions.LimitMaxPureArgumentSize,
+			input:   1,
+			command: -1,
+		},
+		{
+			name:   "input objects at limit",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxInputObjects = 2 },
+			build: func(tx *transactions.Transaction) error {
+				return tx.AddTransferObjects([]transactions.Arg{tx.ObjectRef(objectRef(t, objectID(0x49001), 1)), tx.ObjectRef(objectRef(t, objectID(0x49002), 1))}, transactions.Pure(sui_types.SuiAddress{}))
+			},
+		},
+		{
+			name:   "input objects",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxInputObjects = 1 },
+			build: func(tx *transactions.Transaction) error {
+				return tx.AddTransferObjects([]transactions.Arg{tx.ObjectRef(objectRef(t, objectID(0x49001), 1)), tx.ObjectRef(objectRef(t, objectID(0x49002), 1))}, transactions.Pure(sui_types.SuiAddress{}))
+			},
+			limit:   transactions.LimitMaxInputObjects,
+			input:   -1,
+			command: -1,
+		},
+		{
+			name:   "type arguments",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxTypeArguments = 2 },
+			build: func(tx *transactions.Transaction) error {
+				if _, err := tx.AddMakeMoveVec("u8", nil); err != nil {
+					return err
+				}
+				_, err := tx.AddMakeMoveVec("vector<u8>", nil)
+				return err
+			},
+			limit:   transactions.LimitMaxTypeArguments,
+			input:   -1,
+			command: 1,
+		},
+		{
+			name:   "type argument depth",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxTypeArgumentDepth = 4 },
+			build: func(tx *transactions.Transaction) error {
+				if _, err := tx.AddMakeMoveVec("vector<vector<u8>>", nil); err != nil {
+					return err
+				}
+				_, err := tx.AddMakeMoveVec("vector<vector<vector<u8>>>", nil)
+				return err
+			},
+			limit:   transactions.LimitMaxTypeArgumentDepth,
+			input:   -1,
+			command: 1,
+		},
+		{
+			name:   "gas payment objects",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxGasPaymentObjects = 2 },
+			build: func(tx *transactions.Transaction) error {
+				tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x49003), 1), objectRef(t, objectID(0x49004), 1)})
+				return nil
+			},
+			limit:   transactions.LimitMaxGasPaymentObjects,
+			input:   -1,
+			command: -1,
+		},
+		{
+			name:   "transaction size",
+			limits: func(limits *transactions.ProtocolLimits) { limits.MaxTxSizeBytes = 256 },
+			build: func(tx *transactions.Transaction) error {
+				_, err := tx.AddMakeMoveVec("vector<u8>", []transactions.Arg{transactions.Pure(make([]byte, 200))})
+				return err
+			},
+			limit:   transactions.LimitMaxTxSizeBytes,
+			input:   -1,
+			command: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := mainnet
+			tt.limits(&limits)
+
+			tx := transactions.NewTransaction(nil)
+			tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x49005), 1)})
+			tx.SetGasBudget(1000000)
+			tx.SetGasPrice(1000)
+			tx.SetProtocolLimits(&limits)
+			if err := tt.build(tx); err != nil {
+				t.Fatalf("failed to add commands: %v", err)
+			}
+
+			_, _, err := tx.Build(context.Background(), recipient)
+			if tt.limit == "" {
+				if err != nil {
+					t.Fatalf("failed to build transaction: %v", err)
+				}
+				return
+			}
+			var limitErr *transactions.LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected a limit error, but got %v", err)
+			}
+			if limitErr.Limit != tt.limit || limitErr.Input != tt.input || limitErr.Command != tt.command || limitErr.Value < limitErr.Max {
+				t.Errorf("expected limit %s in input %d and command %d, but got %+v", tt.limit, tt.input, tt.command, limitErr)
+			}
+			if !strings.Contains(err.Error(), tt.limit) {
+				t.Errorf("expected error naming %s, but got %v", tt.limit, err)
+			}
+		})
+	}
+}
+
+func TestGetProtocolLimits(t *testing.T) {
+	version, ended := "48", true
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getLatestSuiSystemState": func([]json.RawMessage) (any, error) {
+			state := mockSystemState(version)
+			if ended {
+				state["epochDurationMs"] = "0"
+			}
+			return state, nil
+		},
+		"sui_getProtocolConfig": func(params []json.RawMessage) (any, error) {
+			var requested string
+			if err := json.Unmarshal(params[0], &requested); err != nil {
+				return nil, err
+			}
+			return mockProtocolConfig(requested, map[string]string{"max_programmable_tx_commands": "2"}), nil
+		},
+	})
+
+	build := func(commands int) error {
+		tx := transactions.NewTransaction(suiClient)
+		for i := 0; i < commands; i++ {
+			if _, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(1))}); err != nil {
+				return err
+			}
+		}
+		tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x49006), 1)})
+		tx.SetGasBudget(1000000)
+		tx.SetGasPrice(1000)
+		_, _, err := tx.Build(context.Background(), recipient)
+		return err
+	}
+
+	if err := build(1); err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	var limitErr *transactions.LimitError
+	if err := build(2); !errors.As(err, &limitErr) || limitErr.Limit != transactions.LimitMaxProgrammableTxCommands || limitErr.Max != 2 {
+		t.Fatalf("expected a limit error of %s, but got %v", transactions.LimitMaxProgrammableTxCommands, err)
+	}
+	// the protocol version is fetched again after the end of the epoch, the limits are fetched once for each version
+	if system, config := node.count("suix_getLatestSuiSystemState"), node.count("sui_getProtocolConfig"); system != 2 || config != 1 {
+		t.Errorf("expected the limits to be fetched once for each protocol version, but got %d system state and %d protocol config requests", system, config)
+	}
+
+	version, ended = "49", false
+	for i := 0; i < 2; i++ {
+		limits, err := transactions.GetProtocolLimits(context.Background(), suiClient)
+		if err != nil {
+			t.Fatalf("failed to get protocol limits: %v", err)
+		}
+		if limits.ProtocolVersion != version {
+			t.Errorf("expected protocol version %s, but got %s", version, limits.ProtocolVersion)
+		}
+	}
+	if system, config := node.count("suix_getLatestSuiSystemState"), node.count("sui_getProtocolConfig"); system != 3 || config != 2 {
+		t.Errorf("expected the protocol version to be fetched once in the epoch, but got %d system state and %d protocol config requests", system, config)
+	}
+	// a released client fetches the protocol version and limits again
+	transactions.ReleaseClient(suiClient)
+	if _, err := transactions.GetProtocolLimits(context.Background(), suiClient); err != nil {
+		t.Fatalf("failed to get protocol limits: %v", err)
+	}
+	if system, config := node.count("suix_getLatestSuiSystemState"), node.count("sui_getProtocolConfig"); system != 4 || config != 3 {
+		t.Errorf("expected the limits to be fetched again after release, but got %d system state and %d protocol config requests", system, config)
+	}
+}
+
+func TestNewProtocolLimits(t *testing.T) {
+	var config types.ProtocolConfig
+	bs, err := json.Marshal(mockProtocolConfig("48", map[string]string{"max_tx_size_bytes": "1024"}))
+	if err != nil {
+		t.Fatalf("failed to marshal protocol config: %v", err)
+	}
+	if err := json.Unmarshal(bs, &config); err != nil {
+		t.Fatalf("failed to unmarshal protocol config: %v", err)
+	}
+
+	limits, err := transactions.NewProtocolLimits(&config)
+	if err != nil {
+		t.Fatalf("failed to read protocol limits: %v", err)
+	}
+	if limits.ProtocolVersion != "48" || limits.MaxTxSizeBytes != 1024 || limits.MaxArguments != 512 || limits.MaxGasPaymentObjects != 256 {
+		t.Errorf("unexpected protocol limits %+v", limits)
+	}
+
+	if _, ok := config.Attributes["future_attribute"].ProtocolConfigValue.(types.ProtocolConfigValueUnknown); !ok {
+		t.Errorf("expected an unknown value of an attribute with an unknown type, but got %#v", config.Attributes["future_attribute"])
+	}
+	if bs, err := json.Marshal(config.Attributes["future_attribute"]); err != nil || string(bs) != `{"i128":"1"}` {
+		t.Errorf("expected the unknown value to be marshalled as received, but got %s, err: %v", bs, err)
+	}
+
+	config.Attributes[transactions.LimitMaxTypeArguments] = &types.ProtocolConfigValueWrapper{ProtocolConfigValue: types.ProtocolConfigValueU16{U16: "8"}}
+	if limits, err := transactions.NewProtocolLimits(&config); err != nil || limits.MaxTypeArguments != 8 {
+		t.Errorf("expected max type arguments 8 of a u16 attribute, but got %+v, err: %v", limits, err)
+	}
+
+	config.Attributes[transactions.LimitMaxArguments] = &types.ProtocolConfigValueWrapper{ProtocolConfigValue: types.ProtocolConfigValueF64{F64: "1.5"}}
+	if _, err := transactions.NewProtocolLimits(&config); err == nil || !strings.Contains(err.Error(), transactions.LimitMaxArguments) {
+		t.Errorf("expected an error naming %s, but got %v", transactions.LimitMaxArguments, err)
+	}
+}
+
+func TestBuildWithoutProtocolLimits(t *testing.T) {
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_getProtocolConfig": func([]json.RawMessage) (any, error) {
+			return nil, errors.New("protocol config unavailable")
+		},
+	})
+
+	tx := transactions.NewTransaction(suiClient)
+	if _, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(1))}); err != nil {
+		t.Fatalf("failed to add split coins: %v", err)
+	}
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x49007), 1)})
+	tx.SetGasBudget(1000000)
+	tx.SetGasPrice(1000)
+	// the transaction is not checked when the limits can not be fetched
+	if _, _, err := tx.Build(context.Background(), recipient); err != nil {
+		t.Errorf("failed to build transaction without protocol limits: %v", err)
+	}
+	if got := node.count("sui_getProtocolConfig"); got != 1 {
+		t.Errorf("expected the protocol config to be requested once, but got %d requests", got)
+	}
+}
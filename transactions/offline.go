@@ -0,0 +1,130 @@
+package transactions
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// suppliedObject defines the data of an object referenced by ID that is used instead of fetching the object.
+type suppliedObject struct {
+	ref                  *sui_types.ObjectRef // immutable, owned or receiving object
+	initialSharedVersion *uint64              // shared object
+	receiving            bool
+}
+
+// MissingDataError defines an error returned when a transaction without a SuiClient is built and data that would be
+// fetched from the network was not supplied.
+type MissingDataError struct {
+	Missing []string
+}
+
+// Error implements the error interface.
+func (e *MissingDataError) Error() string {
+	return fmt.Sprintf("missing sui client to build the transaction, the following data must be supplied: %s", strings.Join(e.Missing, ", "))
+}
+
+// SupplyObjectRef supplies the reference of an immutable or owned object referenced by ID, the object is not fetched when the transaction is built.
+func (txb *Transaction) SupplyObjectRef(ref *sui_types.ObjectRef) {
+	txb.suppliedObjects[ref.ObjectId.String()] = suppliedObject{ref: ref}
+}
+
+// SupplySharedObject supplies the initial shared version of a shared object referenced by ID. The mutability is derived from the
+// function signatures, which must be supplied with SupplyMoveFunction when the transaction is built without a SuiClient.
+func (txb *Transaction) SupplySharedObject(id string, initialSharedVersion uint64) error {
+	if !utils.IsValidSuiObjectID(utils.NormalizeSuiObjectID(id)) {
+		return fmt.Errorf("invalid object id [%s]", id)
+	}
+
+	txb.suppliedObjects[utils.NormalizeSuiObjectID(id)] = suppliedObject{initialSharedVersion: &initialSharedVersion}
+	return nil
+}
+
+// SupplyReceivingObject supplies the reference of an object referenced by ID that is received by another object.
+func (txb *Transaction) SupplyReceivingObject(ref *sui_types.ObjectRef) {
+	txb.suppliedObjects[ref.ObjectId.String()] = suppliedObject{ref: ref, receiving: true}
+}
+
+// SupplyMoveFunction supplies the normalized signature of a Move function, which is used instead of fetching the signature.
+func (txb *Transaction) SupplyMoveFunction(target string, function *types.SuiMoveNormalizedFunction) error {
+	pkg, mod, fn, err := parseMoveCallTarget(target)
+	if err != nil {
+		return err
+	}
+	if function == nil {
+		return fmt.Errorf("missing normalized function of [%s]", target)
+	}
+
+	txb.Cache().AddMoveFunctionDefinition(&MoveFunctionCacheEntry{Package: pkg, Module: mod, Function: fn, Normalized: function})
+	return nil
+}
+
+// missingInputData returns the data needed to resolve the inputs and coin intents without a SuiClient that was not supplied.
+func (txb *Transaction) missingInputData() []string {
+	var missing []string
+
+	sharedInputs := make(map[uint16]bool)
+	inputs := txb.unresolvedInputIndexes()
+	for _, idx := range slices.Sorted(maps.Keys(inputs)) {
+		id := inputs[idx]
+		supplied, ok := txb.suppliedObjects[id]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("object [%s]", id))
+		} else if supplied.initialSharedVersion != nil {
+			sharedInputs[idx] = true
+		}
+	}
+
+	functions := make(map[string]bool)
+	for _, command := range txb.builder.Commands {
+		moveCall := command.MoveCall
+		if moveCall == nil {
+			continue
+		}
+		for _, argument := range moveCall.Arguments {
+			if argument.Input == nil || (!sharedInputs[*argument.Input] && !txb.isUnresolvedPure(argument)) {
+				continue
+			}
+			target := fmt.Sprintf("%s::%s::%s", moveCall.Package.String(), moveCall.Module, moveCall.Function)
+			if txb.Cache().GetMoveFunctionDefinition(moveCall.Package.String(), string(moveCall.Module), string(moveCall.Function)) == nil && !functions[target] {
+				functions[target] = true
+				missing = append(missing, fmt.Sprintf("normalized function [%s]", target))
+			}
+			break
+		}
+	}
+
+	coinTypes := make(map[string]bool)
+	for idx := range txb.builder.Commands {
+		intent, ok := txb.intents[uint16(idx)]
+		if ok && !txb.splitsFromGasCoin(intent) && !coinTypes[intent.CoinType] {
+			coinTypes[intent.CoinType] = true
+			missing = append(missing, fmt.Sprintf("coins of [%s]", intent.CoinType))
+		}
+	}
+
+	return missing
+}
+
+// missingGasData returns the sender and gas data that can not be set without a SuiClient.
+func (txb *Transaction) missingGasData() []string {
+	var missing []string
+	if txb.Sender == nil {
+		missing = append(missing, "sender")
+	}
+	if txb.GasConfig.Price == 0 {
+		missing = append(missing, "gas price")
+	}
+	if txb.GasConfig.Budget == 0 {
+		missing = append(missing, "gas budget")
+	}
+	if len(txb.GasConfig.Payment) == 0 {
+		missing = append(missing, "gas payment")
+	}
+	return missing
+}
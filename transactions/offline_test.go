@@ -0,0 +1,168 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+// depositFunction returns the signature of `deposit(&mut Pool, &Config, u64, &mut TxContext)`.
+func depositFunction(t *testing.T, pkg string) *types.SuiMoveNormalizedFunction {
+	modules, err := normalizedModules([]json.RawMessage{json.RawMessage(`"` + pkg + `"`)})
+	if err != nil {
+		t.Fatalf("failed to create normalized modules: %v", err)
+	}
+	bs, err := json.Marshal(modules.(map[string]any)["pool"].(map[string]any)["exposedFunctions"].(map[string]any)["deposit"])
+	if err != nil {
+		t.Fatalf("failed to marshal normalized function: %v", err)
+	}
+
+	var function types.SuiMoveNormalizedFunction
+	if err := json.Unmarshal(bs, &function); err != nil {
+		t.Fatalf("failed to unmarshal normalized function: %v", err)
+	}
+	return &function
+}
+
+func objectRef(t *testing.T, id string, version uint64) *sui_types.ObjectRef {
+	objectID, err := sui_types.NewObjectIdFromHex(id)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+	return &sui_types.ObjectRef{ObjectId: *objectID, Version: version, Digest: make([]byte, 32)}
+}
+
+func TestBuildOfflineReportsMissingData(t *testing.T) {
+	pkg, pool, config := objectID(0x34001), objectID(0x34002), objectID(0x34003)
+	target := pkg + "::pool::deposit"
+
+	tx := transactions.NewTransaction(nil)
+	if _, err := tx.AddMoveCall(target, []transactions.Arg{tx.Object(pool), tx.Object(config), transactions.Pure(uint64(1))}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	coin, err := tx.CoinWithBalance(usdc, 10)
+	if err != nil {
+		t.Fatalf("failed to add coin with balance: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{coin}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+
+	var missing *transactions.MissingDataError
+	_, _, err = tx.Build(context.Background(), recipient)
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected a missing data error, but got %v", err)
+	}
+	expected := []string{"object [" + pool + "]", "object [" + config + "]", "coins of [" + usdc + "]", "gas price", "gas budget", "gas payment"}
+	if !reflect.DeepEqual(expected, missing.Missing) {
+		t.Errorf("expected missing data %v, but got %v", expected, missing.Missing)
+	}
+
+	tx = transactions.NewTransaction(nil)
+	if _, err := tx.AddMoveCall(target, []transactions.Arg{tx.Object(pool), tx.Object(config), transactions.Pure(uint64(1))}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	if err := tx.SupplySharedObject(pool, 7); err != nil {
+		t.Fatalf("failed to supply shared object: %v", err)
+	}
+	tx.SupplyObjectRef(objectRef(t, config, 3))
+	tx.SetGasPrice(1000)
+	tx.SetGasBudget(1000000)
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x34004), 1)})
+
+	_, err = tx.BuildKind(context.Background())
+	if !errors.As(err, &missing) || !reflect.DeepEqual([]string{"normalized function [" + target + "]"}, missing.Missing) {
+		t.Fatalf("expected a missing normalized function error, but got %v", err)
+	}
+
+	if err := tx.SupplyMoveFunction(target, depositFunction(t, pkg)); err != nil {
+		t.Fatalf("failed to supply move function: %v", err)
+	}
+	data, _, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction offline: %v", err)
+	}
+	inputs := data.V1.Kind.ProgrammableTransaction.Inputs
+	if shared := inputs[0].Object.SharedObject; shared == nil || shared.InitialSharedVersion != 7 || !shared.Mutable {
+		t.Errorf("expected mutable shared pool with initial shared version 7, but got %+v", inputs[0].Object)
+	}
+	if owned := inputs[1].Object.ImmOrOwnedObject; owned == nil || owned.Version != 3 {
+		t.Errorf("expected owned config with version 3, but got %+v", inputs[1].Object)
+	}
+}
+
+func TestMoveCallWithoutClient(t *testing.T) {
+	pkg := objectID(0x34005)
+	target := pkg + "::pool::deposit"
+
+	tx := transactions.NewTransaction(nil)
+	var missing *transactions.MissingDataError
+	if _, err := tx.MoveCall(context.Background(), "0x2::coin::zero", nil, []string{"0x2::sui::SUI"}); !errors.As(err, &missing) || !reflect.DeepEqual([]string{"normalized function [" + objectID(2) + "::coin::zero]"}, missing.Missing) {
+		t.Fatalf("expected a missing normalized function error, but got %v", err)
+	}
+
+	if err := tx.SupplyMoveFunction(target, depositFunction(t, pkg)); err != nil {
+		t.Fatalf("failed to supply move function: %v", err)
+	}
+	if _, err := tx.MoveCall(context.Background(), target, []interface{}{tx.ObjectRef(objectRef(t, objectID(0x34006), 1)), tx.ObjectRef(objectRef(t, objectID(0x34007), 1)), uint64(1)}, nil); err != nil {
+		t.Errorf("failed to add move call with a supplied function: %v", err)
+	}
+}
+
+func TestBuildOfflineWithReceivingObjects(t *testing.T) {
+	pkg, parent := objectID(0x34101), objectID(0x34102)
+	received, supplied := objectRef(t, objectID(0x34103), 4), objectRef(t, objectID(0x34104), 5)
+
+	tx := transactions.NewTransaction(nil)
+	args := []transactions.Arg{tx.SharedObjectRef(parent, 2, true), tx.ReceivingRef(received), tx.Object(supplied.ObjectId.String())}
+	if _, err := tx.AddMoveCall(pkg+"::parent::receive", args, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	tx.SupplyReceivingObject(supplied)
+
+	if err := tx.AddTransferObjects([]transactions.Arg{tx.ObjectRef(received)}, transactions.Pure(sui_types.SuiAddress{})); err == nil {
+		t.Errorf("expected an error when a receiving object is used as an owned object, but got nil")
+	}
+
+	kind, err := tx.BuildKind(context.Background())
+	if err != nil {
+		t.Fatalf("failed to build transaction kind offline: %v", err)
+	}
+
+	pt := tx.TransactionBuilder().Finish()
+	plain, err := bcs.Marshal(sui_types.TransactionKind{ProgrammableTransaction: &pt})
+	if err != nil {
+		t.Fatalf("failed to marshal transaction kind: %v", err)
+	}
+	var diffs []int
+	for idx := range plain {
+		if plain[idx] != kind[idx] {
+			diffs = append(diffs, idx)
+			if kind[idx] != 2 {
+				t.Errorf("expected receiving object arg variant at byte %d, but got %d", idx, kind[idx])
+			}
+		}
+	}
+	if len(plain) != len(kind) || len(diffs) != 2 {
+		t.Errorf("expected two receiving object inputs, but got differences at %v", diffs)
+	}
+
+	decoded, err := transactions.FromKind(nil, kind)
+	if err != nil {
+		t.Fatalf("failed to decode transaction kind: %v", err)
+	}
+	rebuilt, err := decoded.BuildKind(context.Background())
+	if err != nil {
+		t.Fatalf("failed to build decoded transaction kind: %v", err)
+	}
+	if !reflect.DeepEqual(kind, rebuilt) {
+		t.Errorf("expected transaction kind %v, but got %v", kind, rebuilt)
+	}
+}
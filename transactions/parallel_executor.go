@@ -0,0 +1,423 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/cryptography"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+const (
+	// DefaultExecutorConcurrency defines the number of transactions a parallel executor executes at the same time.
+	DefaultExecutorConcurrency = 4
+	// DefaultGasCoinBatchSize defines the number of gas coins split from the balance of the signer when the pool is refilled.
+	DefaultGasCoinBatchSize = 20
+	// DefaultInitialGasCoinBalance defines the balance of the gas coins split when the pool is refilled.
+	DefaultInitialGasCoinBalance uint64 = 200000000
+)
+
+// maxRefillCoins defines the number of coins merged by one refill, the gas payment and one merge coins command.
+var maxRefillCoins = maxGasPaymentCoins + utils.MaxArguments - 1
+
+// ParallelExecutorOptions defines options for executing transactions in parallel.
+type ParallelExecutorOptions struct {
+	Concurrency        int    // maximum number of transactions executed at the same time, defaults to DefaultExecutorConcurrency
+	CoinBatchSize      int    // number of gas coins added when the pool is refilled, defaults to DefaultGasCoinBatchSize
+	InitialCoinBalance uint64 // balance of the added gas coins, defaults to DefaultInitialGasCoinBalance
+	GasBudget          uint64 // gas budget of transactions without a gas budget, defaults to DefaultExecutorGasBudget
+}
+
+// poolCoin defines a gas coin of the pool with its expected balance.
+type poolCoin struct {
+	ref     *sui_types.ObjectRef
+	balance uint64
+}
+
+// ParallelExecutor defines an executor that signs and executes the transactions of one signer concurrently. Every transaction
+// is paid with its own gas coin leased from a pool, and only transactions that share owned objects are executed in order.
+type ParallelExecutor struct {
+	client    *client.SuiClient
+	signer    cryptography.Signer
+	options   ParallelExecutorOptions
+	semaphore chan struct{}
+	cache     *objectCache
+	selecting sync.Mutex // held while the coins of coin intents are selected and locked
+
+	mutex     sync.Mutex
+	cond      *sync.Cond
+	pool      []poolCoin      // idle gas coins
+	managed   map[string]bool // idle and leased gas coins, map key is normalized object id
+	locked    map[string]bool // owned objects used by transactions in flight, map key is normalized object id
+	refilling bool
+}
+
+// NewParallelExecutor creates a new ParallelExecutor that executes transactions signed by the signer.
+func NewParallelExecutor(suiClient *client.SuiClient, signer cryptography.Signer, options *ParallelExecutorOptions) *ParallelExecutor {
+	var opts ParallelExecutorOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultExecutorConcurrency
+	}
+	if opts.CoinBatchSize <= 0 {
+		opts.CoinBatchSize = DefaultGasCoinBatchSize
+	}
+	if opts.InitialCoinBalance == 0 {
+		opts.InitialCoinBalance = DefaultInitialGasCoinBalance
+	}
+	opts.GasBudget = executorGasBudget(opts.GasBudget)
+
+	executor := &ParallelExecutor{
+		client:    suiClient,
+		signer:    signer,
+		options:   opts,
+		semaphore: make(chan struct{}, opts.Concurrency),
+		cache:     newObjectCache(signer.ToSuiAddress()),
+		managed:   make(map[string]bool),
+		locked:    make(map[string]bool),
+	}
+	executor.cond = sync.NewCond(&executor.mutex)
+	return executor
+}
+
+// ExecuteTransaction builds, signs and executes the transaction with a gas coin of the pool. It waits until the transactions
+// in flight using the same owned objects are executed, the gas payment must not be set. The coins of coin intents are selected
+// from the coins that are not used by transactions in flight.
+func (e *ParallelExecutor) ExecuteTransaction(ctx context.Context, tx *Transaction, options *types.SuiTransactionBlockResponseOptions) (*types.SuiTransactionBlockResponse, error) {
+	select {
+	case e.semaphore <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-e.semaphore }()
+
+	if err := prepareForExecutor(tx, e.client, e.signer); err != nil {
+		return nil, err
+	}
+	if len(tx.GasConfig.Payment) > 0 {
+		return nil, fmt.Errorf("gas payment of transactions executed in parallel is selected by the executor")
+	}
+
+	restore := tx.snapshotForExecution()
+	ids, err := e.lockInputs(ctx, tx)
+	if err != nil {
+		restore()
+		return nil, err
+	}
+	defer e.unlockObjects(ids)
+
+	coin, err := e.leaseGasCoin(ctx)
+	if err != nil {
+		restore()
+		return nil, err
+	}
+
+	tx.SetGasBudgetIfNotSet(e.options.GasBudget)
+	tx.SetGasPayment([]*sui_types.ObjectRef{coin.ref})
+	e.cache.supply(tx)
+
+	_, bs, err := tx.Build(ctx, e.signer.ToSuiAddress())
+	if err != nil {
+		restore()
+		e.returnGasCoin(coin)
+		return nil, err
+	}
+	response, err := signAndExecute(ctx, e.client, e.signer, bs, options)
+	if err != nil {
+		// the gas coin and the objects may have been used, they are fetched again
+		restore()
+		e.dropGasCoin(coin)
+		e.cache.invalidate(ids...)
+		return nil, err
+	}
+
+	gasCoin := e.cache.applyEffects(response)
+	tx.observeSharedObjects(response)
+	if gasCoin == nil || tx.usesGasCoin() {
+		// the balance of a gas coin used by the commands is unknown, it is merged by the next refill
+		e.dropGasCoin(coin)
+	} else {
+		e.releaseGasCoin(coin, gasCoin, response.Effects.GasUsed)
+	}
+	return response, executionError(response)
+}
+
+// lockInputs locks the owned objects of the transaction. The coins of coin intents are selected one transaction at a time
+// from the coins that are neither in the pool nor used by a transaction in flight, and are locked with the other objects.
+func (e *ParallelExecutor) lockInputs(ctx context.Context, tx *Transaction) ([]string, error) {
+	if err := e.cacheSharedInputs(ctx, tx); err != nil {
+		return nil, err
+	}
+	if len(tx.intents) == 0 {
+		ids := tx.ownedInputIDs()
+		e.lockObjects(ids)
+		return ids, nil
+	}
+
+	e.selecting.Lock()
+	defer e.selecting.Unlock()
+	if _, err := tx.resolveIntentsExcluding(ctx, e.unavailableCoins()); err != nil {
+		return nil, fmt.Errorf("can not resolve coin intents: %w", err)
+	}
+	ids := tx.ownedInputIDs()
+	e.lockObjects(ids)
+	return ids, nil
+}
+
+// cacheSharedInputs fetches the objects referenced by ID that are neither supplied, cached as shared objects nor owned
+// objects observed by the executor, the shared objects are cached and are not locked.
+func (e *ParallelExecutor) cacheSharedInputs(ctx context.Context, tx *Transaction) error {
+	var ids []string
+	e.cache.mutex.Lock()
+	for id := range tx.unresolvedObjects {
+		if _, ok := tx.suppliedObjects[id]; ok || e.cache.objects[id] != nil || tx.Cache().GetSharedObject(id) != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	e.cache.mutex.Unlock()
+	slices.Sort(ids)
+
+	_, err := tx.fetchObjectArgs(ctx, ids, nil)
+	return err
+}
+
+// lockObjects waits until none of the objects is used by a transaction in flight and marks them as used.
+func (e *ParallelExecutor) lockObjects(ids []string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for slices.ContainsFunc(ids, func(id string) bool { return e.locked[id] }) {
+		e.cond.Wait()
+	}
+	for _, id := range ids {
+		e.locked[id] = true
+	}
+}
+
+func (e *ParallelExecutor) unlockObjects(ids []string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, id := range ids {
+		delete(e.locked, id)
+	}
+	e.cond.Broadcast()
+}
+
+// leaseGasCoin takes a gas coin from the pool, the pool is refilled when it is empty.
+func (e *ParallelExecutor) leaseGasCoin(ctx context.Context) (poolCoin, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for {
+		if len(e.pool) > 0 {
+			coin := e.pool[len(e.pool)-1]
+			e.pool = e.pool[:len(e.pool)-1]
+			return coin, nil
+		}
+		if e.refilling {
+			e.cond.Wait()
+			continue
+		}
+
+		e.refilling = true
+		e.mutex.Unlock()
+		err := e.refill(ctx)
+		e.mutex.Lock()
+		e.refilling = false
+		e.cond.Broadcast()
+		if err != nil {
+			return poolCoin{}, fmt.Errorf("failed to refill gas coin pool, err: %w", err)
+		}
+	}
+}
+
+// returnGasCoin returns an unused gas coin to the pool.
+func (e *ParallelExecutor) returnGasCoin(coin poolCoin) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.pool = append(e.pool, coin)
+	e.cond.Broadcast()
+}
+
+// releaseGasCoin returns the gas coin to the pool with the balance left after paying the gas, a gas coin that can not
+// pay the gas budget is merged by the next refill.
+func (e *ParallelExecutor) releaseGasCoin(coin poolCoin, ref *sui_types.ObjectRef, gasUsed types.GasCostSummary) {
+	estimate, err := parseGasCostSummary(gasUsed)
+	if err != nil {
+		e.dropGasCoin(coin)
+		return
+	}
+
+	balance := saturatingAdd(coin.balance, estimate.StorageRebate)
+	if charged := saturatingAdd(estimate.ComputationCost, estimate.StorageCost); charged < balance {
+		balance -= charged
+	} else {
+		balance = 0
+	}
+	if balance < e.options.GasBudget {
+		e.dropGasCoin(coin)
+		return
+	}
+	e.returnGasCoin(poolCoin{ref: ref, balance: balance})
+}
+
+// dropGasCoin removes the gas coin from the pool, it is an ordinary coin of the signer afterwards.
+func (e *ParallelExecutor) dropGasCoin(coin poolCoin) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	delete(e.managed, coin.ref.ObjectId.String())
+}
+
+// refill merges the SUI coins of the signer that are not in the pool or used by a transaction in flight, and splits
+// gas coins with the initial balance from them. The largest utils.MaxGasObjects - 1 coins pay the gas and the next coins are
+// merged into the gas coin, the remaining coins are merged by later refills.
+func (e *ParallelExecutor) refill(ctx context.Context) error {
+	owner := e.signer.ToSuiAddress()
+	coins, err := SelectCoins(ctx, e.client, owner, utils.SuiTypeArg, 0, &CoinSelectionOptions{Strategy: AllCoins, MaxCoins: maxRefillCoins, Exclude: e.unavailableCoins()})
+	if err != nil {
+		return err
+	}
+
+	// coins used by transactions that started while the coins were fetched are skipped
+	e.mutex.Lock()
+	var sources []types.CoinStruct
+	var ids []string
+	for _, coin := range coins {
+		id := utils.NormalizeSuiObjectID(coin.CoinObjectID)
+		if !e.locked[id] && !e.managed[id] {
+			e.locked[id] = true
+			sources, ids = append(sources, coin), append(ids, id)
+		}
+	}
+	e.mutex.Unlock()
+	defer e.unlockObjects(ids)
+
+	var balance uint64
+	refs := make([]*sui_types.ObjectRef, len(sources))
+	for idx, coin := range sources {
+		value, err := strconv.ParseUint(coin.Balance, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid balance [%s] of coin [%s]: %v", coin.Balance, coin.CoinObjectID, err)
+		}
+		balance = saturatingAdd(balance, value)
+		if refs[idx], err = coinStructToObjectRef(coin); err != nil {
+			return fmt.Errorf("failed to create object reference, err: %v", err)
+		}
+	}
+	// the coins are selected the largest first
+	payment, merged := refs[:min(len(refs), maxGasPaymentCoins)], refs[min(len(refs), maxGasPaymentCoins):]
+
+	count := 0
+	if balance > e.options.GasBudget {
+		count = int(min(uint64(e.options.CoinBatchSize), (balance-e.options.GasBudget)/e.options.InitialCoinBalance))
+	}
+	if count == 0 {
+		return &InsufficientBalanceError{Owner: owner, CoinType: utils.SuiTypeArg, Required: e.options.GasBudget + e.options.InitialCoinBalance, Available: balance}
+	}
+
+	tx := NewTransaction(e.client)
+	tx.SetSender(owner)
+	tx.SetGasBudget(e.options.GasBudget)
+	tx.SetGasPayment(payment)
+	if len(merged) > 0 {
+		sources := make([]Arg, len(merged))
+		for idx, ref := range merged {
+			sources[idx] = tx.ObjectRef(ref)
+		}
+		if err := tx.AddMergeCoins(tx.Gas(), sources); err != nil {
+			return err
+		}
+	}
+	if err := tx.PaySui(slices.Repeat([]string{owner}, count), slices.Repeat([]uint64{e.options.InitialCoinBalance}, count)); err != nil {
+		return err
+	}
+	_, bs, err := tx.Build(ctx, owner)
+	if err != nil {
+		return err
+	}
+	response, err := signAndExecute(ctx, e.client, e.signer, bs, nil)
+	if err != nil {
+		return err
+	}
+	e.cache.applyEffects(response)
+	if err := executionError(response); err != nil {
+		return err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, created := range response.Effects.Created {
+		addressOwner, ok := created.Owner.ObjectOwner.(types.ObjectOwnerAddressOwner)
+		if !ok || utils.NormalizeSuiAddress(addressOwner.AddressOwner) != utils.NormalizeSuiAddress(owner) {
+			continue
+		}
+		ref, err := ObjectStringRef{ObjectID: created.Reference.ObjectID, Version: strconv.FormatUint(created.Reference.Version, 10), Digest: created.Reference.Digest}.ToObjectRef()
+		if err != nil {
+			return fmt.Errorf("failed to create object reference, err: %v", err)
+		}
+		e.managed[ref.ObjectId.String()] = true
+		e.pool = append(e.pool, poolCoin{ref: ref, balance: e.options.InitialCoinBalance})
+	}
+	return nil
+}
+
+// unavailableCoins returns the IDs of the gas coins of the pool and the objects used by transactions in flight.
+func (e *ParallelExecutor) unavailableCoins() []string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	ids := make([]string, 0, len(e.managed)+len(e.locked))
+	for id := range e.managed {
+		ids = append(ids, id)
+	}
+	for id := range e.locked {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// ownedInputIDs returns the IDs of the object inputs that are not shared, objects referenced by ID are treated as owned
+// unless they are supplied or cached as shared objects.
+func (txb *Transaction) ownedInputIDs() []string {
+	var ids []string
+	for _, key := range txb.builder.InputsKeyOrder {
+		if key.Object == nil {
+			continue
+		}
+		id := key.Object.String()
+		if input := txb.builder.Inputs[key.String()]; input.Object != nil && input.Object.SharedObject != nil {
+			continue
+		}
+		if supplied, ok := txb.suppliedObjects[id]; ok && supplied.initialSharedVersion != nil {
+			continue
+		} else if !ok && txb.Cache().GetSharedObject(id) != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// usesGasCoin reports whether a command of the transaction uses the gas coin.
+func (txb *Transaction) usesGasCoin() bool {
+	var used bool
+	for _, command := range txb.builder.Commands {
+		remapCommand(command, func(argument sui_types.Argument) sui_types.Argument {
+			used = used || argument.GasCoin != nil
+			return argument
+		})
+	}
+	return used
+}
@@ -0,0 +1,367 @@
+package transactions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/keypairs/ed25519"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// mockPool executes refills paid with the source coin by creating gas coins, and other transactions by bumping the
+// versions of their gas coin and objects. Objects used by concurrent transactions are recorded as conflicts.
+type mockPool struct {
+	mutex     sync.Mutex
+	owner     string
+	source    string
+	batchSize int
+	objects   []string
+	versions  map[string]uint64
+	coins     []string
+	used      map[string][]uint64 // versions of the objects used by transactions
+	inFlight  map[string]bool
+	conflicts []string
+	executing int
+	peak      int
+}
+
+func (m *mockPool) id(t *testing.T, id string) []byte {
+	objectID, err := sui_types.NewObjectIdFromHex(id)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+	return objectID[:]
+}
+
+func (m *mockPool) owned(id string) map[string]any {
+	m.versions[id]++
+	return map[string]any{"owner": map[string]any{"AddressOwner": m.owner}, "reference": map[string]any{"objectId": id, "version": m.versions[id], "digest": zeroDigest}}
+}
+
+func (m *mockPool) execute(t *testing.T) func(params []json.RawMessage) (any, error) {
+	gasUsed := map[string]any{"computationCost": "1000", "storageCost": "2000", "storageRebate": "1000", "nonRefundableStorageFee": "10"}
+	success := map[string]any{"status": "success"}
+
+	return func(params []json.RawMessage) (any, error) {
+		var encoded string
+		if err := json.Unmarshal(params[0], &encoded); err != nil {
+			return nil, err
+		}
+		bs, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		m.mutex.Lock()
+		if bytes.Contains(bs, m.id(t, m.source)) {
+			defer m.mutex.Unlock()
+			var created []any
+			for range m.batchSize {
+				id := objectID(0x37100 + len(m.coins))
+				m.coins = append(m.coins, id)
+				created = append(created, m.owned(id))
+			}
+			effects := map[string]any{"status": success, "gasUsed": gasUsed, "created": created, "gasObject": m.owned(m.source)}
+			return map[string]any{"digest": zeroDigest, "effects": effects}, nil
+		}
+
+		var used []string
+		for _, id := range slices.Concat(m.coins, m.objects) {
+			if !bytes.Contains(bs, m.id(t, id)) {
+				continue
+			}
+			if m.inFlight[id] {
+				m.conflicts = append(m.conflicts, id)
+			}
+			m.inFlight[id] = true
+			used = append(used, id)
+			m.used[id] = append(m.used[id], m.versions[id])
+		}
+		m.executing++
+		m.peak = max(m.peak, m.executing)
+		m.mutex.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		m.executing--
+
+		var mutated []any
+		var gasObject map[string]any
+		for _, id := range used {
+			delete(m.inFlight, id)
+			if gasObject == nil && slices.Contains(m.coins, id) {
+				gasObject = m.owned(id)
+				continue
+			}
+			mutated = append(mutated, m.owned(id))
+		}
+		if gasObject == nil {
+			return nil, fmt.Errorf("missing gas coin")
+		}
+		effects := map[string]any{"status": success, "gasUsed": gasUsed, "mutated": mutated, "gasObject": gasObject}
+		return map[string]any{"digest": zeroDigest, "effects": effects}, nil
+	}
+}
+
+func TestParallelExecutorLeasesGasCoins(t *testing.T) {
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+	source := newCoins(10000000000)
+	objects := []string{objectID(0x37001), objectID(0x37001), objectID(0x37002), objectID(0x37003), objectID(0x37004), objectID(0x37005)}
+	pool := &mockPool{
+		owner:     signer.ToSuiAddress(),
+		source:    source[0].CoinObjectID,
+		batchSize: 3,
+		objects:   objects[1:],
+		versions:  map[string]uint64{source[0].CoinObjectID: 1},
+		used:      make(map[string][]uint64),
+		inFlight:  make(map[string]bool),
+	}
+	for _, id := range objects[1:] {
+		pool.versions[id] = 1
+	}
+
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getCoins":               getCoins(source),
+		"suix_getReferenceGasPrice":   func([]json.RawMessage) (any, error) { return "1000", nil },
+		"sui_multiGetObjects":         multiGetObjects(nil),
+		"sui_executeTransactionBlock": pool.execute(t),
+	})
+	executor := transactions.NewParallelExecutor(suiClient, signer, &transactions.ParallelExecutorOptions{Concurrency: 3, CoinBatchSize: 3, InitialCoinBalance: 1000000000})
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(objects))
+	for idx, id := range objects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx := transactions.NewTransaction(suiClient)
+			if err := tx.AddTransferObjects([]transactions.Arg{tx.Object(id)}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+				errs[idx] = err
+				return
+			}
+			_, errs[idx] = executor.ExecuteTransaction(context.Background(), tx, nil)
+		}()
+	}
+	wg.Wait()
+
+	for idx, err := range errs {
+		if err != nil {
+			t.Errorf("failed to execute transaction %d: %v", idx, err)
+		}
+	}
+	if len(pool.conflicts) > 0 {
+		t.Errorf("expected gas coins and owned objects not to be used concurrently, but got conflicts on %v", pool.conflicts)
+	}
+	if pool.peak > 3 {
+		t.Errorf("expected at most 3 transactions in flight, but got %d", pool.peak)
+	}
+	if got := node.count("suix_getCoins"); got != 1 {
+		t.Errorf("expected the pool to be refilled once, but got %d refills", got)
+	}
+	if got := node.count("sui_executeTransactionBlock"); got != len(objects)+1 {
+		t.Errorf("expected %d executions, but got %d", len(objects)+1, got)
+	}
+	if versions := pool.used[objects[0]]; len(versions) != 2 || versions[0] != 1 || versions[1] != 2 {
+		t.Errorf("expected the object of two transactions to be used with versions [1 2], but got %v", versions)
+	}
+}
+
+func TestParallelExecutorRunsSharedObjectsConcurrently(t *testing.T) {
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+	source := newCoins(10000000000)
+	shared := objectID(0x37201)
+	pool := &mockPool{
+		owner:     signer.ToSuiAddress(),
+		source:    source[0].CoinObjectID,
+		batchSize: 2,
+		versions:  map[string]uint64{source[0].CoinObjectID: 1},
+		used:      make(map[string][]uint64),
+		inFlight:  make(map[string]bool),
+	}
+
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getCoins":               getCoins(source),
+		"suix_getReferenceGasPrice":   func([]json.RawMessage) (any, error) { return "1000", nil },
+		"sui_multiGetObjects":         multiGetObjects(map[string]uint64{shared: 3}),
+		"sui_executeTransactionBlock": pool.execute(t),
+	})
+	executor := transactions.NewParallelExecutor(suiClient, signer, &transactions.ParallelExecutorOptions{Concurrency: 2, CoinBatchSize: 2, InitialCoinBalance: 1000000000})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for idx := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx := transactions.NewTransaction(suiClient)
+			tx.SetCache(transactions.NewLRUCache(0))
+			if err := tx.AddTransferObjects([]transactions.Arg{tx.Object(shared)}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+				errs[idx] = err
+				return
+			}
+			_, errs[idx] = executor.ExecuteTransaction(context.Background(), tx, nil)
+		}()
+	}
+	wg.Wait()
+
+	for idx, err := range errs {
+		if err != nil {
+			t.Errorf("failed to execute transaction %d: %v", idx, err)
+		}
+	}
+	// the shared object referenced by ID is not locked, the transactions are executed at the same time
+	if pool.peak != 2 {
+		t.Errorf("expected 2 transactions using the shared object in flight, but got %d", pool.peak)
+	}
+}
+
+func TestParallelExecutorLocksIntentCoins(t *testing.T) {
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+	source, coins := newCoins(10000000000), newTypedCoins(usdc, 100, 100, 100)
+	pool := &mockPool{
+		owner:     signer.ToSuiAddress(),
+		source:    source[0].CoinObjectID,
+		batchSize: 3,
+		versions:  map[string]uint64{source[0].CoinObjectID: 1},
+		used:      make(map[string][]uint64),
+		inFlight:  make(map[string]bool),
+	}
+	for _, coin := range coins {
+		pool.objects = append(pool.objects, coin.CoinObjectID)
+		pool.versions[coin.CoinObjectID] = 1
+	}
+
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getCoins":               getCoinsByType(map[string][]types.CoinStruct{utils.SuiTypeArg: source, usdc: coins}),
+		"suix_getReferenceGasPrice":   func([]json.RawMessage) (any, error) { return "1000", nil },
+		"sui_executeTransactionBlock": pool.execute(t),
+	})
+	executor := transactions.NewParallelExecutor(suiClient, signer, &transactions.ParallelExecutorOptions{Concurrency: 3, CoinBatchSize: 3, InitialCoinBalance: 1000000000})
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(coins))
+	for idx := range coins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx := transactions.NewTransaction(suiClient)
+			coin, err := tx.CoinWithBalance(usdc, 50)
+			if err != nil {
+				errs[idx] = err
+				return
+			}
+			if err := tx.AddTransferObjects([]transactions.Arg{coin}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+				errs[idx] = err
+				return
+			}
+			_, errs[idx] = executor.ExecuteTransaction(context.Background(), tx, nil)
+		}()
+	}
+	wg.Wait()
+
+	for idx, err := range errs {
+		if err != nil {
+			t.Errorf("failed to execute transaction %d: %v", idx, err)
+		}
+	}
+	// the coins selected for transactions in flight are excluded, so every transaction selects another coin
+	if len(pool.conflicts) > 0 {
+		t.Errorf("expected the selected coins not to be used concurrently, but got conflicts on %v", pool.conflicts)
+	}
+	for _, coin := range coins {
+		if versions := pool.used[coin.CoinObjectID]; len(versions) != 1 {
+			t.Errorf("expected coin %s to be used once, but got versions %v", coin.CoinObjectID, versions)
+		}
+	}
+}
+
+func TestParallelExecutorRefillCapsGasPayment(t *testing.T) {
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+	balances := make([]uint64, utils.MaxGasObjects+44)
+	for idx := range balances {
+		balances[idx] = uint64(idx+1) * 10000000
+	}
+	coins := newCoins(balances...)
+
+	var refill *sui_types.TransactionData
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getCoins":             getCoins(coins),
+		"suix_getReferenceGasPrice": func([]json.RawMessage) (any, error) { return "1000", nil },
+		"sui_executeTransactionBlock": func(params []json.RawMessage) (any, error) {
+			var encoded string
+			if err := json.Unmarshal(params[0], &encoded); err != nil {
+				return nil, err
+			}
+			bs, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, err
+			}
+			if refill, err = transactions.DecodeTransactionData(bs); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("refill rejected")
+		},
+	})
+
+	tx := transactions.NewTransaction(suiClient)
+	if err := tx.AddTransferObjects([]transactions.Arg{tx.ObjectRef(objectRef(t, objectID(0x37301), 1))}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	if _, err := transactions.NewParallelExecutor(suiClient, signer, nil).ExecuteTransaction(context.Background(), tx, nil); err == nil {
+		t.Fatalf("expected the rejected refill to fail the transaction, but got nil")
+	}
+	if refill == nil {
+		t.Fatalf("expected a refill transaction")
+	}
+
+	// the largest coins pay the gas and the smaller coins are merged into the gas coin
+	payment := refill.V1.GasData.Payment
+	if len(payment) != utils.MaxGasObjects-1 || payment[0].ObjectId.String() != coins[len(coins)-1].CoinObjectID {
+		t.Errorf("expected a gas payment of the %d largest coins, but got %d coins", utils.MaxGasObjects-1, len(payment))
+	}
+	pt := refill.V1.Kind.ProgrammableTransaction
+	merge := pt.Commands[0].MergeCoins
+	if merge == nil || merge.Argument.GasCoin == nil || len(merge.Arguments) != 45 {
+		t.Fatalf("expected 45 coins merged into the gas coin, but got %+v", pt.Commands[0])
+	}
+	if ref := pt.Inputs[*merge.Arguments[0].Input].Object.ImmOrOwnedObject; ref == nil || ref.ObjectId.String() != coins[44].CoinObjectID {
+		t.Errorf("expected the merge of coin %s first, but got %+v", coins[44].CoinObjectID, pt.Inputs[*merge.Arguments[0].Input])
+	}
+}
+
+func TestParallelExecutorRejectsGasPayment(t *testing.T) {
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+
+	tx := transactions.NewTransaction(nil)
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x37201), 1)})
+	if _, err := transactions.NewParallelExecutor(nil, signer, nil).ExecuteTransaction(context.Background(), tx, nil); err == nil {
+		t.Errorf("expected an error for a transaction with gas payment, but got nil")
+	}
+}
@@ -1,12 +1,9 @@
 package transactions
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
-	"github.com/W3Tools/gosui/client"
-	"github.com/W3Tools/gosui/types"
 	"github.com/W3Tools/gosui/utils"
 )
 
@@ -18,10 +15,10 @@ type UnresolvedParameter struct {
 
 // UnresolvedArgument defines an unresolved argument for a transaction command.
 type UnresolvedArgument struct {
-	Pure     any
-	Object   *sui_types.ObjectArg
-	Argument *sui_types.Argument
-	resolved bool
+	Pure      any
+	Object    *sui_types.ObjectArg
+	Receiving bool // Object is an object reference encoded as a receiving object
+	Argument  *sui_types.Argument
 }
 
 // UnresolvedArguments defines a slice of unresolved arguments.
@@ -41,104 +38,26 @@ func NewUnresolvedParameter(count int) *UnresolvedParameter {
 	}
 }
 
-// merge merges another UnresolvedParameter into the current one.
-func (up *UnresolvedParameter) merge(dest *UnresolvedParameter) {
-	if dest == nil {
-		return
-	}
-
-	count := len(up.Arguments)
-
-	up.Arguments = append(up.Arguments, dest.Arguments...)
-	for idx, obj := range dest.Objects {
-		up.Objects[idx+count] = obj
-	}
-}
-
-// resolveAndParseToArguments resolves objects and parses all arguments to Sui types.
-func (up *UnresolvedParameter) resolveAndParseToArguments(ctx context.Context, suiClient *client.SuiClient, txb *Transaction) ([]sui_types.Argument, error) {
-	err := up.resolveObjects(ctx, suiClient)
-	if err != nil {
-		return nil, fmt.Errorf("can not resolve objects: %v", err)
-	}
-
-	return up.toArguments(txb)
-}
-
-// resolveObjects resolves all unresolved objects using the Sui client.
-func (up *UnresolvedParameter) resolveObjects(ctx context.Context, suiClient *client.SuiClient) error {
-	var ids []string
-	for idx, resolve := range up.Objects {
-		if entry := cache.GetSharedObject(resolve.ObjectID); entry != nil {
-			up.Arguments[idx] = &UnresolvedArgument{Object: entry.ToObjectArg(resolve.Mutable), resolved: true}
-		} else {
-			ids = append(ids, resolve.ObjectID)
-		}
-	}
-
-	if len(ids) > 0 {
-		var objects []*types.SuiObjectResponse
-		objects, err := suiClient.MultiGetObjects(ctx, types.MultiGetObjectsParams{IDs: ids, Options: &types.SuiObjectDataOptions{ShowOwner: true}})
-		if err != nil {
-			return fmt.Errorf("can not call jsonrpc to multi get objects: %v", err)
-		}
-
-		objectMap := utils.SliceToMap(objects, func(v *types.SuiObjectResponse) string {
-			if v.Data != nil {
-				return v.Data.ObjectID
-			}
-			return ""
-		})
-
-		for idx, resolveObject := range up.Objects {
-			if idx >= len(up.Arguments) {
-				return fmt.Errorf("can not resolve object at index %d, out of range", idx)
-			}
-
-			if argument := up.Arguments[idx]; argument != nil {
-				if argument.resolved {
-					continue
-				}
-			}
-
-			object := objectMap[utils.NormalizeSuiObjectID(resolveObject.ObjectID)]
-			if object == nil {
-				return fmt.Errorf("can not fetch object with id [%s] at index %d", resolveObject.ObjectID, idx)
-			}
-
-			objectArg, err := objectResponseToObjectArg(object, resolveObject.Mutable)
-			if err != nil {
-				return fmt.Errorf("can not convert object response to object arg at index %d: %v", idx, err)
-			}
-
-			up.Arguments[idx] = &UnresolvedArgument{Object: objectArg}
-
-			if objectArg.SharedObject != nil {
-				cache.AddSharedObject(
-					&SharedObjectCacheEntry{
-						ObjectID:             &objectArg.SharedObject.Id,
-						InitialSharedVersion: &objectArg.SharedObject.InitialSharedVersion,
-					},
-				)
-			}
-		}
-	}
-
-	return nil
-}
-
-// toArguments parses all unresolved arguments to Sui types.
+// toArguments parses all unresolved arguments to Sui types, objects referenced by ID are added as inputs that are resolved at build time.
 func (up *UnresolvedParameter) toArguments(txb *Transaction) ([]sui_types.Argument, error) {
 	arguments := make([]sui_types.Argument, len(up.Arguments))
 	for idx, input := range up.Arguments {
-		if input.Pure != nil {
+		if object, ok := up.Objects[idx]; ok {
+			value, err := txb.unresolvedObjectInput(object)
+			if err != nil {
+				return nil, fmt.Errorf("can not create object argument at index %d: %v", idx, err)
+			}
+			arguments[idx] = value
+		} else if input == nil {
+			return nil, fmt.Errorf("missing input argument at index: %v", idx)
+		} else if input.Pure != nil {
 			value, err := txb.builder.Pure(input.Pure)
 			if err != nil {
 				return nil, fmt.Errorf("can not create pure argument at index %d: %v", idx, err)
 			}
 			arguments[idx] = value
 		} else if input.Object != nil {
-			value, err := txb.builder.Obj(*input.Object)
+			value, err := txb.addObjectInput(*input.Object, input.Receiving)
 			if err != nil {
 				return nil, fmt.Errorf("can not create object argument at index %d: %v", idx, err)
 			}
@@ -152,3 +71,88 @@ func (up *UnresolvedParameter) toArguments(txb *Transaction) ([]sui_types.Argume
 
 	return arguments, nil
 }
+
+// unresolvedObjectInput adds an object input that is resolved at build time, the same object is only added once.
+func (txb *Transaction) unresolvedObjectInput(object UnresolvedObject) (sui_types.Argument, error) {
+	id, err := sui_types.NewObjectIdFromHex(utils.NormalizeSuiObjectID(object.ObjectID))
+	if err != nil {
+		return sui_types.Argument{}, fmt.Errorf("invalid object id [%s]: %v", object.ObjectID, err)
+	}
+
+	key := sui_types.BuilderArg{Object: id}
+	value, ok := txb.builder.Inputs[key.String()]
+	switch {
+	case !ok:
+		txb.unresolvedObjects[id.String()] = UnresolvedObject{ObjectID: id.String(), Mutable: object.Mutable}
+		value = sui_types.CallArg{Object: &sui_types.ObjectArg{ImmOrOwnedObject: &sui_types.ObjectRef{ObjectId: *id}}}
+	case value.Object == nil:
+		return sui_types.Argument{}, fmt.Errorf("object [%s] is already used as a pure input", id.String())
+	case txb.receivingObjects[id.String()]:
+		return sui_types.Argument{}, fmt.Errorf("object [%s] is already used as a receiving input", id.String())
+	default:
+		if unresolved, ok := txb.unresolvedObjects[id.String()]; ok {
+			unresolved.Mutable = unresolved.Mutable || object.Mutable
+			txb.unresolvedObjects[id.String()] = unresolved
+		} else if shared := value.Object.SharedObject; shared != nil && object.Mutable && !shared.Mutable {
+			value = sui_types.CallArg{Object: sharedObjectArg(*id, shared.InitialSharedVersion, true)}
+		}
+	}
+
+	index := txb.builder.InsertFull(key, value)
+	return sui_types.Argument{Input: &index}, nil
+}
+
+// objectInput adds a resolved object input, replacing the input of the same object if it is still unresolved.
+func (txb *Transaction) objectInput(objectArg sui_types.ObjectArg) (sui_types.Argument, error) {
+	return txb.addObjectInput(objectArg, false)
+}
+
+// addObjectInput adds a resolved object input, receiving objects are immutable or owned references that are encoded as receiving objects.
+func (txb *Transaction) addObjectInput(objectArg sui_types.ObjectArg, receiving bool) (argument sui_types.Argument, err error) {
+	if receiving && objectArg.ImmOrOwnedObject == nil {
+		return sui_types.Argument{}, fmt.Errorf("receiving object must be an object reference")
+	}
+	defer func() {
+		if err == nil && receiving {
+			txb.receivingObjects[objectArg.ImmOrOwnedObject.ObjectId.String()] = true
+		}
+	}()
+
+	var id sui_types.ObjectID
+	switch {
+	case objectArg.ImmOrOwnedObject != nil:
+		id = objectArg.ImmOrOwnedObject.ObjectId
+	case objectArg.SharedObject != nil:
+		id = objectArg.SharedObject.Id
+	default:
+		return sui_types.Argument{}, fmt.Errorf("empty object argument")
+	}
+
+	_, exists := txb.builder.Inputs[sui_types.BuilderArg{Object: &id}.String()]
+	if exists && txb.receivingObjects[id.String()] != receiving {
+		return sui_types.Argument{}, fmt.Errorf("object [%s] can not be used both as a receiving object and as an owned or shared object", id.String())
+	}
+
+	if unresolved, ok := txb.unresolvedObjects[id.String()]; ok {
+		if shared := objectArg.SharedObject; shared != nil {
+			objectArg = *sharedObjectArg(id, shared.InitialSharedVersion, shared.Mutable || unresolved.Mutable)
+		}
+		delete(txb.unresolvedObjects, id.String())
+
+		index := txb.builder.InsertFull(sui_types.BuilderArg{Object: &id}, sui_types.CallArg{Object: &objectArg})
+		return sui_types.Argument{Input: &index}, nil
+	}
+
+	if value, ok := txb.builder.Inputs[sui_types.BuilderArg{Object: &id}.String()]; ok {
+		if value.Object == nil || (value.Object.SharedObject == nil) != (objectArg.SharedObject == nil) {
+			return sui_types.Argument{}, fmt.Errorf("mismatched object argument kind for object [%s]", id.String())
+		}
+	}
+
+	return txb.builder.Obj(objectArg)
+}
+
+// sharedObjectArg creates a shared object argument.
+func sharedObjectArg(id sui_types.ObjectID, initialSharedVersion uint64, mutable bool) *sui_types.ObjectArg {
+	return (&SharedObjectCacheEntry{ObjectID: &id, InitialSharedVersion: &initialSharedVersion}).ToObjectArg(mutable)
+}
@@ -0,0 +1,265 @@
+package transactions
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// paymentLimits defines the number of amounts split by one split coins command, the number of coins transferred by one
+// transfer objects command and the number of commands of a transaction.
+type paymentLimits struct {
+	splits    int
+	transfers int
+	commands  int
+}
+
+// newPaymentLimits returns the payment limits of the protocol limits, the utils constants are used for limits that are not set.
+// Sui nodes require fewer arguments and commands than the limits.
+func newPaymentLimits(limits *ProtocolLimits) paymentLimits {
+	arguments, commands := uint64(utils.MaxArguments), uint64(utils.MaxProgrammableTxCommands)
+	if limits != nil && limits.MaxArguments > 1 {
+		arguments = limits.MaxArguments
+	}
+	if limits != nil && limits.MaxProgrammableTxCommands > 1 {
+		commands = limits.MaxProgrammableTxCommands
+	}
+	return paymentLimits{splits: int(arguments - 1), transfers: int(arguments - 1), commands: int(commands - 1)}
+}
+
+// paymentCommands counts the commands of payments.
+type paymentCommands struct {
+	limits    paymentLimits
+	payments  int
+	groups    map[sui_types.SuiAddress]int // number of payments of each address
+	transfers int
+}
+
+func newPaymentCommands(limits paymentLimits) *paymentCommands {
+	return &paymentCommands{limits: limits, groups: make(map[sui_types.SuiAddress]int)}
+}
+
+// add adds a payment to the address.
+func (c *paymentCommands) add(address sui_types.SuiAddress) {
+	if c.groups[address]%c.limits.transfers == 0 {
+		c.transfers++
+	}
+	c.groups[address]++
+	c.payments++
+}
+
+// count returns the number of commands of the payments, intents counts the commands of the coin intents used by Pay.
+func (c *paymentCommands) count(intents bool) int {
+	if !intents {
+		return (c.payments+c.limits.splits-1)/c.limits.splits + c.transfers
+	}
+	// each coin intent adds a split coins command, its amounts are split by another command and the coins are merged once
+	chunks := (c.payments + c.limits.splits) / (c.limits.splits + 1)
+	return 2*chunks + 1 + c.transfers
+}
+
+// checkPaymentCommands checks that the payments fit in the transaction before they are added.
+func (txb *Transaction) checkPaymentCommands(addresses []sui_types.SuiAddress, limits paymentLimits, intents bool) error {
+	commands := newPaymentCommands(limits)
+	for _, address := range addresses {
+		commands.add(address)
+	}
+	if total := len(txb.builder.Commands) + commands.count(intents); total > limits.commands {
+		return fmt.Errorf("payments need %d commands, exceeding the limit of %d commands, the payments can be split with SplitPayments", total, limits.commands)
+	}
+	return nil
+}
+
+// SplitPayments splits payments into batches that each fit in one transaction paid with PaySui or Pay, the limits of the
+// utils constants are used when limits is nil. A single transaction pays about a thousand different recipients at most.
+func SplitPayments(recipients []string, amounts []uint64, limits *ProtocolLimits) ([][]string, [][]uint64, error) {
+	addresses, err := parsePayments(recipients, amounts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paymentLimits := newPaymentLimits(limits)
+	var batchRecipients [][]string
+	var batchAmounts [][]uint64
+	start, commands := 0, newPaymentCommands(paymentLimits)
+	for idx, address := range addresses {
+		commands.add(address)
+		if commands.count(true) <= paymentLimits.commands {
+			continue
+		}
+		batchRecipients, batchAmounts = append(batchRecipients, recipients[start:idx]), append(batchAmounts, amounts[start:idx])
+		start, commands = idx, newPaymentCommands(paymentLimits)
+		commands.add(address)
+	}
+	return append(batchRecipients, recipients[start:]), append(batchAmounts, amounts[start:]), nil
+}
+
+// PaySui splits the amounts from the gas coin and transfers the i-th amount to the i-th recipient.
+// The amounts are split with one command per max_arguments-1 amounts of the protocol limits set on the transaction, or
+// utils.MaxArguments-1 amounts, and the coins of a recipient are transferred together. Payments that need more commands than
+// a transaction can hold return an error without changing the transaction, see SplitPayments.
+// A gas payment selected when building covers the gas budget and the amounts.
+func (txb *Transaction) PaySui(recipients []string, amounts []uint64) (err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	addresses, err := parsePayments(recipients, amounts)
+	if err != nil {
+		return err
+	}
+	limits := newPaymentLimits(txb.protocolLimits)
+	if err := txb.checkPaymentCommands(addresses, limits, false); err != nil {
+		return err
+	}
+
+	coins := make([]Arg, 0, len(amounts))
+	for start := 0; start < len(amounts); start += limits.splits {
+		chunk := amounts[start:min(start+limits.splits, len(amounts))]
+		result, err := txb.AddSplitCoins(txb.Gas(), pureAmounts(chunk))
+		if err != nil {
+			return err
+		}
+		for idx := range chunk {
+			coins = append(coins, result.Nested(uint16(idx)))
+		}
+	}
+
+	return txb.transferCoins(addresses, coins, limits)
+}
+
+// Pay transfers the i-th amount of coinType to the i-th recipient. The coins are selected from the coins of the sender
+// when the transaction is built, with one coin intent per max_arguments amounts of the protocol limits set on the transaction,
+// or utils.MaxArguments amounts. SUI is paid with PaySui.
+func (txb *Transaction) Pay(coinType string, recipients []string, amounts []uint64) (err error) {
+	if utils.NormalizeSuiCoinType(coinType) == utils.SuiTypeArg {
+		return txb.PaySui(recipients, amounts)
+	}
+
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	addresses, err := parsePayments(recipients, amounts)
+	if err != nil {
+		return err
+	}
+	limits := newPaymentLimits(txb.protocolLimits)
+	if err := txb.checkPaymentCommands(addresses, limits, true); err != nil {
+		return err
+	}
+
+	coins := make([]Arg, 0, len(amounts))
+	for start := 0; start < len(amounts); start += limits.splits + 1 {
+		chunk := amounts[start:min(start+limits.splits+1, len(amounts))]
+		var total uint64
+		for _, amount := range chunk {
+			if total > math.MaxUint64-amount {
+				return fmt.Errorf("total amount of payments overflows u64")
+			}
+			total += amount
+		}
+
+		// the intent coin keeps the first amount after the other amounts are split from it
+		coin, err := txb.CoinWithBalance(coinType, total)
+		if err != nil {
+			return err
+		}
+		coins = append(coins, coin)
+		if len(chunk) == 1 {
+			continue
+		}
+
+		result, err := txb.AddSplitCoins(coin, pureAmounts(chunk[1:]))
+		if err != nil {
+			return err
+		}
+		for idx := range chunk[1:] {
+			coins = append(coins, result.Nested(uint16(idx)))
+		}
+	}
+
+	return txb.transferCoins(addresses, coins, limits)
+}
+
+// PayAllSui transfers the gas coin to the recipient and selects all SUI coins of the gas owner as gas payment,
+// so the recipient receives the whole SUI balance minus the gas fee.
+func (txb *Transaction) PayAllSui(recipient string) error {
+	if err := txb.TransferSui(recipient, nil); err != nil {
+		return err
+	}
+
+	txb.SetCoinSelectionStrategy(AllCoins)
+	return nil
+}
+
+// TransferSui transfers the amount split from the gas coin to the recipient, or the gas coin itself when the amount is nil.
+func (txb *Transaction) TransferSui(recipient string, amount *uint64) error {
+	address, err := sui_types.NewAddressFromHex(recipient)
+	if err != nil {
+		return fmt.Errorf("invalid recipient [%s]: %v", recipient, err)
+	}
+
+	if amount == nil {
+		return txb.AddTransferObjects([]Arg{txb.Gas()}, Pure(*address))
+	}
+	return txb.PaySui([]string{recipient}, []uint64{*amount})
+}
+
+// transferCoins transfers each coin to the address at the same index, the coins of an address are transferred by one command.
+func (txb *Transaction) transferCoins(addresses []sui_types.SuiAddress, coins []Arg, limits paymentLimits) error {
+	var order []sui_types.SuiAddress
+	grouped := make(map[sui_types.SuiAddress][]Arg)
+	for idx, address := range addresses {
+		if _, ok := grouped[address]; !ok {
+			order = append(order, address)
+		}
+		grouped[address] = append(grouped[address], coins[idx])
+	}
+
+	for _, address := range order {
+		group := grouped[address]
+		for start := 0; start < len(group); start += limits.transfers {
+			if err := txb.AddTransferObjects(group[start:min(start+limits.transfers, len(group))], Pure(address)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parsePayments validates that every recipient has an amount and returns the addresses of the recipients.
+func parsePayments(recipients []string, amounts []uint64) ([]sui_types.SuiAddress, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("missing recipients")
+	}
+	if len(recipients) != len(amounts) {
+		return nil, fmt.Errorf("recipients and amounts must have the same length, got %d recipients and %d amounts", len(recipients), len(amounts))
+	}
+
+	addresses := make([]sui_types.SuiAddress, len(recipients))
+	for idx, recipient := range recipients {
+		address, err := sui_types.NewAddressFromHex(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient [%s] at index %d: %v", recipient, idx, err)
+		}
+		addresses[idx] = *address
+	}
+	return addresses, nil
+}
+
+func pureAmounts(amounts []uint64) []Arg {
+	args := make([]Arg, len(amounts))
+	for idx, amount := range amounts {
+		args[idx] = Pure(amount)
+	}
+	return args
+}
@@ -0,0 +1,240 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+)
+
+func recipients(count int) []string {
+	addresses := make([]string, count)
+	for idx := range addresses {
+		addresses[idx] = fmt.Sprintf("0x%x", 0x32000+idx)
+	}
+	return addresses
+}
+
+func amounts(count int) []uint64 {
+	values := make([]uint64, count)
+	for idx := range values {
+		values[idx] = uint64(idx + 1)
+	}
+	return values
+}
+
+func TestPaySui(t *testing.T) {
+	tests := []struct {
+		name       string
+		recipients []string
+		amounts    []uint64
+		limits     *transactions.ProtocolLimits
+		splits     []int // number of amounts of each split coins command
+		transfers  []int // number of coins of each transfer objects command
+	}{
+		{name: "one recipient", recipients: recipients(1), amounts: amounts(1), splits: []int{1}, transfers: []int{1}},
+		{name: "repeated recipient", recipients: []string{"0x1", "0x2", "0x1"}, amounts: amounts(3), splits: []int{3}, transfers: []int{2, 1}},
+		{name: "chunked amounts", recipients: recipients(600), amounts: amounts(600), splits: []int{511, 89}, transfers: repeat(1, 600)},
+		{
+			name:       "protocol limits",
+			recipients: []string{"0x1", "0x1", "0x1", "0x1", "0x2", "0x2", "0x2"},
+			amounts:    amounts(7),
+			limits:     &transactions.ProtocolLimits{MaxArguments: 4},
+			splits:     []int{3, 3, 1},
+			transfers:  []int{3, 1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := transactions.NewTransaction(nil)
+			tx.SetProtocolLimits(tt.limits)
+			if err := tx.PaySui(tt.recipients, tt.amounts); err != nil {
+				t.Fatalf("failed to pay sui: %v", err)
+			}
+
+			commands := tx.TransactionBuilder().Commands
+			if len(commands) != len(tt.splits)+len(tt.transfers) {
+				t.Fatalf("expected %d commands, but got %d", len(tt.splits)+len(tt.transfers), len(commands))
+			}
+			for idx, count := range tt.splits {
+				split := commands[idx].SplitCoins
+				if split == nil || split.Argument.GasCoin == nil || len(split.Arguments) != count {
+					t.Errorf("expected split of %d amounts from gas in command %d, but got %+v", count, idx, commands[idx])
+				}
+			}
+			for idx, count := range tt.transfers {
+				transfer := commands[len(tt.splits)+idx].TransferObjects
+				if transfer == nil || len(transfer.Arguments) != count {
+					t.Errorf("expected transfer of %d coins in command %d, but got %+v", count, len(tt.splits)+idx, commands[len(tt.splits)+idx])
+				}
+			}
+		})
+	}
+}
+
+func repeat(value, count int) []int {
+	values := make([]int, count)
+	for idx := range values {
+		values[idx] = value
+	}
+	return values
+}
+
+func TestPaySuiErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		recipients []string
+		amounts    []uint64
+		limits     *transactions.ProtocolLimits
+	}{
+		{name: "missing recipients"},
+		{name: "mismatched lengths", recipients: recipients(2), amounts: amounts(1)},
+		{name: "invalid recipient", recipients: []string{"0x1", "recipient"}, amounts: amounts(2)},
+		{name: "too many commands", recipients: recipients(1100), amounts: amounts(1100)},
+		// one split and three transfers fit in fewer than 5 commands, another recipient needs a second split
+		{name: "commands of protocol limits", recipients: recipients(4), amounts: amounts(4), limits: &transactions.ProtocolLimits{MaxArguments: 4, MaxProgrammableTxCommands: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := transactions.NewTransaction(nil)
+			tx.SetProtocolLimits(tt.limits)
+			if err := tx.PaySui(tt.recipients, tt.amounts); err == nil {
+				t.Fatalf("expected an error, but got nil")
+			}
+			if builder := tx.TransactionBuilder(); len(builder.Commands) != 0 || len(builder.InputsKeyOrder) != 0 {
+				t.Errorf("expected an empty transaction, but got %d commands and %d inputs", len(builder.Commands), len(builder.InputsKeyOrder))
+			}
+		})
+	}
+}
+
+func TestSplitPayments(t *testing.T) {
+	limits := &transactions.ProtocolLimits{MaxArguments: 4, MaxProgrammableTxCommands: 5}
+	tx := transactions.NewTransaction(nil)
+	tx.SetProtocolLimits(limits)
+	if err := tx.PaySui(recipients(3), amounts(3)); err != nil {
+		t.Fatalf("expected payments at the command limit to fit, but got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		count   int
+		limits  *transactions.ProtocolLimits
+		batches int
+	}{
+		{name: "one batch", count: 3, batches: 1},
+		{name: "default limits", count: 2500, batches: 3},
+		{name: "protocol limits", count: 10, limits: limits, batches: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchRecipients, batchAmounts, err := transactions.SplitPayments(recipients(tt.count), amounts(tt.count), tt.limits)
+			if err != nil {
+				t.Fatalf("failed to split payments: %v", err)
+			}
+			if len(batchRecipients) != tt.batches || len(batchAmounts) != tt.batches {
+				t.Fatalf("expected %d batches, but got %d", tt.batches, len(batchRecipients))
+			}
+
+			var paid int
+			for idx := range batchRecipients {
+				for _, pay := range []func(tx *transactions.Transaction) error{
+					func(tx *transactions.Transaction) error { return tx.PaySui(batchRecipients[idx], batchAmounts[idx]) },
+					func(tx *transactions.Transaction) error { return tx.Pay(usdc, batchRecipients[idx], batchAmounts[idx]) },
+				} {
+					tx := transactions.NewTransaction(nil)
+					tx.SetProtocolLimits(tt.limits)
+					if err := pay(tx); err != nil {
+						t.Fatalf("failed to pay batch %d: %v", idx, err)
+					}
+				}
+				if batchAmounts[idx][0] != uint64(paid+1) {
+					t.Errorf("expected batch %d to start with payment %d, but got %d", idx, paid+1, batchAmounts[idx][0])
+				}
+				paid += len(batchRecipients[idx])
+			}
+			if paid != tt.count {
+				t.Errorf("expected %d payments in the batches, but got %d", tt.count, paid)
+			}
+		})
+	}
+
+	if _, _, err := transactions.SplitPayments(recipients(2), amounts(1), nil); err == nil {
+		t.Errorf("expected an error for mismatched lengths, but got nil")
+	}
+}
+
+func TestPay(t *testing.T) {
+	coins := newTypedCoins(usdc, 30, 50)
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){"suix_getCoins": getCoinsByType(map[string][]types.CoinStruct{usdc: coins})})
+
+	tx := transactions.NewTransaction(suiClient)
+	tx.SetSender(recipient)
+	if err := tx.Pay(usdc, []string{"0x1", "0x2"}, []uint64{10, 20}); err != nil {
+		t.Fatalf("failed to pay: %v", err)
+	}
+	if _, err := tx.BuildKind(context.Background()); err != nil {
+		t.Fatalf("failed to build transaction kind: %v", err)
+	}
+
+	// split of the total from the selected coin, split of the second amount, two transfers
+	commands := tx.TransactionBuilder().Commands
+	if len(commands) != 4 {
+		t.Fatalf("expected 4 commands, but got %d", len(commands))
+	}
+	if split := commands[0].SplitCoins; split == nil || split.Argument.Input == nil {
+		t.Errorf("expected split from the selected coin, but got %+v", commands[0])
+	}
+	if index, _, ok := nestedResult(commands[1].SplitCoins.Argument); !ok || index != 0 {
+		t.Errorf("expected split from the intent coin, but got %+v", commands[1].SplitCoins.Argument)
+	}
+	for idx, expected := range []uint16{0, 1} {
+		if index, _, ok := nestedResult(commands[2+idx].TransferObjects.Arguments[0]); !ok || index != expected {
+			t.Errorf("expected transfer of the result of command %d, but got %+v", expected, commands[2+idx].TransferObjects.Arguments[0])
+		}
+	}
+}
+
+func TestPayAllSui(t *testing.T) {
+	coins := newCoins(100, 200, 300)
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){"suix_getCoins": getCoins(coins)})
+
+	tx := transactions.NewTransaction(suiClient)
+	if err := tx.PayAllSui("0x1"); err != nil {
+		t.Fatalf("failed to pay all sui: %v", err)
+	}
+	tx.SetGasPrice(1000)
+	tx.SetGasBudget(100)
+
+	data, _, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	if payment := data.V1.GasData.Payment; len(payment) != 3 {
+		t.Errorf("expected gas payment with 3 coins, but got %v", payment)
+	}
+	if commands := data.V1.Kind.ProgrammableTransaction.Commands; len(commands) != 1 || commands[0].TransferObjects.Arguments[0].GasCoin == nil {
+		t.Errorf("expected transfer of the gas coin, but got %+v", commands)
+	}
+}
+
+func TestTransferSui(t *testing.T) {
+	amount := uint64(100)
+	tx := transactions.NewTransaction(nil)
+	if err := tx.TransferSui("0x1", &amount); err != nil {
+		t.Fatalf("failed to transfer sui: %v", err)
+	}
+
+	commands := tx.TransactionBuilder().Commands
+	if len(commands) != 2 || commands[0].SplitCoins == nil || commands[1].TransferObjects == nil {
+		t.Errorf("expected split and transfer commands, but got %+v", commands)
+	}
+	if err := tx.TransferSui("recipient", &amount); err == nil {
+		t.Errorf("expected an invalid recipient error, but got nil")
+	}
+}
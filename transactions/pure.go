@@ -0,0 +1,243 @@
+package transactions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/utils"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+// unresolvedPureInput adds a pure input whose type is not known yet, the JSON value is encoded when the transaction is built.
+func (txb *Transaction) unresolvedPureInput(value json.RawMessage) sui_types.Argument {
+	argument := txb.builder.PureBytes([]byte{}, true)
+	txb.unresolvedPures[*argument.Input] = value
+	return argument
+}
+
+// resolvePureValues encodes the pure values of unknown type with the types of the parameters they are passed to.
+// Move calls are resolved with their signatures, split amounts are u64 values and recipients are addresses.
+func (txb *Transaction) resolvePureValues(ctx context.Context) error {
+	if len(txb.unresolvedPures) == 0 {
+		return nil
+	}
+
+	address, u64 := &move_types.TypeTag{Address: &lib.EmptyEnum{}}, &move_types.TypeTag{U64: &lib.EmptyEnum{}}
+	typeTags := make(map[uint16]*move_types.TypeTag)
+	setType := func(argument sui_types.Argument, typeTag *move_types.TypeTag) {
+		if argument.Input == nil || typeTags[*argument.Input] != nil {
+			return
+		}
+		if _, ok := txb.unresolvedPures[*argument.Input]; ok {
+			typeTags[*argument.Input] = typeTag
+		}
+	}
+
+	fetched := make(map[string]bool)
+	for idx, command := range txb.builder.Commands {
+		switch {
+		case command.MoveCall != nil:
+			if !slices.ContainsFunc(command.MoveCall.Arguments, txb.isUnresolvedPure) {
+				continue
+			}
+			parameterTypes, err := txb.moveCallParameterTypes(ctx, command.MoveCall, fetched)
+			if err != nil {
+				return fmt.Errorf("can not resolve pure values of command %d: %v", idx, err)
+			}
+			for i, argument := range command.MoveCall.Arguments {
+				setType(argument, parameterTypes[i])
+			}
+		case command.SplitCoins != nil:
+			for _, argument := range command.SplitCoins.Arguments {
+				setType(argument, u64)
+			}
+		case command.TransferObjects != nil:
+			setType(command.TransferObjects.Argument, address)
+		}
+	}
+
+	for _, index := range slices.Sorted(maps.Keys(txb.unresolvedPures)) {
+		typeTag := typeTags[index]
+		if typeTag == nil {
+			return fmt.Errorf("can not infer the type of pure input %d, it is not passed to a move call, split or transfer", index)
+		}
+		if !isPureType(typeTag) {
+			return fmt.Errorf("pure input %d is passed to a parameter of non-pure type [%s]", index, FormatTypeTag(*typeTag))
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(txb.unresolvedPures[index]))
+		decoder.UseNumber()
+		var value any
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("invalid value of pure input %d: %v", index, err)
+		}
+		var buf bytes.Buffer
+		if err := encodePureValue(&buf, typeTag, value); err != nil {
+			return fmt.Errorf("can not encode pure input %d as [%s]: %v", index, FormatTypeTag(*typeTag), err)
+		}
+
+		bs := buf.Bytes()
+		txb.builder.Inputs[txb.builder.InputsKeyOrder[index].String()] = sui_types.CallArg{Pure: &bs}
+	}
+
+	txb.unresolvedPures = make(map[uint16]json.RawMessage)
+	return nil
+}
+
+// isUnresolvedPure reports whether the argument is a pure input whose type is not known yet.
+func (txb *Transaction) isUnresolvedPure(argument sui_types.Argument) bool {
+	if argument.Input == nil {
+		return false
+	}
+	_, ok := txb.unresolvedPures[*argument.Input]
+	return ok
+}
+
+// moveCallParameterTypes returns the types of the parameters of a Move call, the package is fetched once when the signature is not cached.
+func (txb *Transaction) moveCallParameterTypes(ctx context.Context, moveCall *sui_types.ProgrammableMoveCall, fetched map[string]bool) ([]*move_types.TypeTag, error) {
+	pkg, target := moveCall.Package.String(), fmt.Sprintf("%s::%s::%s", moveCall.Package.String(), moveCall.Module, moveCall.Function)
+	entry := txb.Cache().GetMoveFunctionDefinition(pkg, string(moveCall.Module), string(moveCall.Function))
+	if entry == nil && !fetched[pkg] {
+		if txb.client == nil {
+			return nil, fmt.Errorf("missing sui client to get normalized move modules of package [%s]", pkg)
+		}
+		if err := cacheNormalizedMoveModules(ctx, txb.client, txb.Cache(), pkg); err != nil {
+			return nil, fmt.Errorf("can not get normalized move modules of package [%s]: %v", pkg, err)
+		}
+		fetched[pkg] = true
+		entry = txb.Cache().GetMoveFunctionDefinition(pkg, string(moveCall.Module), string(moveCall.Function))
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("function [%s] does not exist", target)
+	}
+
+	parameters := moveFunctionParameters(entry.Normalized)
+	if len(moveCall.Arguments) != len(parameters) || len(moveCall.TypeArguments) != len(entry.Normalized.TypeParameters) {
+		return nil, fmt.Errorf("incorrect number of arguments or type arguments, function [%s] has %d parameters and %d type parameters", target, len(parameters), len(entry.Normalized.TypeParameters))
+	}
+
+	parameterTypes := make([]*move_types.TypeTag, len(parameters))
+	for i, parameter := range parameters {
+		typeTag, _, err := parameterType(parameter.SuiMoveNormalizedType, moveCall.TypeArguments)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter %d of function [%s]: %v", i, target, err)
+		}
+		parameterTypes[i] = typeTag
+	}
+	return parameterTypes, nil
+}
+
+// encodePureValue encodes a JSON value decoded with UseNumber as a BCS value of a pure type. Integers are numbers or
+// decimal strings, addresses and IDs are hex strings and options are null or their value.
+func encodePureValue(buf *bytes.Buffer, typeTag *move_types.TypeTag, value any) error {
+	switch {
+	case typeTag.Bool != nil:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected a bool, got %T", value)
+		}
+		if b {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case typeTag.U8 != nil:
+		return encodeInteger(buf, value, 1)
+	case typeTag.U16 != nil:
+		return encodeInteger(buf, value, 2)
+	case typeTag.U32 != nil:
+		return encodeInteger(buf, value, 4)
+	case typeTag.U64 != nil:
+		return encodeInteger(buf, value, 8)
+	case typeTag.U128 != nil:
+		return encodeInteger(buf, value, 16)
+	case typeTag.U256 != nil:
+		return encodeInteger(buf, value, 32)
+	case typeTag.Address != nil:
+		return encodeAddress(buf, value)
+	case typeTag.Vector != nil:
+		values, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected an array, got %T", value)
+		}
+		buf.Write(bcs.ULEB128Encode(len(values)))
+		for idx, element := range values {
+			if err := encodePureValue(buf, typeTag.Vector, element); err != nil {
+				return fmt.Errorf("invalid element %d: %v", idx, err)
+			}
+		}
+	case typeTag.Struct != nil:
+		switch structName(typeTag.Struct) {
+		case stringStruct, asciiStringStruct:
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("expected a string, got %T", value)
+			}
+			if structName(typeTag.Struct) == asciiStringStruct && strings.IndexFunc(s, func(r rune) bool { return r > unicode.MaxASCII }) >= 0 {
+				return fmt.Errorf("string [%s] is not ascii", s)
+			}
+			buf.Write(bcs.ULEB128Encode(len(s)))
+			buf.WriteString(s)
+		case idStruct:
+			return encodeAddress(buf, value)
+		case optionStruct:
+			if value == nil {
+				buf.WriteByte(0)
+				return nil
+			}
+			buf.WriteByte(1)
+			return encodePureValue(buf, &typeTag.Struct.TypeParams[0], value)
+		default:
+			return fmt.Errorf("unsupported type [%s]", FormatTypeTag(*typeTag))
+		}
+	default:
+		return fmt.Errorf("unsupported type [%s]", FormatTypeTag(*typeTag))
+	}
+	return nil
+}
+
+// encodeInteger encodes a number or decimal string as a little-endian unsigned integer of n bytes.
+func encodeInteger(buf *bytes.Buffer, value any, n int) error {
+	var s string
+	switch value := value.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = value
+	default:
+		return fmt.Errorf("expected an integer, got %T", value)
+	}
+
+	integer, ok := new(big.Int).SetString(s, 10)
+	if !ok || integer.Sign() < 0 || integer.BitLen() > n*8 {
+		return fmt.Errorf("[%s] is not a %d-bit unsigned integer", s, n*8)
+	}
+	bs := integer.FillBytes(make([]byte, n))
+	slices.Reverse(bs)
+	buf.Write(bs)
+	return nil
+}
+
+// encodeAddress encodes a hex string as an address.
+func encodeAddress(buf *bytes.Buffer, value any) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected an address, got %T", value)
+	}
+	address, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(s))
+	if err != nil {
+		return fmt.Errorf("invalid address [%s]: %v", s, err)
+	}
+	buf.Write(address[:])
+	return nil
+}
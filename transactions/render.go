@@ -0,0 +1,445 @@
+package transactions
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+)
+
+// programmableView defines the inputs and commands of a programmable transaction to render, with the inputs and
+// commands that are only known by the builder before it is built.
+type programmableView struct {
+	inputs     []sui_types.CallArg
+	commands   []sui_types.Command
+	receiving  map[string]bool            // map key is normalized object id
+	unresolved map[string]bool            // map key is normalized object id
+	pures      map[uint16]json.RawMessage // map key is the index of the input
+	intents    map[uint16]coinIntent      // map key is the index of the placeholder command
+	cache      Cache
+}
+
+// argumentUsage defines the inputs and results used by the commands, a nil set of nested results means the whole result is used.
+type argumentUsage struct {
+	gas     bool
+	inputs  map[uint16]bool
+	results map[uint16]map[uint16]bool
+}
+
+// FormatProgrammableTransaction returns a built or decoded programmable transaction as readable text. Pure inputs are
+// decoded with the types of the parameters they are passed to, function signatures are looked up in the default cache.
+// Receiving objects are only known by the builder, decode the bytes with FromBytes to render them.
+func FormatProgrammableTransaction(pt *sui_types.ProgrammableTransaction) string {
+	return newProgrammableView(pt).text()
+}
+
+// ProgrammableTransactionDOT returns the dataflow between the inputs and commands of a built or decoded programmable
+// transaction as a Graphviz DOT graph, unused inputs and results are highlighted.
+func ProgrammableTransactionDOT(pt *sui_types.ProgrammableTransaction) string {
+	return newProgrammableView(pt).dot()
+}
+
+// String returns the inputs and commands of the transaction as readable text, objects and coins that are resolved when
+// the transaction is built are marked as unresolved.
+func (txb *Transaction) String() string {
+	return txb.view().text()
+}
+
+// DOT returns the dataflow between the inputs and commands of the transaction as a Graphviz DOT graph, unused inputs and
+// results are highlighted.
+func (txb *Transaction) DOT() string {
+	return txb.view().dot()
+}
+
+func newProgrammableView(pt *sui_types.ProgrammableTransaction) *programmableView {
+	return &programmableView{inputs: pt.Inputs, commands: pt.Commands, cache: DefaultCache()}
+}
+
+func (txb *Transaction) view() *programmableView {
+	view := &programmableView{
+		commands:   txb.builder.Commands,
+		receiving:  txb.receivingObjects,
+		unresolved: make(map[string]bool, len(txb.unresolvedObjects)),
+		pures:      txb.unresolvedPures,
+		intents:    txb.intents,
+		cache:      txb.Cache(),
+	}
+	for _, key := range txb.builder.InputsKeyOrder {
+		view.inputs = append(view.inputs, txb.builder.Inputs[key.String()])
+	}
+	for id := range txb.unresolvedObjects {
+		view.unresolved[id] = true
+	}
+	return view
+}
+
+// text renders every input and command on its own line, unused inputs and results are marked.
+func (v *programmableView) text() string {
+	usage, pureTypes := v.usage(), v.pureTypes()
+
+	var sb strings.Builder
+	sb.WriteString("Inputs:\n")
+	for idx := range v.inputs {
+		fmt.Fprintf(&sb, "  Input(%d): %s", idx, v.formatInput(uint16(idx), pureTypes))
+		if !usage.inputs[uint16(idx)] {
+			sb.WriteString(" (unused)")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Commands:\n")
+	for idx := range v.commands {
+		fmt.Fprintf(&sb, "  Result(%d) = %s", idx, v.formatCommand(uint16(idx)))
+		if unused := v.unusedResults(usage, uint16(idx)); len(unused) > 0 {
+			fmt.Fprintf(&sb, " (unused %s)", strings.Join(unused, ", "))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// dot renders inputs and commands as nodes and the arguments as edges from the input or command they refer to.
+func (v *programmableView) dot() string {
+	usage, pureTypes := v.usage(), v.pureTypes()
+
+	var sb strings.Builder
+	sb.WriteString("digraph ProgrammableTransaction {\n")
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	if usage.gas {
+		sb.WriteString("\tgas [label=\"GasCoin\", shape=ellipse];\n")
+	}
+	for idx := range v.inputs {
+		attributes := ""
+		if !usage.inputs[uint16(idx)] {
+			attributes = `, color=red, style=dashed, xlabel="unused"`
+		}
+		fmt.Fprintf(&sb, "\tinput%d [label=%s, shape=ellipse%s];\n", idx, dotQuote(fmt.Sprintf("Input(%d)\n%s", idx, v.formatInput(uint16(idx), pureTypes))), attributes)
+	}
+	for idx := range v.commands {
+		attributes := ""
+		if unused := v.unusedResults(usage, uint16(idx)); len(unused) > 0 {
+			attributes = ", color=red, xlabel=" + dotQuote("unused "+strings.Join(unused, ", "))
+		}
+		fmt.Fprintf(&sb, "\tcommand%d [label=%s%s];\n", idx, dotQuote(fmt.Sprintf("Result(%d)\n%s", idx, v.commandName(uint16(idx)))), attributes)
+	}
+	for idx := range v.commands {
+		for _, argument := range commandArguments(v.commands[idx]) {
+			switch {
+			case argument.GasCoin != nil:
+				fmt.Fprintf(&sb, "\tgas -> command%d;\n", idx)
+			case argument.Input != nil:
+				fmt.Fprintf(&sb, "\tinput%d -> command%d;\n", *argument.Input, idx)
+			case argument.Result != nil:
+				fmt.Fprintf(&sb, "\tcommand%d -> command%d;\n", *argument.Result, idx)
+			case argument.NestedResult != nil:
+				fmt.Fprintf(&sb, "\tcommand%d -> command%d [label=\"%d\"];\n", argument.NestedResult.Result1, idx, argument.NestedResult.Result2)
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// usage returns the inputs and results used as arguments of the commands.
+func (v *programmableView) usage() argumentUsage {
+	usage := argumentUsage{inputs: make(map[uint16]bool), results: make(map[uint16]map[uint16]bool)}
+	for _, command := range v.commands {
+		for _, argument := range commandArguments(command) {
+			switch {
+			case argument.GasCoin != nil:
+				usage.gas = true
+			case argument.Input != nil:
+				usage.inputs[*argument.Input] = true
+			case argument.Result != nil:
+				usage.results[*argument.Result] = nil
+			case argument.NestedResult != nil:
+				nested, ok := usage.results[argument.NestedResult.Result1]
+				if ok && nested == nil {
+					continue
+				}
+				if nested == nil {
+					nested = make(map[uint16]bool)
+					usage.results[argument.NestedResult.Result1] = nested
+				}
+				nested[argument.NestedResult.Result2] = true
+			}
+		}
+	}
+	return usage
+}
+
+// unusedResults returns the results of a command that are not used by later commands, nothing is returned when the
+// number of results is not known.
+func (v *programmableView) unusedResults(usage argumentUsage, idx uint16) []string {
+	count, ok := v.resultCount(idx)
+	if !ok || count == 0 {
+		return nil
+	}
+	nested, used := usage.results[idx]
+	if used && nested == nil {
+		return nil
+	}
+
+	var unused []string
+	for i := 0; i < count; i++ {
+		if !nested[uint16(i)] {
+			unused = append(unused, fmt.Sprintf("Result(%d.%d)", idx, i))
+		}
+	}
+	if count == 1 && len(unused) == 1 {
+		return []string{fmt.Sprintf("Result(%d)", idx)}
+	}
+	return unused
+}
+
+// resultCount returns the number of values returned by a command, the results of Move calls are known when the
+// signature of the function is cached.
+func (v *programmableView) resultCount(idx uint16) (int, bool) {
+	if _, ok := v.intents[idx]; ok {
+		return 1, true
+	}
+	command := v.commands[idx]
+	switch {
+	case command.MoveCall != nil:
+		entry := v.cache.GetMoveFunctionDefinition(command.MoveCall.Package.String(), string(command.MoveCall.Module), string(command.MoveCall.Function))
+		if entry == nil {
+			return 0, false
+		}
+		return len(entry.Normalized.Return), true
+	case command.SplitCoins != nil:
+		return len(command.SplitCoins.Arguments), true
+	case command.MakeMoveVec != nil, command.Publish != nil, command.Upgrade != nil:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// commandName returns the kind of a command with the function of Move calls and the type of vectors.
+func (v *programmableView) commandName(idx uint16) string {
+	if intent, ok := v.intents[idx]; ok {
+		return fmt.Sprintf("CoinWithBalance<%s>(%d) (unresolved)", intent.CoinType, intent.Amount)
+	}
+	command := v.commands[idx]
+	switch {
+	case command.MoveCall != nil:
+		moveCall := command.MoveCall
+		name := fmt.Sprintf("MoveCall %s::%s::%s", moveCall.Package.String(), moveCall.Module, moveCall.Function)
+		if len(moveCall.TypeArguments) > 0 {
+			typeArguments := make([]string, len(moveCall.TypeArguments))
+			for i, typeArgument := range moveCall.TypeArguments {
+				typeArguments[i] = FormatTypeTag(typeArgument)
+			}
+			name += "<" + strings.Join(typeArguments, ", ") + ">"
+		}
+		return name
+	case command.TransferObjects != nil:
+		return "TransferObjects"
+	case command.SplitCoins != nil:
+		return "SplitCoins"
+	case command.MergeCoins != nil:
+		return "MergeCoins"
+	case command.MakeMoveVec != nil:
+		if command.MakeMoveVec.TypeTag != nil {
+			return "MakeMoveVec<" + FormatTypeTag(*command.MakeMoveVec.TypeTag) + ">"
+		}
+		return "MakeMoveVec"
+	case command.Publish != nil:
+		return fmt.Sprintf("Publish(%d modules)", len(command.Publish.Bytes))
+	case command.Upgrade != nil:
+		return fmt.Sprintf("Upgrade %s", command.Upgrade.ObjectID.String())
+	default:
+		return "Unknown"
+	}
+}
+
+// formatCommand returns a command with its arguments, lists of arguments are enclosed in brackets.
+func (v *programmableView) formatCommand(idx uint16) string {
+	name := v.commandName(idx)
+	if _, ok := v.intents[idx]; ok {
+		return name
+	}
+
+	list := func(arguments []sui_types.Argument) string {
+		formatted := make([]string, len(arguments))
+		for i, argument := range arguments {
+			formatted[i] = formatArgument(argument)
+		}
+		return "[" + strings.Join(formatted, ", ") + "]"
+	}
+	command := v.commands[idx]
+	switch {
+	case command.MoveCall != nil:
+		arguments := list(command.MoveCall.Arguments)
+		return name + "(" + arguments[1:len(arguments)-1] + ")"
+	case command.TransferObjects != nil:
+		return fmt.Sprintf("%s(%s, %s)", name, list(command.TransferObjects.Arguments), formatArgument(command.TransferObjects.Argument))
+	case command.SplitCoins != nil:
+		return fmt.Sprintf("%s(%s, %s)", name, formatArgument(command.SplitCoins.Argument), list(command.SplitCoins.Arguments))
+	case command.MergeCoins != nil:
+		return fmt.Sprintf("%s(%s, %s)", name, formatArgument(command.MergeCoins.Argument), list(command.MergeCoins.Arguments))
+	case command.MakeMoveVec != nil:
+		return fmt.Sprintf("%s(%s)", name, list(command.MakeMoveVec.Arguments))
+	case command.Upgrade != nil:
+		return fmt.Sprintf("%s(%s)", name, formatArgument(command.Upgrade.Argument))
+	default:
+		return name
+	}
+}
+
+// formatInput returns the kind of an input with the version of objects and the decoded value of pure inputs of known type.
+func (v *programmableView) formatInput(idx uint16, pureTypes map[uint16]*move_types.TypeTag) string {
+	if value, ok := v.pures[idx]; ok {
+		return fmt.Sprintf("Pure %s (unresolved)", string(value))
+	}
+
+	input := v.inputs[idx]
+	switch {
+	case input.Pure != nil:
+		if typeTag := pureTypes[idx]; typeTag != nil {
+			if value, err := DefaultTypeRegistry().Decode(*input.Pure, FormatTypeTag(*typeTag)); err == nil {
+				return fmt.Sprintf("Pure %s %s", FormatTypeTag(*typeTag), formatValue(value, typeTag))
+			}
+		}
+		return "Pure 0x" + hex.EncodeToString(*input.Pure)
+	case input.Object != nil && input.Object.ImmOrOwnedObject != nil:
+		ref := input.Object.ImmOrOwnedObject
+		switch id := ref.ObjectId.String(); {
+		case v.unresolved[id]:
+			return fmt.Sprintf("Object %s (unresolved)", id)
+		case v.receiving[id]:
+			return fmt.Sprintf("Receiving %s version %d digest %s", id, ref.Version, ref.Digest.String())
+		default:
+			return fmt.Sprintf("ImmOrOwnedObject %s version %d digest %s", id, ref.Version, ref.Digest.String())
+		}
+	case input.Object != nil && input.Object.SharedObject != nil:
+		shared := input.Object.SharedObject
+		mutability := "immutable"
+		if shared.Mutable {
+			mutability = "mutable"
+		}
+		return fmt.Sprintf("SharedObject %s initial shared version %d %s", shared.Id.String(), shared.InitialSharedVersion, mutability)
+	default:
+		return "Unknown"
+	}
+}
+
+// pureTypes returns the types of the pure inputs passed to split amounts, recipients, typed vectors and Move calls with
+// cached signatures.
+func (v *programmableView) pureTypes() map[uint16]*move_types.TypeTag {
+	typeTags := make(map[uint16]*move_types.TypeTag)
+	setType := func(argument sui_types.Argument, typeTag *move_types.TypeTag) {
+		if argument.Input == nil || typeTags[*argument.Input] != nil || int(*argument.Input) >= len(v.inputs) || v.inputs[*argument.Input].Pure == nil {
+			return
+		}
+		typeTags[*argument.Input] = typeTag
+	}
+
+	for _, command := range v.commands {
+		switch {
+		case command.MoveCall != nil:
+			moveCall := command.MoveCall
+			entry := v.cache.GetMoveFunctionDefinition(moveCall.Package.String(), string(moveCall.Module), string(moveCall.Function))
+			if entry == nil {
+				continue
+			}
+			parameters := moveFunctionParameters(entry.Normalized)
+			if len(parameters) != len(moveCall.Arguments) {
+				continue
+			}
+			for i, parameter := range parameters {
+				if typeTag, _, err := parameterType(parameter.SuiMoveNormalizedType, moveCall.TypeArguments); err == nil && isPureType(typeTag) {
+					setType(moveCall.Arguments[i], typeTag)
+				}
+			}
+		case command.SplitCoins != nil:
+			for _, argument := range command.SplitCoins.Arguments {
+				setType(argument, &move_types.TypeTag{U64: &lib.EmptyEnum{}})
+			}
+		case command.TransferObjects != nil:
+			setType(command.TransferObjects.Argument, &move_types.TypeTag{Address: &lib.EmptyEnum{}})
+		case command.MakeMoveVec != nil && command.MakeMoveVec.TypeTag != nil && isPureType(command.MakeMoveVec.TypeTag):
+			for _, argument := range command.MakeMoveVec.Arguments {
+				setType(argument, command.MakeMoveVec.TypeTag)
+			}
+		}
+	}
+	return typeTags
+}
+
+// commandArguments returns the arguments of a command in order.
+func commandArguments(command sui_types.Command) []sui_types.Argument {
+	switch {
+	case command.MoveCall != nil:
+		return command.MoveCall.Arguments
+	case command.TransferObjects != nil:
+		return append(slices.Clone(command.TransferObjects.Arguments), command.TransferObjects.Argument)
+	case command.SplitCoins != nil:
+		return append([]sui_types.Argument{command.SplitCoins.Argument}, command.SplitCoins.Arguments...)
+	case command.MergeCoins != nil:
+		return append([]sui_types.Argument{command.MergeCoins.Argument}, command.MergeCoins.Arguments...)
+	case command.MakeMoveVec != nil:
+		return command.MakeMoveVec.Arguments
+	case command.Upgrade != nil:
+		return []sui_types.Argument{command.Upgrade.Argument}
+	default:
+		return nil
+	}
+}
+
+// formatArgument returns an argument as GasCoin, Input(n), Result(n) or Result(n.m) for nested results.
+func formatArgument(argument sui_types.Argument) string {
+	switch {
+	case argument.GasCoin != nil:
+		return "GasCoin"
+	case argument.Input != nil:
+		return fmt.Sprintf("Input(%d)", *argument.Input)
+	case argument.Result != nil:
+		return fmt.Sprintf("Result(%d)", *argument.Result)
+	case argument.NestedResult != nil:
+		return fmt.Sprintf("Result(%d.%d)", argument.NestedResult.Result1, argument.NestedResult.Result2)
+	default:
+		return "Unknown"
+	}
+}
+
+// formatValue formats a value decoded by the TypeRegistry, strings are quoted and byte vectors are hex encoded.
+func formatValue(value any, typeTag *move_types.TypeTag) string {
+	if typeTag.Struct != nil && structName(typeTag.Struct) == optionStruct && value != nil {
+		return formatValue(value, &typeTag.Struct.TypeParams[0])
+	}
+	switch value := value.(type) {
+	case nil:
+		return "none"
+	case []byte:
+		return "0x" + hex.EncodeToString(value)
+	case *big.Int:
+		return value.String()
+	case string:
+		if typeTag.Struct != nil && (structName(typeTag.Struct) == stringStruct || structName(typeTag.Struct) == asciiStringStruct) {
+			return strconv.Quote(value)
+		}
+		return value
+	case []any:
+		elements := make([]string, len(value))
+		for i, element := range value {
+			elements[i] = formatValue(element, typeTag.Vector)
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// dotQuote returns a quoted DOT string, line breaks are kept as \n.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
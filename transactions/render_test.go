@@ -0,0 +1,129 @@
+package transactions_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+)
+
+// renderedTransaction returns a transaction with a split coin and a coin intent that are not used.
+func renderedTransaction(t *testing.T) (*transactions.Transaction, string, string, string) {
+	pkg, pool, config := objectID(0x48001), objectID(0x48002), objectID(0x48003)
+
+	tx := transactions.NewTransaction(nil)
+	tx.SetCache(transactions.NewLRUCache(0))
+	if err := tx.SupplyMoveFunction(pkg+"::pool::deposit", depositFunction(t, pkg)); err != nil {
+		t.Fatalf("failed to supply move function: %v", err)
+	}
+	coins, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(100)), transactions.Pure(uint64(200))})
+	if err != nil {
+		t.Fatalf("failed to add split coins: %v", err)
+	}
+	if _, err := tx.AddMoveCall(pkg+"::pool::deposit", []transactions.Arg{tx.SharedObjectRef(pool, 3, true), tx.Object(config), transactions.Pure(uint64(5))}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{coins.Nested(0), tx.ReceivingRef(objectRef(t, objectID(0x48004), 4))}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	if _, err := tx.AddMakeMoveVec("vector<u8>", []transactions.Arg{transactions.Pure([]byte("ab"))}); err != nil {
+		t.Fatalf("failed to add make move vec: %v", err)
+	}
+	if _, err := tx.CoinWithBalance(usdc, 7); err != nil {
+		t.Fatalf("failed to add coin with balance: %v", err)
+	}
+	return tx, pkg, pool, config
+}
+
+func TestTransactionString(t *testing.T) {
+	tx, pkg, pool, config := renderedTransaction(t)
+
+	expected := `Inputs:
+  Input(0): Pure u64 100
+  Input(1): Pure u64 200
+  Input(2): SharedObject ` + pool + ` initial shared version 3 mutable
+  Input(3): Object ` + config + ` (unresolved)
+  Input(4): Pure u64 5
+  Input(5): Receiving ` + objectID(0x48004) + ` version 4 digest ` + zeroDigest + `
+  Input(6): Pure address 0x0000000000000000000000000000000000000000000000000000000000000000
+  Input(7): Pure vector<u8> 0x6162
+Commands:
+  Result(0) = SplitCoins(GasCoin, [Input(0), Input(1)]) (unused Result(0.1))
+  Result(1) = MoveCall ` + pkg + `::pool::deposit(Input(2), Input(3), Input(4))
+  Result(2) = TransferObjects([Result(0.0), Input(5)], Input(6))
+  Result(3) = MakeMoveVec<vector<u8>>([Input(7)]) (unused Result(3))
+  Result(4) = CoinWithBalance<` + usdc + `>(7) (unresolved) (unused Result(4))
+`
+	if got := tx.String(); got != expected {
+		t.Errorf("expected\n%s\nbut got\n%s", expected, got)
+	}
+}
+
+func TestTransactionDOT(t *testing.T) {
+	tx, _, _, _ := renderedTransaction(t)
+
+	dot := tx.DOT()
+	for _, expected := range []string{
+		"digraph ProgrammableTransaction {\n",
+		`gas [label="GasCoin", shape=ellipse];`,
+		`input0 [label="Input(0)\nPure u64 100", shape=ellipse];`,
+		`command0 [label="Result(0)\nSplitCoins", color=red, xlabel="unused Result(0.1)"];`,
+		`command1 [label="Result(1)\nMoveCall ` + objectID(0x48001) + `::pool::deposit"];`,
+		`command3 [label="Result(3)\nMakeMoveVec<vector<u8>>", color=red, xlabel="unused Result(3)"];`,
+		"gas -> command0;",
+		"input3 -> command1;",
+		`command0 -> command2 [label="0"];`,
+		"input7 -> command3;",
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("expected DOT to contain %q, but got\n%s", expected, dot)
+		}
+	}
+	if strings.Contains(dot, "command2 [label=\"Result(2)\\nTransferObjects\", color=red") {
+		t.Errorf("expected transfer without results not to be flagged, but got\n%s", dot)
+	}
+}
+
+func TestFormatProgrammableTransaction(t *testing.T) {
+	tx := transactions.NewTransaction(nil)
+	coins, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(100))})
+	if err != nil {
+		t.Fatalf("failed to add split coins: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{coins}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x48005), 1)})
+	tx.SetGasBudget(1000000)
+	tx.SetGasPrice(1000)
+	_, bs, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	data, err := transactions.DecodeTransactionData(bs)
+	if err != nil {
+		t.Fatalf("failed to decode transaction data: %v", err)
+	}
+
+	// an input that is not used by any command
+	pt := data.V1.Kind.ProgrammableTransaction
+	unused := []byte{1, 2}
+	pt.Inputs = append(pt.Inputs, sui_types.CallArg{Pure: &unused})
+
+	expected := `Inputs:
+  Input(0): Pure u64 100
+  Input(1): Pure address 0x0000000000000000000000000000000000000000000000000000000000000000
+  Input(2): Pure 0x0102 (unused)
+Commands:
+  Result(0) = SplitCoins(GasCoin, [Input(0)])
+  Result(1) = TransferObjects([Result(0)], Input(1))
+`
+	if got := transactions.FormatProgrammableTransaction(pt); got != expected {
+		t.Errorf("expected\n%s\nbut got\n%s", expected, got)
+	}
+	if dot := transactions.ProgrammableTransactionDOT(pt); !strings.Contains(dot, `input2 [label="Input(2)\nPure 0x0102", shape=ellipse, color=red, style=dashed, xlabel="unused"];`) || !strings.Contains(dot, "command0 -> command1;") {
+		t.Errorf("expected the unused input to be flagged, but got\n%s", dot)
+	}
+}
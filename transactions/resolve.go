@@ -0,0 +1,222 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// maxObjectsPerRequest defines the maximum number of objects fetched by one multi get objects request.
+const maxObjectsPerRequest = 50
+
+// resolveInputs resolves all objects referenced by ID with a minimal number of requests, the inputs of the
+// transaction are only replaced when every object is resolved.
+func (txb *Transaction) resolveInputs(ctx context.Context) error {
+	if len(txb.unresolvedObjects) == 0 {
+		return nil
+	}
+
+	// the mutability of supplied immutable, owned or receiving objects does not depend on the function signatures
+	inputs := txb.unresolvedInputIndexes()
+	for idx, id := range inputs {
+		if supplied, ok := txb.suppliedObjects[id]; ok && supplied.ref != nil {
+			delete(inputs, idx)
+		}
+	}
+	mutable, err := txb.resolveMoveCallMutability(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("can not resolve move call parameters: %v", err)
+	}
+
+	objectArgs, err := txb.resolveObjects(ctx, mutable)
+	if err != nil {
+		return fmt.Errorf("can not resolve objects: %v", err)
+	}
+
+	for id, objectArg := range objectArgs {
+		objectID, err := sui_types.NewObjectIdFromHex(id)
+		if err != nil {
+			return fmt.Errorf("invalid object id [%s]: %v", id, err)
+		}
+		txb.builder.Inputs[sui_types.BuilderArg{Object: objectID}.String()] = sui_types.CallArg{Object: objectArg}
+		delete(txb.unresolvedObjects, id)
+		if txb.suppliedObjects[id].receiving {
+			txb.receivingObjects[id] = true
+		}
+	}
+
+	return nil
+}
+
+// unresolvedInputIndexes returns the object IDs of unresolved inputs by input index.
+func (txb *Transaction) unresolvedInputIndexes() map[uint16]string {
+	inputs := make(map[uint16]string)
+	for idx, key := range txb.builder.InputsKeyOrder {
+		if key.Object == nil {
+			continue
+		}
+		if _, ok := txb.unresolvedObjects[key.Object.String()]; ok {
+			inputs[uint16(idx)] = key.Object.String()
+		}
+	}
+
+	return inputs
+}
+
+// resolveMoveCallMutability fetches the signatures of Move calls that use unresolved objects, one request per package,
+// and returns the IDs of objects that must be mutable.
+func (txb *Transaction) resolveMoveCallMutability(ctx context.Context, inputs map[uint16]string) (map[string]bool, error) {
+	mutable := make(map[string]bool)
+	for id, object := range txb.unresolvedObjects {
+		mutable[id] = object.Mutable
+	}
+
+	var moveCalls []int
+	for idx, command := range txb.builder.Commands {
+		if command.MoveCall == nil {
+			continue
+		}
+		for _, argument := range command.MoveCall.Arguments {
+			if argument.Input == nil {
+				continue
+			}
+			if _, ok := inputs[*argument.Input]; ok {
+				moveCalls = append(moveCalls, idx)
+				break
+			}
+		}
+	}
+
+	fetched := make(map[string]bool)
+	for _, idx := range moveCalls {
+		moveCall := txb.builder.Commands[idx].MoveCall
+		pkg := moveCall.Package.String()
+		if txb.Cache().GetMoveFunctionDefinition(pkg, string(moveCall.Module), string(moveCall.Function)) != nil || fetched[pkg] {
+			continue
+		}
+		if txb.client == nil {
+			return nil, fmt.Errorf("missing sui client to get normalized move modules of package [%s]", pkg)
+		}
+		if err := cacheNormalizedMoveModules(ctx, txb.client, txb.Cache(), pkg); err != nil {
+			return nil, fmt.Errorf("can not get normalized move modules of package [%s]: %v", pkg, err)
+		}
+		fetched[pkg] = true
+	}
+
+	for _, idx := range moveCalls {
+		moveCall := txb.builder.Commands[idx].MoveCall
+		target := fmt.Sprintf("%s::%s::%s", moveCall.Package.String(), moveCall.Module, moveCall.Function)
+
+		entry := txb.Cache().GetMoveFunctionDefinition(moveCall.Package.String(), string(moveCall.Module), string(moveCall.Function))
+		if entry == nil {
+			return nil, fmt.Errorf("function [%s] of command %d does not exist", target, idx)
+		}
+
+		parameters := moveFunctionParameters(entry.Normalized)
+		if len(moveCall.Arguments) != len(parameters) || len(moveCall.TypeArguments) != len(entry.Normalized.TypeParameters) {
+			return nil, fmt.Errorf("incorrect number of arguments or type arguments in command %d, function [%s] has %d parameters and %d type parameters", idx, target, len(parameters), len(entry.Normalized.TypeParameters))
+		}
+
+		for i, argument := range moveCall.Arguments {
+			if argument.Input == nil {
+				continue
+			}
+			id, ok := inputs[*argument.Input]
+			if !ok {
+				continue
+			}
+			if _, ok := parameters[i].SuiMoveNormalizedType.(types.SuiMoveNormalizedTypeString); ok {
+				return nil, fmt.Errorf("argument at index %d must be a pure value for parameter [%v] in command %d", i, parameters[i].SuiMoveNormalizedType, idx)
+			}
+			if isMutableParameter(parameters[i].SuiMoveNormalizedType) {
+				mutable[id] = true
+			}
+		}
+	}
+
+	return mutable, nil
+}
+
+// resolveObjects resolves unresolved objects from the supplied objects, the cache or in batches of multi get objects requests.
+func (txb *Transaction) resolveObjects(ctx context.Context, mutable map[string]bool) (map[string]*sui_types.ObjectArg, error) {
+	objectArgs := make(map[string]*sui_types.ObjectArg)
+
+	var ids []string
+	for _, key := range txb.builder.InputsKeyOrder {
+		if key.Object == nil {
+			continue
+		}
+		id := key.Object.String()
+		if _, ok := txb.unresolvedObjects[id]; !ok {
+			continue
+		}
+
+		if supplied, ok := txb.suppliedObjects[id]; ok && supplied.ref != nil {
+			objectArgs[id] = &sui_types.ObjectArg{ImmOrOwnedObject: supplied.ref}
+		} else if ok {
+			objectArgs[id] = sharedObjectArg(*key.Object, *supplied.initialSharedVersion, mutable[id])
+		} else if entry := txb.Cache().GetSharedObject(id); entry != nil {
+			objectArgs[id] = entry.ToObjectArg(mutable[id])
+		} else {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 && txb.client == nil {
+		return nil, fmt.Errorf("missing sui client to get %d objects", len(ids))
+	}
+
+	fetched, err := txb.fetchObjectArgs(ctx, ids, mutable)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(objectArgs, fetched)
+	return objectArgs, nil
+}
+
+// fetchObjectArgs fetches objects in batches of multi get objects requests and caches the shared objects.
+func (txb *Transaction) fetchObjectArgs(ctx context.Context, ids []string, mutable map[string]bool) (map[string]*sui_types.ObjectArg, error) {
+	objectArgs := make(map[string]*sui_types.ObjectArg, len(ids))
+	for start := 0; start < len(ids); start += maxObjectsPerRequest {
+		end := min(start+maxObjectsPerRequest, len(ids))
+
+		objects, err := txb.client.MultiGetObjects(ctx, types.MultiGetObjectsParams{IDs: ids[start:end], Options: &types.SuiObjectDataOptions{ShowOwner: true}})
+		if err != nil {
+			return nil, fmt.Errorf("can not call jsonrpc to multi get objects: %v", err)
+		}
+
+		objectMap := utils.SliceToMap(objects, func(v *types.SuiObjectResponse) string {
+			if v.Data != nil {
+				return utils.NormalizeSuiObjectID(v.Data.ObjectID)
+			}
+			return ""
+		})
+
+		for _, id := range ids[start:end] {
+			object := objectMap[id]
+			if object == nil {
+				return nil, fmt.Errorf("can not fetch object with id [%s]", id)
+			}
+
+			objectArg, err := objectResponseToObjectArg(object, mutable[id])
+			if err != nil {
+				return nil, fmt.Errorf("can not convert object response to object arg of [%s]: %v", id, err)
+			}
+			objectArgs[id] = objectArg
+
+			if objectArg.SharedObject != nil {
+				txb.Cache().AddSharedObject(
+					&SharedObjectCacheEntry{
+						ObjectID:             &objectArg.SharedObject.Id,
+						InitialSharedVersion: &objectArg.SharedObject.InitialSharedVersion,
+					},
+				)
+			}
+		}
+	}
+
+	return objectArgs, nil
+}
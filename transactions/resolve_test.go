@@ -0,0 +1,351 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/utils"
+	"github.com/fardream/go-bcs/bcs"
+)
+
+const zeroDigest = "11111111111111111111111111111111"
+
+// mockSuiNode serves JSON-RPC requests with handlers by method and counts the calls of each method.
+type mockSuiNode struct {
+	mutex    sync.Mutex
+	calls    map[string]int
+	handlers map[string]func(params []json.RawMessage) (any, error)
+}
+
+func newMockSuiClient(t *testing.T, handlers map[string]func(params []json.RawMessage) (any, error)) (*client.SuiClient, *mockSuiNode) {
+	node := &mockSuiNode{calls: make(map[string]int), handlers: map[string]func(params []json.RawMessage) (any, error){
+		"suix_getLatestSuiSystemState": func([]json.RawMessage) (any, error) {
+			return mockSystemState("1"), nil
+		},
+		"sui_getProtocolConfig": func([]json.RawMessage) (any, error) {
+			return mockProtocolConfig("1", nil), nil
+		},
+	}}
+	for method, handler := range handlers {
+		node.handlers[method] = handler
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		node.mutex.Lock()
+		node.calls[request.Method]++
+		handler, ok := node.handlers[request.Method]
+		node.mutex.Unlock()
+
+		response := map[string]any{"jsonrpc": "2.0", "id": request.ID}
+		if !ok {
+			response["error"] = map[string]any{"code": -32601, "message": fmt.Sprintf("method %s not found", request.Method)}
+		} else if result, err := handler(request.Params); err != nil {
+			response["error"] = map[string]any{"code": -32000, "message": err.Error()}
+		} else {
+			response["result"] = result
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	suiClient, err := client.NewSuiClient(server.URL)
+	if err != nil {
+		t.Fatalf("failed to create sui client: %v", err)
+	}
+	return suiClient, node
+}
+
+// mockSystemState returns the system state of an epoch that started now.
+func mockSystemState(protocolVersion string) map[string]any {
+	return map[string]any{
+		"epoch":                 "1",
+		"protocolVersion":       protocolVersion,
+		"epochStartTimestampMs": strconv.FormatInt(time.Now().UnixMilli(), 10),
+		"epochDurationMs":       "86400000",
+	}
+}
+
+// mockProtocolConfig returns a protocol config with the limits of mainnet, the limits are replaced by the overrides.
+func mockProtocolConfig(protocolVersion string, overrides map[string]string) map[string]any {
+	limits := map[string]string{
+		"max_tx_size_bytes":            "131072",
+		"max_input_objects":            "2048",
+		"max_arguments":                "512",
+		"max_programmable_tx_commands": "1024",
+		"max_pure_argument_size":       "16384",
+		"max_type_arguments":           "16",
+		"max_type_argument_depth":      "16",
+		"max_gas_payment_objects":      "256",
+	}
+	for name, value := range overrides {
+		limits[name] = value
+	}
+
+	attributes := map[string]any{
+		"gas_model_version":                     map[string]string{"u64": "8"},
+		"max_back_edges_per_function":           nil,
+		"random_beacon_reduction_allowed_delta": map[string]string{"u16": "800"},
+		"enable_feature":                        map[string]string{"bool": "true"},
+		"future_attribute":                      map[string]string{"i128": "1"},
+	}
+	for name, value := range limits {
+		attributes[name] = map[string]string{"u64": value}
+	}
+	attributes["max_arguments"] = map[string]string{"u32": limits["max_arguments"]}
+	return map[string]any{
+		"minSupportedProtocolVersion": "1",
+		"maxSupportedProtocolVersion": protocolVersion,
+		"protocolVersion":             protocolVersion,
+		"featureFlags":                map[string]bool{},
+		"attributes":                  attributes,
+	}
+}
+
+func (node *mockSuiNode) count(method string) int {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	return node.calls[method]
+}
+
+// multiGetObjects returns owned objects, or shared objects for the IDs in shared.
+func multiGetObjects(shared map[string]uint64) func(params []json.RawMessage) (any, error) {
+	return func(params []json.RawMessage) (any, error) {
+		var ids []string
+		if err := json.Unmarshal(params[0], &ids); err != nil {
+			return nil, err
+		}
+
+		objects := make([]map[string]any, len(ids))
+		for idx, id := range ids {
+			var owner any = map[string]any{"AddressOwner": recipient}
+			if version, ok := shared[id]; ok {
+				owner = map[string]any{"Shared": map[string]any{"initial_shared_version": version}}
+			}
+			objects[idx] = map[string]any{"data": map[string]any{"objectId": id, "version": "1", "digest": zeroDigest, "owner": owner}}
+		}
+		return objects, nil
+	}
+}
+
+// normalizedModules returns a package with module `pool` exposing `deposit(&mut Pool, &Config, u64, &mut TxContext)`.
+func normalizedModules(params []json.RawMessage) (any, error) {
+	var pkg string
+	if err := json.Unmarshal(params[0], &pkg); err != nil {
+		return nil, err
+	}
+
+	structType := func(module, name string) map[string]any {
+		return map[string]any{"Struct": map[string]any{"address": pkg, "module": module, "name": name, "typeArguments": []any{}}}
+	}
+	txContext := map[string]any{"Struct": map[string]any{"address": "0x2", "module": "tx_context", "name": "TxContext", "typeArguments": []any{}}}
+
+	return map[string]any{
+		"pool": map[string]any{
+			"fileFormatVersion": 6,
+			"address":           pkg,
+			"name":              "pool",
+			"friends":           []any{},
+			"structs":           map[string]any{},
+			"exposedFunctions": map[string]any{
+				"deposit": map[string]any{
+					"visibility":     "Public",
+					"isEntry":        true,
+					"typeParameters": []any{},
+					"parameters": []any{
+						map[string]any{"MutableReference": structType("pool", "Pool")},
+						map[string]any{"Reference": structType("pool", "Config")},
+						"U64",
+						map[string]any{"MutableReference": txContext},
+					},
+					"return": []any{},
+				},
+			},
+		},
+	}, nil
+}
+
+func objectID(n int) string {
+	return utils.NormalizeSuiObjectID(fmt.Sprintf("0x%x", n))
+}
+
+func TestCommandsAreAddedOffline(t *testing.T) {
+	tx := transactions.NewTransaction(nil)
+
+	pkg := objectID(0x27001)
+	if _, err := tx.AddMoveCall(pkg+"::pool::deposit", []transactions.Arg{tx.Object(objectID(0x27002)), tx.Object(objectID(0x27003)), transactions.Pure(uint64(1))}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{tx.Object(objectID(0x27004))}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+
+	builder := tx.TransactionBuilder()
+	if len(builder.Commands) != 2 || len(builder.InputsKeyOrder) != 5 {
+		t.Errorf("expected 2 commands and 5 inputs, but got %d commands and %d inputs", len(builder.Commands), len(builder.InputsKeyOrder))
+	}
+
+	_, _, err := tx.Build(context.Background(), recipient)
+	if err == nil || !strings.Contains(err.Error(), "missing sui client") {
+		t.Errorf("expected missing sui client error, but got %v", err)
+	}
+}
+
+func TestBuildResolvesObjectsInBatches(t *testing.T) {
+	pkg := objectID(0x27100)
+	pool, config := objectID(0x27101), objectID(0x27102)
+
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_multiGetObjects":                   multiGetObjects(map[string]uint64{pool: 7, config: 8}),
+		"sui_getNormalizedMoveModulesByPackage": normalizedModules,
+	})
+
+	tx := transactions.NewTransaction(suiClient)
+	for i := 0; i < 2; i++ {
+		if _, err := tx.AddMoveCall(pkg+"::pool::deposit", []transactions.Arg{tx.Object(pool), tx.Object(config), transactions.Pure(uint64(i))}, nil); err != nil {
+			t.Fatalf("failed to add move call: %v", err)
+		}
+	}
+
+	var owned []transactions.Arg
+	for i := 0; i < 60; i++ {
+		owned = append(owned, tx.Object(objectID(0x27200+i)))
+	}
+	if err := tx.AddTransferObjects(owned, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	if node.count("sui_multiGetObjects") != 0 || node.count("sui_getNormalizedMoveModulesByPackage") != 0 {
+		t.Fatalf("expected no requests before build, but got %v", node.calls)
+	}
+
+	tx.SetGasPrice(1000)
+	tx.SetGasBudget(1000000)
+	tx.SetGasPayment([]*sui_types.ObjectRef{{Digest: make([]byte, 32)}})
+	data, _, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	if got := node.count("sui_multiGetObjects"); got != 2 {
+		t.Errorf("expected 2 multi get objects requests, but got %d", got)
+	}
+	if got := node.count("sui_getNormalizedMoveModulesByPackage"); got != 1 {
+		t.Errorf("expected 1 normalized move modules request, but got %d", got)
+	}
+
+	inputs := data.V1.Kind.ProgrammableTransaction.Inputs
+	if len(inputs) != 65 {
+		t.Fatalf("expected 65 inputs, but got %d", len(inputs))
+	}
+	if shared := inputs[0].Object.SharedObject; shared == nil || shared.InitialSharedVersion != 7 || !shared.Mutable {
+		t.Errorf("expected mutable shared pool with initial shared version 7, but got %+v", inputs[0].Object)
+	}
+	if shared := inputs[1].Object.SharedObject; shared == nil || shared.InitialSharedVersion != 8 || shared.Mutable {
+		t.Errorf("expected immutable shared config with initial shared version 8, but got %+v", inputs[1].Object)
+	}
+	for _, input := range inputs[4:64] {
+		if input.Object == nil || input.Object.ImmOrOwnedObject == nil || input.Object.ImmOrOwnedObject.Version != 1 {
+			t.Errorf("expected owned object with version 1, but got %+v", input)
+		}
+	}
+}
+
+func TestBuildFailureKeepsTransaction(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		call   int  // the failing call of the method
+		drop   bool // drop an object from the response instead of returning an error
+	}{
+		{name: "normalized move modules", method: "sui_getNormalizedMoveModulesByPackage", call: 1},
+		{name: "first objects batch", method: "sui_multiGetObjects", call: 1},
+		{name: "second objects batch", method: "sui_multiGetObjects", call: 2},
+		{name: "missing object", method: "sui_multiGetObjects", call: 2, drop: true},
+	}
+
+	for idx, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, pool := objectID(0x28100+idx*0x100), objectID(0x28101+idx*0x100)
+			getObjects, calls, fail := multiGetObjects(map[string]uint64{pool: 3}), 0, true
+
+			suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+				"sui_getNormalizedMoveModulesByPackage": func(params []json.RawMessage) (any, error) {
+					if fail && tt.method == "sui_getNormalizedMoveModulesByPackage" {
+						return nil, fmt.Errorf("injected failure")
+					}
+					return normalizedModules(params)
+				},
+				"sui_multiGetObjects": func(params []json.RawMessage) (any, error) {
+					calls++
+					objects, err := getObjects(params)
+					if fail && tt.method == "sui_multiGetObjects" && calls == tt.call {
+						if tt.drop {
+							return objects.([]map[string]any)[1:], err
+						}
+						return nil, fmt.Errorf("injected failure")
+					}
+					return objects, err
+				},
+			})
+
+			tx := transactions.NewTransaction(suiClient)
+			if _, err := tx.AddMoveCall(pkg+"::pool::deposit", []transactions.Arg{tx.Object(pool), tx.Object(objectID(0x28102 + idx*0x100)), transactions.Pure(uint64(1))}, nil); err != nil {
+				t.Fatalf("failed to add move call: %v", err)
+			}
+			var owned []transactions.Arg
+			for i := 0; i < 55; i++ {
+				owned = append(owned, tx.Object(objectID(0x28110+idx*0x100+i)))
+			}
+			if err := tx.AddTransferObjects(owned, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+				t.Fatalf("failed to add transfer objects: %v", err)
+			}
+			tx.SetGasPrice(1000)
+			tx.SetGasBudget(1000000)
+			tx.SetGasPayment([]*sui_types.ObjectRef{{Digest: make([]byte, 32)}})
+
+			expected, err := bcs.Marshal(tx.TransactionBuilder().Finish())
+			if err != nil {
+				t.Fatalf("failed to marshal transaction: %v", err)
+			}
+			if _, _, err := tx.Build(context.Background(), recipient); err == nil {
+				t.Fatalf("expected an error, but got nil")
+			}
+			got, err := bcs.Marshal(tx.TransactionBuilder().Finish())
+			if err != nil {
+				t.Fatalf("failed to marshal transaction: %v", err)
+			}
+			if !reflect.DeepEqual(expected, got) {
+				t.Errorf("expected unchanged transaction %v, but got %v", expected, got)
+			}
+
+			fail, calls = false, 0
+			data, _, err := tx.Build(context.Background(), recipient)
+			if err != nil {
+				t.Fatalf("failed to build transaction after failure: %v", err)
+			}
+			if shared := data.V1.Kind.ProgrammableTransaction.Inputs[0].Object.SharedObject; shared == nil || !shared.Mutable {
+				t.Errorf("expected mutable shared object, but got %+v", data.V1.Kind.ProgrammableTransaction.Inputs[0].Object)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package transactions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// FromTransactionResponse creates a Transaction with the sender, gas data, inputs and commands of an executed programmable
+// transaction, the response must be requested with ShowInput. Objects keep the versions they were used with, call
+// UnpinObjectVersions to run the transaction against the current state. The expiration is not part of the response.
+func FromTransactionResponse(client *client.SuiClient, response *types.SuiTransactionBlockResponse) (*Transaction, error) {
+	if response == nil || response.Transaction == nil {
+		return nil, fmt.Errorf("missing transaction input, the response must be requested with ShowInput")
+	}
+	data := response.Transaction.Data
+	pt, ok := data.Transaction.SuiTransactionBlockKind.(types.SuiTransactionBlockKindProgrammableTransaction)
+	if !ok {
+		return nil, fmt.Errorf("transaction [%s] is not a programmable transaction", response.Digest)
+	}
+
+	txb := NewTransaction(client)
+	sender, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(data.Sender))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address [%s]: %v", data.Sender, err)
+	}
+	txb.Sender = sender
+	if err := txb.setResponseGasData(data.GasData); err != nil {
+		return nil, err
+	}
+
+	for idx, input := range pt.Inputs {
+		if err := txb.addResponseInput(input.SuiCallArg); err != nil {
+			return nil, fmt.Errorf("invalid input at index %d: %v", idx, err)
+		}
+		if len(txb.builder.InputsKeyOrder) != idx+1 {
+			return nil, fmt.Errorf("duplicate object input at index %d", idx)
+		}
+	}
+	for idx, command := range pt.Transactions {
+		if err := txb.addResponseCommand(command.SuiTransaction); err != nil {
+			return nil, fmt.Errorf("invalid command %d: %v", idx, err)
+		}
+	}
+
+	return txb, nil
+}
+
+// UnpinObjectVersions replaces the immutable and owned object inputs with their IDs and removes the gas payment, the
+// current versions and gas coins are fetched when the transaction is built. Shared and receiving objects are kept.
+func (txb *Transaction) UnpinObjectVersions() {
+	for _, key := range txb.builder.InputsKeyOrder {
+		if key.Object == nil {
+			continue
+		}
+		id := key.Object.String()
+		input := txb.builder.Inputs[key.String()]
+		if input.Object == nil || input.Object.ImmOrOwnedObject == nil || txb.receivingObjects[id] {
+			continue
+		}
+		if _, ok := txb.unresolvedObjects[id]; !ok {
+			txb.unresolvedObjects[id] = UnresolvedObject{ObjectID: id}
+			txb.builder.Inputs[key.String()] = sui_types.CallArg{Object: &sui_types.ObjectArg{ImmOrOwnedObject: &sui_types.ObjectRef{ObjectId: *key.Object}}}
+		}
+		if supplied, ok := txb.suppliedObjects[id]; ok && supplied.ref != nil {
+			delete(txb.suppliedObjects, id)
+		}
+	}
+	txb.GasConfig.Payment = nil
+}
+
+// setResponseGasData sets the gas owner when it is not the sender, the price, budget and payment of the response.
+func (txb *Transaction) setResponseGasData(gasData types.SuiGasData) error {
+	owner, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(gasData.Owner))
+	if err != nil {
+		return fmt.Errorf("invalid gas owner [%s]: %v", gasData.Owner, err)
+	}
+	if *owner != *txb.Sender {
+		txb.SetGasOwner(owner.String())
+	}
+
+	price, err := strconv.ParseUint(gasData.Price, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid gas price [%s]: %v", gasData.Price, err)
+	}
+	budget, err := strconv.ParseUint(gasData.Budget, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid gas budget [%s]: %v", gasData.Budget, err)
+	}
+	txb.SetGasPrice(price)
+	txb.SetGasBudget(budget)
+
+	payment := make([]*sui_types.ObjectRef, len(gasData.Payment))
+	for idx, coin := range gasData.Payment {
+		ref, err := ObjectStringRef{ObjectID: coin.ObjectID, Version: strconv.FormatUint(coin.Version, 10), Digest: coin.Digest}.ToObjectRef()
+		if err != nil {
+			return fmt.Errorf("invalid gas payment at index %d: %v", idx, err)
+		}
+		payment[idx] = ref
+	}
+	txb.SetGasPayment(payment)
+	return nil
+}
+
+// addResponseInput adds an input of the response, pure values are encoded with their value type and kept as bytes
+// when the node did not infer the type.
+func (txb *Transaction) addResponseInput(input types.SuiCallArg) error {
+	switch input := input.(type) {
+	case types.SuiCallArgPure:
+		bs, err := encodeResponsePureValue(input)
+		if err != nil {
+			return err
+		}
+		txb.builder.PureBytes(bs, true)
+	case types.SuiCallArgImmOrOwnedObject:
+		ref, err := ObjectStringRef{ObjectID: input.ObjectID, Version: input.Version, Digest: input.Digest}.ToObjectRef()
+		if err != nil {
+			return err
+		}
+		_, err = txb.addObjectInput(sui_types.ObjectArg{ImmOrOwnedObject: ref}, false)
+		return err
+	case types.SuiCallArgReceiving:
+		ref, err := ObjectStringRef{ObjectID: input.ObjectID, Version: input.Version, Digest: input.Digest}.ToObjectRef()
+		if err != nil {
+			return err
+		}
+		_, err = txb.addObjectInput(sui_types.ObjectArg{ImmOrOwnedObject: ref}, true)
+		return err
+	case types.SuiCallArgSharedObject:
+		id, err := sui_types.NewObjectIdFromHex(utils.NormalizeSuiObjectID(input.ObjectID))
+		if err != nil {
+			return fmt.Errorf("invalid object id [%s]: %v", input.ObjectID, err)
+		}
+		initialSharedVersion, err := strconv.ParseUint(input.InitialSharedVersion, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid initial shared version [%s]: %v", input.InitialSharedVersion, err)
+		}
+		_, err = txb.addObjectInput(*sharedObjectArg(*id, initialSharedVersion, input.Mutable), false)
+		return err
+	default:
+		return fmt.Errorf("empty input")
+	}
+	return nil
+}
+
+// addResponseCommand adds a command of the response, arguments must refer to existing inputs and previous commands.
+// Published and upgraded modules are not part of the response.
+func (txb *Transaction) addResponseCommand(command types.SuiTransaction) error {
+	var err error
+	var result sui_types.Command
+	switch command := command.(type) {
+	case types.SuiTransactionMoveCall:
+		result, err = txb.responseMoveCall(command.MoveCall)
+	case types.SuiTransactionTransferObjects:
+		transferObjects := &struct {
+			Arguments []sui_types.Argument
+			Argument  sui_types.Argument
+		}{}
+		if transferObjects.Arguments, err = txb.responseArguments(command.TransferObjects[0]); err == nil {
+			transferObjects.Argument, err = txb.responseArgument(command.TransferObjects[1])
+		}
+		result.TransferObjects = transferObjects
+	case types.SuiTransactionSplitCoins:
+		splitCoins := &struct {
+			Argument  sui_types.Argument
+			Arguments []sui_types.Argument
+		}{}
+		if splitCoins.Argument, err = txb.responseArgument(command.SplitCoins[0]); err == nil {
+			splitCoins.Arguments, err = txb.responseArguments(command.SplitCoins[1])
+		}
+		result.SplitCoins = splitCoins
+	case types.SuiTransactionMergeCoins:
+		mergeCoins := &struct {
+			Argument  sui_types.Argument
+			Arguments []sui_types.Argument
+		}{}
+		if mergeCoins.Argument, err = txb.responseArgument(command.MergeCoins[0]); err == nil {
+			mergeCoins.Arguments, err = txb.responseArguments(command.MergeCoins[1])
+		}
+		result.MergeCoins = mergeCoins
+	case types.SuiTransactionMakeMoveVec:
+		makeMoveVec := &struct {
+			TypeTag   *move_types.TypeTag `bcs:"optional"`
+			Arguments []sui_types.Argument
+		}{}
+		if makeMoveVec.TypeTag, err = responseMakeMoveVecType(command.MakeMoveVec[0]); err == nil {
+			if command.MakeMoveVec[1] == nil {
+				err = fmt.Errorf("missing elements")
+			} else {
+				makeMoveVec.Arguments, err = txb.responseArguments(*command.MakeMoveVec[1])
+			}
+		}
+		result.MakeMoveVec = makeMoveVec
+	case types.SuiTransactionPublish, types.SuiTransactionUpgrade:
+		return fmt.Errorf("modules of publish and upgrade commands are not part of the response, request ShowRawInput and decode the raw transaction with FromBytes")
+	default:
+		return fmt.Errorf("empty command")
+	}
+	if err != nil {
+		return err
+	}
+
+	txb.builder.Commands = append(txb.builder.Commands, result)
+	return nil
+}
+
+func (txb *Transaction) responseMoveCall(moveCall types.MoveCallSuiTransaction) (sui_types.Command, error) {
+	pkg, err := sui_types.NewObjectIdFromHex(utils.NormalizeSuiObjectID(moveCall.Package))
+	if err != nil {
+		return sui_types.Command{}, fmt.Errorf("invalid package [%s]: %v", moveCall.Package, err)
+	}
+	typeArguments := make([]move_types.TypeTag, len(moveCall.TypeArguments))
+	for idx, typeArgument := range moveCall.TypeArguments {
+		if typeArgument == nil {
+			return sui_types.Command{}, fmt.Errorf("missing type argument %d", idx)
+		}
+		typeTag, err := ParseTypeTag(*typeArgument)
+		if err != nil {
+			return sui_types.Command{}, fmt.Errorf("invalid type argument %d: %v", idx, err)
+		}
+		typeArguments[idx] = *typeTag
+	}
+	arguments := make([]sui_types.Argument, len(moveCall.Arguments))
+	for idx, argument := range moveCall.Arguments {
+		if arguments[idx], err = txb.responseSuiArgument(argument.SuiArgument); err != nil {
+			return sui_types.Command{}, fmt.Errorf("invalid argument %d: %v", idx, err)
+		}
+	}
+
+	return sui_types.Command{MoveCall: &sui_types.ProgrammableMoveCall{
+		Package:       *pkg,
+		Module:        move_types.Identifier(moveCall.Module),
+		Function:      move_types.Identifier(moveCall.Function),
+		TypeArguments: typeArguments,
+		Arguments:     arguments,
+	}}, nil
+}
+
+// responseArgument converts a single argument of a command of the response.
+func (txb *Transaction) responseArgument(argument types.SuiTransactionArgumentWrapper) (sui_types.Argument, error) {
+	one, ok := argument.SuiTransactionArgument.(types.SuiTransactionArgumentOne)
+	if !ok {
+		return sui_types.Argument{}, fmt.Errorf("expected an argument, got %T", argument.SuiTransactionArgument)
+	}
+	return txb.responseSuiArgument(one.SuiArgument)
+}
+
+// responseArguments converts the argument list of a command of the response.
+func (txb *Transaction) responseArguments(argument types.SuiTransactionArgumentWrapper) ([]sui_types.Argument, error) {
+	array, ok := argument.SuiTransactionArgument.(types.SuiTransactionArgumentArray)
+	if !ok {
+		return nil, fmt.Errorf("expected a list of arguments, got %T", argument.SuiTransactionArgument)
+	}
+	arguments := make([]sui_types.Argument, len(array))
+	for idx, argument := range array {
+		var err error
+		if arguments[idx], err = txb.responseSuiArgument(argument.SuiArgument); err != nil {
+			return nil, fmt.Errorf("invalid argument %d: %v", idx, err)
+		}
+	}
+	return arguments, nil
+}
+
+// responseSuiArgument converts an argument of the response, it must refer to an existing input or a previous command.
+func (txb *Transaction) responseSuiArgument(argument types.SuiArgument) (sui_types.Argument, error) {
+	index := func(value uint64) (*uint16, error) {
+		if value > math.MaxUint16 {
+			return nil, fmt.Errorf("index %d is out of range", value)
+		}
+		index := uint16(value)
+		return &index, nil
+	}
+
+	var serialized serializedArgument
+	var err error
+	switch argument := argument.(type) {
+	case types.SuiArgumentGasCoin:
+		if argument != "GasCoin" {
+			return sui_types.Argument{}, fmt.Errorf("unknown argument [%s]", string(argument))
+		}
+		gasCoin := true
+		serialized.GasCoin = &gasCoin
+	case types.SuiArgumentInput:
+		serialized.Input, err = index(argument.Input)
+	case types.SuiArgumentResult:
+		serialized.Result, err = index(argument.Result)
+	case types.SuiArgumentNestedResult:
+		var command, result *uint16
+		if command, err = index(argument.NestedResult[0]); err == nil {
+			if result, err = index(argument.NestedResult[1]); err == nil {
+				serialized.NestedResult = &[2]uint16{*command, *result}
+			}
+		}
+	default:
+		return sui_types.Argument{}, fmt.Errorf("empty argument")
+	}
+	if err != nil {
+		return sui_types.Argument{}, err
+	}
+	return txb.serializedArgument(serialized)
+}
+
+// responseMakeMoveVecType returns the element type of a MakeMoveVec command of the response, which is decoded as an argument.
+func responseMakeMoveVecType(argument *types.SuiTransactionArgumentWrapper) (*move_types.TypeTag, error) {
+	if argument == nil {
+		return nil, nil
+	}
+	var moveType string
+	switch argument := argument.SuiTransactionArgument.(type) {
+	case types.SuiTransactionArgumentOne:
+		s, ok := argument.SuiArgument.(types.SuiArgumentGasCoin)
+		if !ok {
+			return nil, fmt.Errorf("expected an element type, got %T", argument.SuiArgument)
+		}
+		moveType = string(s)
+	case types.SuiTransactionArgumentString:
+		moveType = string(argument)
+	default:
+		return nil, fmt.Errorf("expected an element type, got %T", argument)
+	}
+
+	typeTag, err := ParseTypeTag(moveType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid element type: %v", err)
+	}
+	return typeTag, nil
+}
+
+// encodeResponsePureValue encodes a pure value of the response, values without a value type are BCS bytes.
+func encodeResponsePureValue(pure types.SuiCallArgPure) ([]byte, error) {
+	bs, err := json.Marshal(pure.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pure value: %v", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(bs))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid pure value: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if pure.ValueType == nil {
+		values, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected the bytes of a pure value without value type, got %T", value)
+		}
+		for idx, element := range values {
+			if err := encodeInteger(&buf, element, 1); err != nil {
+				return nil, fmt.Errorf("invalid byte %d: %v", idx, err)
+			}
+		}
+		return buf.Bytes(), nil
+	}
+
+	typeTag, err := ParseTypeTag(*pure.ValueType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pure value type: %v", err)
+	}
+	if err := encodePureValue(&buf, typeTag, value); err != nil {
+		return nil, fmt.Errorf("can not encode pure value as [%s]: %v", FormatTypeTag(*typeTag), err)
+	}
+	return buf.Bytes(), nil
+}
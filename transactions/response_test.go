@@ -0,0 +1,179 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+)
+
+// transactionResponse returns the JSON of an executed transaction requested with ShowInput, the commands are replaced.
+func transactionResponse(pkg, owned, receiving, pool, payment, commands string) string {
+	return `{
+		"digest": "` + zeroDigest + `",
+		"transaction": {"data": {
+			"messageVersion": "v1",
+			"transaction": {"kind": "ProgrammableTransaction", "inputs": [
+				{"type": "pure", "valueType": "u64", "value": "100"},
+				{"type": "object", "objectType": "immOrOwnedObject", "objectId": "` + owned + `", "version": "2", "digest": "` + zeroDigest + `"},
+				{"type": "object", "objectType": "receiving", "objectId": "` + receiving + `", "version": "3", "digest": "` + zeroDigest + `"},
+				{"type": "pure", "value": [1, 0, 0, 0, 0, 0, 0, 0]},
+				{"type": "object", "objectType": "sharedObject", "objectId": "` + pool + `", "initialSharedVersion": "7", "mutable": true},
+				{"type": "pure", "valueType": "address", "value": "` + recipient + `"}
+			], "transactions": ` + commands + `},
+			"sender": "` + recipient + `",
+			"gasData": {"payment": [{"objectId": "` + payment + `", "version": 1, "digest": "` + zeroDigest + `"}], "owner": "` + sponsor + `", "price": "1000", "budget": "1000000"}
+		}, "txSignatures": []}
+	}`
+}
+
+func TestFromTransactionResponse(t *testing.T) {
+	ctx := context.Background()
+	pkg, owned, receiving, pool, payment := objectID(0x47001), objectID(0x47002), objectID(0x47003), objectID(0x47004), objectID(0x47005)
+
+	tx := transactions.NewTransaction(nil)
+	coins, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(100))})
+	if err != nil {
+		t.Fatalf("failed to add split coins: %v", err)
+	}
+	received, err := tx.AddMoveCall(pkg+"::pool::receive", []transactions.Arg{tx.ObjectRef(objectRef(t, owned, 2)), tx.ReceivingRef(objectRef(t, receiving, 3))}, []string{"0x2::sui::SUI"})
+	if err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	amounts, err := tx.AddMakeMoveVec("u64", []transactions.Arg{transactions.Pure(uint64(1))})
+	if err != nil {
+		t.Fatalf("failed to add make move vec: %v", err)
+	}
+	if _, err := tx.AddMoveCall(pkg+"::pool::deposit", []transactions.Arg{tx.SharedObjectRef(pool, 7, true), amounts}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	address, err := sui_types.NewAddressFromHex(recipient)
+	if err != nil {
+		t.Fatalf("failed to parse address: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{coins.Nested(0)}, transactions.Pure(*address)); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	if err := tx.AddMergeCoins(tx.Gas(), []transactions.Arg{received.Nested(0)}); err != nil {
+		t.Fatalf("failed to add merge coins: %v", err)
+	}
+	tx.SetGasOwner(sponsor)
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, payment, 1)})
+	tx.SetGasBudget(1000000)
+	tx.SetGasPrice(1000)
+
+	commands := `[
+		{"SplitCoins": ["GasCoin", [{"Input": 0}]]},
+		{"MoveCall": {"package": "` + pkg + `", "module": "pool", "function": "receive", "type_arguments": ["0x2::sui::SUI"], "arguments": [{"Input": 1}, {"Input": 2}]}},
+		{"MakeMoveVec": ["u64", [{"Input": 3}]]},
+		{"MoveCall": {"package": "` + pkg + `", "module": "pool", "function": "deposit", "arguments": [{"Input": 4}, {"Result": 2}]}},
+		{"TransferObjects": [[{"NestedResult": [0, 0]}], {"Input": 5}]},
+		{"MergeCoins": ["GasCoin", [{"NestedResult": [1, 0]}]]}
+	]`
+	var response types.SuiTransactionBlockResponse
+	if err := json.Unmarshal([]byte(transactionResponse(pkg, owned, receiving, pool, payment, commands)), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	converted, err := transactions.FromTransactionResponse(nil, &response)
+	if err != nil {
+		t.Fatalf("failed to convert response: %v", err)
+	}
+
+	_, expected, err := tx.Build(ctx, recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	_, got, err := converted.Build(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to build converted transaction: %v", err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected bytes %v, but got %v", expected, got)
+	}
+
+	// the converted transaction runs against the current versions of owned objects and gas coins
+	converted.UnpinObjectVersions()
+	_, _, err = converted.Build(ctx, "")
+	var missing *transactions.MissingDataError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected a missing data error, but got %v", err)
+	}
+	if !strings.Contains(err.Error(), "object ["+owned+"]") || strings.Contains(err.Error(), receiving) || strings.Contains(err.Error(), pool) {
+		t.Errorf("expected only the owned object to be missing, but got %v", err)
+	}
+	converted.SupplyObjectRef(objectRef(t, owned, 9))
+	converted.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, payment, 8)})
+	data, _, err := converted.Build(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to build unpinned transaction: %v", err)
+	}
+	inputs := data.V1.Kind.ProgrammableTransaction.Inputs
+	if got := inputs[1].Object.ImmOrOwnedObject.Version; got != 9 {
+		t.Errorf("expected owned object version 9, but got %d", got)
+	}
+	if got := inputs[2].Object.ImmOrOwnedObject.Version; got != 3 {
+		t.Errorf("expected receiving object version 3, but got %d", got)
+	}
+	if got := data.V1.GasData.Payment[0].Version; got != 8 {
+		t.Errorf("expected gas payment version 8, but got %d", got)
+	}
+}
+
+func TestFromTransactionResponseErrors(t *testing.T) {
+	pkg, owned, receiving, pool, payment := objectID(0x47001), objectID(0x47002), objectID(0x47003), objectID(0x47004), objectID(0x47005)
+
+	tests := []struct {
+		name     string
+		response string
+		err      string
+	}{
+		{
+			name:     "missing input",
+			response: `{"digest": "` + zeroDigest + `"}`,
+			err:      "ShowInput",
+		},
+		{
+			name:     "not programmable",
+			response: `{"digest": "` + zeroDigest + `", "transaction": {"data": {"transaction": {"kind": "Genesis", "objects": []}}}}`,
+			err:      "not a programmable transaction",
+		},
+		{
+			name:     "publish",
+			response: transactionResponse(pkg, owned, receiving, pool, payment, `[{"Publish": ["0x1", "0x2"]}]`),
+			err:      "ShowRawInput",
+		},
+		{
+			name:     "input out of range",
+			response: transactionResponse(pkg, owned, receiving, pool, payment, `[{"SplitCoins": ["GasCoin", [{"Input": 6}]]}]`),
+			err:      "input 6 does not exist",
+		},
+		{
+			name:     "result of later command",
+			response: transactionResponse(pkg, owned, receiving, pool, payment, `[{"TransferObjects": [[{"Result": 0}], {"Input": 5}]}]`),
+			err:      "result of command 0 does not exist",
+		},
+		{
+			name:     "invalid pure value",
+			response: strings.Replace(transactionResponse(pkg, owned, receiving, pool, payment, `[]`), `"value": "100"`, `"value": "-1"`, 1),
+			err:      "invalid input at index 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response types.SuiTransactionBlockResponse
+			if err := json.Unmarshal([]byte(tt.response), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			_, err := transactions.FromTransactionResponse(nil, &response)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error containing %q, but got %v", tt.err, err)
+			}
+		})
+	}
+}
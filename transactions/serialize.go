@@ -0,0 +1,737 @@
+package transactions
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// coinWithBalanceIntent defines the name of the coin intent in the TypeScript SDK, the coin type is "gas" for SUI split from the gas coin.
+const coinWithBalanceIntent = "CoinWithBalance"
+
+// serializedTransaction defines the JSON transaction data of the TypeScript SDK in version 2 of the schema.
+type serializedTransaction struct {
+	Version    int                   `json:"version"`
+	Sender     *string               `json:"sender"`
+	Expiration *serializedExpiration `json:"expiration"`
+	GasData    serializedGasData     `json:"gasData"`
+	Inputs     []serializedCallArg   `json:"inputs"`
+	Commands   []serializedCommand   `json:"commands"`
+}
+
+// jsonU64 defines a u64 value that is a decimal string in JSON, numbers are accepted as well.
+type jsonU64 uint64
+
+type serializedExpiration struct {
+	Kind  string   `json:"$kind,omitempty"`
+	None  *bool    `json:"None,omitempty"`
+	Epoch *jsonU64 `json:"Epoch,omitempty"`
+}
+
+type serializedGasData struct {
+	Budget  *jsonU64              `json:"budget"`
+	Price   *jsonU64              `json:"price"`
+	Owner   *string               `json:"owner"`
+	Payment []serializedObjectRef `json:"payment"`
+}
+
+type serializedObjectRef struct {
+	ObjectID string  `json:"objectId"`
+	Version  jsonU64 `json:"version"`
+	Digest   string  `json:"digest"`
+}
+
+type serializedCallArg struct {
+	Kind             string                      `json:"$kind,omitempty"`
+	Object           *serializedObjectArg        `json:"Object,omitempty"`
+	Pure             *serializedPure             `json:"Pure,omitempty"`
+	UnresolvedPure   *serializedUnresolvedPure   `json:"UnresolvedPure,omitempty"`
+	UnresolvedObject *serializedUnresolvedObject `json:"UnresolvedObject,omitempty"`
+}
+
+type serializedObjectArg struct {
+	Kind             string                  `json:"$kind,omitempty"`
+	ImmOrOwnedObject *serializedObjectRef    `json:"ImmOrOwnedObject,omitempty"`
+	SharedObject     *serializedSharedObject `json:"SharedObject,omitempty"`
+	Receiving        *serializedObjectRef    `json:"Receiving,omitempty"`
+}
+
+type serializedSharedObject struct {
+	ObjectID             string  `json:"objectId"`
+	InitialSharedVersion jsonU64 `json:"initialSharedVersion"`
+	Mutable              bool    `json:"mutable"`
+}
+
+type serializedPure struct {
+	Bytes string `json:"bytes"` // base64
+}
+
+type serializedUnresolvedPure struct {
+	Value json.RawMessage `json:"value"`
+}
+
+type serializedUnresolvedObject struct {
+	ObjectID             string   `json:"objectId"`
+	Version              *jsonU64 `json:"version,omitempty"`
+	Digest               *string  `json:"digest,omitempty"`
+	InitialSharedVersion *jsonU64 `json:"initialSharedVersion,omitempty"`
+	Mutable              *bool    `json:"mutable,omitempty"`
+}
+
+type serializedArgument struct {
+	Kind         string     `json:"$kind,omitempty"`
+	GasCoin      *bool      `json:"GasCoin,omitempty"`
+	Input        *uint16    `json:"Input,omitempty"`
+	Type         string     `json:"type,omitempty"` // "pure" or "object" for inputs
+	Result       *uint16    `json:"Result,omitempty"`
+	NestedResult *[2]uint16 `json:"NestedResult,omitempty"`
+}
+
+type serializedCommand struct {
+	Kind            string                     `json:"$kind,omitempty"`
+	MoveCall        *serializedMoveCall        `json:"MoveCall,omitempty"`
+	TransferObjects *serializedTransferObjects `json:"TransferObjects,omitempty"`
+	SplitCoins      *serializedSplitCoins      `json:"SplitCoins,omitempty"`
+	MergeCoins      *serializedMergeCoins      `json:"MergeCoins,omitempty"`
+	Publish         *serializedPublish         `json:"Publish,omitempty"`
+	MakeMoveVec     *serializedMakeMoveVec     `json:"MakeMoveVec,omitempty"`
+	Upgrade         *serializedUpgrade         `json:"Upgrade,omitempty"`
+	Intent          *serializedIntent          `json:"$Intent,omitempty"`
+}
+
+type serializedMoveCall struct {
+	Package       string               `json:"package"`
+	Module        string               `json:"module"`
+	Function      string               `json:"function"`
+	TypeArguments []string             `json:"typeArguments"`
+	Arguments     []serializedArgument `json:"arguments"`
+}
+
+type serializedTransferObjects struct {
+	Objects []serializedArgument `json:"objects"`
+	Address serializedArgument   `json:"address"`
+}
+
+type serializedSplitCoins struct {
+	Coin    serializedArgument   `json:"coin"`
+	Amounts []serializedArgument `json:"amounts"`
+}
+
+type serializedMergeCoins struct {
+	Destination serializedArgument   `json:"destination"`
+	Sources     []serializedArgument `json:"sources"`
+}
+
+type serializedPublish struct {
+	Modules      []string `json:"modules"` // base64
+	Dependencies []string `json:"dependencies"`
+}
+
+type serializedMakeMoveVec struct {
+	Type     *string              `json:"type"`
+	Elements []serializedArgument `json:"elements"`
+}
+
+type serializedUpgrade struct {
+	Modules      []string           `json:"modules"` // base64
+	Dependencies []string           `json:"dependencies"`
+	Package      string             `json:"package"`
+	Ticket       serializedArgument `json:"ticket"`
+}
+
+type serializedIntent struct {
+	Name   string                     `json:"name"`
+	Inputs map[string]json.RawMessage `json:"inputs"`
+	Data   json.RawMessage            `json:"data"`
+}
+
+type serializedCoinWithBalance struct {
+	Type    string  `json:"type"`
+	Balance jsonU64 `json:"balance"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v jsonU64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(v), 10))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *jsonU64) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	value, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid u64 [%s]", string(data))
+	}
+	*v = jsonU64(value)
+	return nil
+}
+
+// FromJSON creates a Transaction from the JSON transaction data of the TypeScript SDK, as returned by `Transaction.toJSON`.
+// Objects and pure values that are not resolved yet and coin intents are resolved with the SuiClient when the transaction is built.
+func FromJSON(client *client.SuiClient, data []byte) (*Transaction, error) {
+	var serialized serializedTransaction
+	if err := json.Unmarshal(data, &serialized); err != nil {
+		return nil, fmt.Errorf("can not unmarshal transaction json: %v", err)
+	}
+	if serialized.Version != 2 {
+		return nil, fmt.Errorf("unsupported transaction json version %d, only version 2 is supported", serialized.Version)
+	}
+
+	txb := NewTransaction(client)
+	if serialized.Sender != nil {
+		sender, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(*serialized.Sender))
+		if err != nil {
+			return nil, fmt.Errorf("invalid sender address [%s]: %v", *serialized.Sender, err)
+		}
+		txb.Sender = sender
+	}
+	if serialized.Expiration != nil && serialized.Expiration.Epoch != nil {
+		txb.SetExpiration(uint64(*serialized.Expiration.Epoch))
+	}
+
+	gasData := serialized.GasData
+	if gasData.Budget != nil {
+		txb.SetGasBudget(uint64(*gasData.Budget))
+	}
+	if gasData.Price != nil {
+		txb.SetGasPrice(uint64(*gasData.Price))
+	}
+	if gasData.Owner != nil {
+		txb.SetGasOwner(*gasData.Owner)
+	}
+	for idx, payment := range gasData.Payment {
+		ref, err := payment.toObjectRef()
+		if err != nil {
+			return nil, fmt.Errorf("invalid gas payment at index %d: %v", idx, err)
+		}
+		txb.GasConfig.Payment = append(txb.GasConfig.Payment, ref)
+	}
+
+	for idx, input := range serialized.Inputs {
+		if err := txb.addSerializedInput(input); err != nil {
+			return nil, fmt.Errorf("invalid input at index %d: %v", idx, err)
+		}
+		if len(txb.builder.InputsKeyOrder) != idx+1 {
+			return nil, fmt.Errorf("duplicate object input at index %d", idx)
+		}
+	}
+	for idx, command := range serialized.Commands {
+		if err := txb.addSerializedCommand(command); err != nil {
+			return nil, fmt.Errorf("invalid command %d: %v", idx, err)
+		}
+	}
+
+	return txb, nil
+}
+
+// ToJSON returns the transaction as JSON transaction data of the TypeScript SDK, which is restored with `Transaction.from`
+// or FromJSON. Objects referenced by ID and coin intents are kept unresolved until the transaction is built.
+func (txb *Transaction) ToJSON() ([]byte, error) {
+	serialized := serializedTransaction{Version: 2, Inputs: []serializedCallArg{}, Commands: []serializedCommand{}}
+	if txb.Sender != nil {
+		sender := txb.Sender.String()
+		serialized.Sender = &sender
+	}
+	if txb.expiration != nil {
+		epoch := jsonU64(*txb.expiration)
+		serialized.Expiration = &serializedExpiration{Kind: "Epoch", Epoch: &epoch}
+	}
+
+	gasConfig := txb.GasConfig
+	if gasConfig.Budget != 0 {
+		budget := jsonU64(gasConfig.Budget)
+		serialized.GasData.Budget = &budget
+	}
+	if gasConfig.Price != 0 {
+		price := jsonU64(gasConfig.Price)
+		serialized.GasData.Price = &price
+	}
+	if gasConfig.Owner != "" {
+		owner := utils.NormalizeSuiAddress(gasConfig.Owner)
+		serialized.GasData.Owner = &owner
+	}
+	for _, ref := range gasConfig.Payment {
+		serialized.GasData.Payment = append(serialized.GasData.Payment, newSerializedObjectRef(ref))
+	}
+
+	for idx := range txb.builder.InputsKeyOrder {
+		input, err := txb.serializeInput(uint16(idx))
+		if err != nil {
+			return nil, fmt.Errorf("can not serialize input %d: %v", idx, err)
+		}
+		serialized.Inputs = append(serialized.Inputs, input)
+	}
+	for idx, command := range txb.builder.Commands {
+		serializedCommand, err := txb.serializeCommand(uint16(idx), command)
+		if err != nil {
+			return nil, fmt.Errorf("can not serialize command %d: %v", idx, err)
+		}
+		serialized.Commands = append(serialized.Commands, serializedCommand)
+	}
+
+	bs, err := json.Marshal(serialized)
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal transaction json: %v", err)
+	}
+	return bs, nil
+}
+
+// addSerializedInput adds an input of the JSON transaction data, objects with a version and digest or an initial shared
+// version are supplied and not fetched.
+func (txb *Transaction) addSerializedInput(input serializedCallArg) error {
+	switch {
+	case input.Pure != nil:
+		bs, err := base64.StdEncoding.DecodeString(input.Pure.Bytes)
+		if err != nil {
+			return fmt.Errorf("invalid pure bytes: %v", err)
+		}
+		txb.builder.PureBytes(bs, true)
+	case input.UnresolvedPure != nil:
+		if len(input.UnresolvedPure.Value) == 0 {
+			return fmt.Errorf("missing unresolved pure value")
+		}
+		txb.unresolvedPureInput(input.UnresolvedPure.Value)
+	case input.Object != nil:
+		objectArg, receiving, err := input.Object.toObjectArg()
+		if err != nil {
+			return err
+		}
+		if _, err := txb.addObjectInput(*objectArg, receiving); err != nil {
+			return err
+		}
+	case input.UnresolvedObject != nil:
+		object := input.UnresolvedObject
+		if _, err := txb.unresolvedObjectInput(UnresolvedObject{ObjectID: object.ObjectID, Mutable: object.Mutable != nil && *object.Mutable}); err != nil {
+			return err
+		}
+		switch {
+		case object.Version != nil && object.Digest != nil:
+			ref, err := serializedObjectRef{ObjectID: object.ObjectID, Version: *object.Version, Digest: *object.Digest}.toObjectRef()
+			if err != nil {
+				return err
+			}
+			txb.SupplyObjectRef(ref)
+		case object.InitialSharedVersion != nil:
+			return txb.SupplySharedObject(object.ObjectID, uint64(*object.InitialSharedVersion))
+		}
+	default:
+		return fmt.Errorf("empty input")
+	}
+	return nil
+}
+
+// addSerializedCommand adds a command of the JSON transaction data, arguments must refer to existing inputs and previous commands.
+func (txb *Transaction) addSerializedCommand(command serializedCommand) error {
+	arguments := func(serialized []serializedArgument) ([]sui_types.Argument, error) {
+		arguments := make([]sui_types.Argument, len(serialized))
+		for idx, argument := range serialized {
+			var err error
+			if arguments[idx], err = txb.serializedArgument(argument); err != nil {
+				return nil, fmt.Errorf("invalid argument %d: %v", idx, err)
+			}
+		}
+		return arguments, nil
+	}
+
+	var err error
+	var result sui_types.Command
+	switch {
+	case command.MoveCall != nil:
+		result, err = txb.serializedMoveCall(command.MoveCall)
+	case command.TransferObjects != nil:
+		transferObjects := &struct {
+			Arguments []sui_types.Argument
+			Argument  sui_types.Argument
+		}{}
+		if transferObjects.Arguments, err = arguments(command.TransferObjects.Objects); err == nil {
+			transferObjects.Argument, err = txb.serializedArgument(command.TransferObjects.Address)
+		}
+		result.TransferObjects = transferObjects
+	case command.SplitCoins != nil:
+		splitCoins := &struct {
+			Argument  sui_types.Argument
+			Arguments []sui_types.Argument
+		}{}
+		if splitCoins.Argument, err = txb.serializedArgument(command.SplitCoins.Coin); err == nil {
+			splitCoins.Arguments, err = arguments(command.SplitCoins.Amounts)
+		}
+		result.SplitCoins = splitCoins
+	case command.MergeCoins != nil:
+		mergeCoins := &struct {
+			Argument  sui_types.Argument
+			Arguments []sui_types.Argument
+		}{}
+		if mergeCoins.Argument, err = txb.serializedArgument(command.MergeCoins.Destination); err == nil {
+			mergeCoins.Arguments, err = arguments(command.MergeCoins.Sources)
+		}
+		result.MergeCoins = mergeCoins
+	case command.MakeMoveVec != nil:
+		makeMoveVec := &struct {
+			TypeTag   *move_types.TypeTag `bcs:"optional"`
+			Arguments []sui_types.Argument
+		}{}
+		if command.MakeMoveVec.Type != nil {
+			makeMoveVec.TypeTag, err = ParseTypeTag(*command.MakeMoveVec.Type)
+		}
+		if err == nil {
+			makeMoveVec.Arguments, err = arguments(command.MakeMoveVec.Elements)
+		}
+		result.MakeMoveVec = makeMoveVec
+	case command.Publish != nil:
+		publish := &struct {
+			Bytes   [][]uint8
+			Objects []sui_types.ObjectID
+		}{}
+		if publish.Bytes, err = decodeModules(command.Publish.Modules); err == nil {
+			publish.Objects, err = parseObjectIDs(command.Publish.Dependencies)
+		}
+		result.Publish = publish
+	case command.Upgrade != nil:
+		result, err = txb.serializedUpgrade(command.Upgrade)
+	case command.Intent != nil:
+		return txb.addSerializedIntent(command.Intent)
+	default:
+		return fmt.Errorf("empty command")
+	}
+	if err != nil {
+		return err
+	}
+
+	txb.builder.Commands = append(txb.builder.Commands, result)
+	return nil
+}
+
+func (txb *Transaction) serializedMoveCall(moveCall *serializedMoveCall) (sui_types.Command, error) {
+	pkg, err := sui_types.NewObjectIdFromHex(utils.NormalizeSuiObjectID(moveCall.Package))
+	if err != nil {
+		return sui_types.Command{}, fmt.Errorf("invalid package [%s]: %v", moveCall.Package, err)
+	}
+	typeArguments, err := txb.resolveFunctionTypeArguments(moveCall.TypeArguments)
+	if err != nil {
+		return sui_types.Command{}, err
+	}
+	arguments := make([]sui_types.Argument, len(moveCall.Arguments))
+	for idx, argument := range moveCall.Arguments {
+		if arguments[idx], err = txb.serializedArgument(argument); err != nil {
+			return sui_types.Command{}, fmt.Errorf("invalid argument %d: %v", idx, err)
+		}
+	}
+
+	return sui_types.Command{MoveCall: &sui_types.ProgrammableMoveCall{
+		Package:       *pkg,
+		Module:        move_types.Identifier(moveCall.Module),
+		Function:      move_types.Identifier(moveCall.Function),
+		TypeArguments: typeArguments,
+		Arguments:     arguments,
+	}}, nil
+}
+
+func (txb *Transaction) serializedUpgrade(upgrade *serializedUpgrade) (sui_types.Command, error) {
+	modules, err := decodeModules(upgrade.Modules)
+	if err != nil {
+		return sui_types.Command{}, err
+	}
+	dependencies, err := parseObjectIDs(upgrade.Dependencies)
+	if err != nil {
+		return sui_types.Command{}, err
+	}
+	pkg, err := sui_types.NewObjectIdFromHex(utils.NormalizeSuiObjectID(upgrade.Package))
+	if err != nil {
+		return sui_types.Command{}, fmt.Errorf("invalid package [%s]: %v", upgrade.Package, err)
+	}
+	ticket, err := txb.serializedArgument(upgrade.Ticket)
+	if err != nil {
+		return sui_types.Command{}, fmt.Errorf("invalid ticket: %v", err)
+	}
+
+	return sui_types.Command{Upgrade: &struct {
+		Bytes    [][]uint8
+		Objects  []sui_types.ObjectID
+		ObjectID sui_types.ObjectID
+		Argument sui_types.Argument
+	}{Bytes: modules, Objects: dependencies, ObjectID: *pkg, Argument: ticket}}, nil
+}
+
+// addSerializedIntent adds a coin intent, other intents of the TypeScript SDK are not supported.
+func (txb *Transaction) addSerializedIntent(intent *serializedIntent) error {
+	if intent.Name != coinWithBalanceIntent {
+		return fmt.Errorf("unsupported intent [%s]", intent.Name)
+	}
+	if len(intent.Inputs) > 0 {
+		return fmt.Errorf("intent [%s] must not have inputs", intent.Name)
+	}
+
+	var coin serializedCoinWithBalance
+	if err := json.Unmarshal(intent.Data, &coin); err != nil {
+		return fmt.Errorf("invalid data of intent [%s]: %v", intent.Name, err)
+	}
+	if coin.Type == "gas" {
+		_, err := txb.CoinWithBalance(utils.SuiTypeArg, uint64(coin.Balance))
+		return err
+	}
+
+	_, err := txb.CoinWithBalanceFromSender(coin.Type, uint64(coin.Balance))
+	return err
+}
+
+// serializedArgument converts an argument of the JSON transaction data, it must refer to an existing input or a previous command.
+func (txb *Transaction) serializedArgument(argument serializedArgument) (sui_types.Argument, error) {
+	commands := uint16(len(txb.builder.Commands))
+	switch {
+	case argument.GasCoin != nil:
+		return sui_types.Argument{GasCoin: &lib.EmptyEnum{}}, nil
+	case argument.Input != nil:
+		if int(*argument.Input) >= len(txb.builder.InputsKeyOrder) {
+			return sui_types.Argument{}, fmt.Errorf("input %d does not exist", *argument.Input)
+		}
+		return sui_types.Argument{Input: argument.Input}, nil
+	case argument.Result != nil:
+		if *argument.Result >= commands {
+			return sui_types.Argument{}, fmt.Errorf("result of command %d does not exist", *argument.Result)
+		}
+		return sui_types.Argument{Result: argument.Result}, nil
+	case argument.NestedResult != nil:
+		if argument.NestedResult[0] >= commands {
+			return sui_types.Argument{}, fmt.Errorf("result of command %d does not exist", argument.NestedResult[0])
+		}
+		return sui_types.Argument{NestedResult: &struct {
+			Result1 uint16
+			Result2 uint16
+		}{Result1: argument.NestedResult[0], Result2: argument.NestedResult[1]}}, nil
+	default:
+		return sui_types.Argument{}, fmt.Errorf("empty argument")
+	}
+}
+
+// serializeInput returns the input at index idx as JSON transaction data, supplied objects keep their version and digest
+// or initial shared version.
+func (txb *Transaction) serializeInput(idx uint16) (serializedCallArg, error) {
+	if value, ok := txb.unresolvedPures[idx]; ok {
+		return serializedCallArg{Kind: "UnresolvedPure", UnresolvedPure: &serializedUnresolvedPure{Value: value}}, nil
+	}
+
+	input := txb.builder.Inputs[txb.builder.InputsKeyOrder[idx].String()]
+	switch {
+	case input.Pure != nil:
+		return serializedCallArg{Kind: "Pure", Pure: &serializedPure{Bytes: base64.StdEncoding.EncodeToString(*input.Pure)}}, nil
+	case input.Object == nil:
+		return serializedCallArg{}, fmt.Errorf("empty input")
+	}
+
+	var id string
+	switch {
+	case input.Object.ImmOrOwnedObject != nil:
+		id = input.Object.ImmOrOwnedObject.ObjectId.String()
+	case input.Object.SharedObject != nil:
+		id = input.Object.SharedObject.Id.String()
+	}
+
+	if unresolved, ok := txb.unresolvedObjects[id]; ok {
+		object := &serializedUnresolvedObject{ObjectID: id}
+		if unresolved.Mutable {
+			object.Mutable = &unresolved.Mutable
+		}
+		supplied, ok := txb.suppliedObjects[id]
+		switch {
+		case ok && supplied.receiving:
+			ref := newSerializedObjectRef(supplied.ref)
+			return serializedCallArg{Kind: "Object", Object: &serializedObjectArg{Kind: "Receiving", Receiving: &ref}}, nil
+		case ok && supplied.ref != nil:
+			version, digest := jsonU64(supplied.ref.Version), supplied.ref.Digest.String()
+			object.Version, object.Digest = &version, &digest
+		case ok:
+			version := jsonU64(*supplied.initialSharedVersion)
+			object.InitialSharedVersion = &version
+		}
+		return serializedCallArg{Kind: "UnresolvedObject", UnresolvedObject: object}, nil
+	}
+
+	objectArg := &serializedObjectArg{}
+	switch {
+	case input.Object.SharedObject != nil:
+		shared := input.Object.SharedObject
+		objectArg.Kind = "SharedObject"
+		objectArg.SharedObject = &serializedSharedObject{ObjectID: id, InitialSharedVersion: jsonU64(shared.InitialSharedVersion), Mutable: shared.Mutable}
+	case txb.receivingObjects[id]:
+		ref := newSerializedObjectRef(input.Object.ImmOrOwnedObject)
+		objectArg.Kind, objectArg.Receiving = "Receiving", &ref
+	default:
+		ref := newSerializedObjectRef(input.Object.ImmOrOwnedObject)
+		objectArg.Kind, objectArg.ImmOrOwnedObject = "ImmOrOwnedObject", &ref
+	}
+	return serializedCallArg{Kind: "Object", Object: objectArg}, nil
+}
+
+// serializeCommand returns the command at index idx as JSON transaction data, coin intents are kept as intents.
+func (txb *Transaction) serializeCommand(idx uint16, command sui_types.Command) (serializedCommand, error) {
+	if intent, ok := txb.intents[idx]; ok {
+		coinType := intent.CoinType
+		if intent.UseGasCoin {
+			coinType = "gas"
+		}
+		data, err := json.Marshal(serializedCoinWithBalance{Type: coinType, Balance: jsonU64(intent.Amount)})
+		if err != nil {
+			return serializedCommand{}, err
+		}
+		return serializedCommand{Kind: "$Intent", Intent: &serializedIntent{Name: coinWithBalanceIntent, Inputs: map[string]json.RawMessage{}, Data: data}}, nil
+	}
+
+	arguments := func(arguments []sui_types.Argument) []serializedArgument {
+		serialized := make([]serializedArgument, len(arguments))
+		for idx, argument := range arguments {
+			serialized[idx] = txb.serializeArgument(argument)
+		}
+		return serialized
+	}
+
+	switch {
+	case command.MoveCall != nil:
+		moveCall := command.MoveCall
+		typeArguments := make([]string, len(moveCall.TypeArguments))
+		for idx, typeArgument := range moveCall.TypeArguments {
+			typeArguments[idx] = FormatTypeTag(typeArgument)
+		}
+		return serializedCommand{Kind: "MoveCall", MoveCall: &serializedMoveCall{
+			Package:       moveCall.Package.String(),
+			Module:        string(moveCall.Module),
+			Function:      string(moveCall.Function),
+			TypeArguments: typeArguments,
+			Arguments:     arguments(moveCall.Arguments),
+		}}, nil
+	case command.TransferObjects != nil:
+		return serializedCommand{Kind: "TransferObjects", TransferObjects: &serializedTransferObjects{
+			Objects: arguments(command.TransferObjects.Arguments),
+			Address: txb.serializeArgument(command.TransferObjects.Argument),
+		}}, nil
+	case command.SplitCoins != nil:
+		return serializedCommand{Kind: "SplitCoins", SplitCoins: &serializedSplitCoins{
+			Coin:    txb.serializeArgument(command.SplitCoins.Argument),
+			Amounts: arguments(command.SplitCoins.Arguments),
+		}}, nil
+	case command.MergeCoins != nil:
+		return serializedCommand{Kind: "MergeCoins", MergeCoins: &serializedMergeCoins{
+			Destination: txb.serializeArgument(command.MergeCoins.Argument),
+			Sources:     arguments(command.MergeCoins.Arguments),
+		}}, nil
+	case command.MakeMoveVec != nil:
+		makeMoveVec := &serializedMakeMoveVec{Elements: arguments(command.MakeMoveVec.Arguments)}
+		if command.MakeMoveVec.TypeTag != nil {
+			vecType := FormatTypeTag(*command.MakeMoveVec.TypeTag)
+			makeMoveVec.Type = &vecType
+		}
+		return serializedCommand{Kind: "MakeMoveVec", MakeMoveVec: makeMoveVec}, nil
+	case command.Publish != nil:
+		return serializedCommand{Kind: "Publish", Publish: &serializedPublish{
+			Modules:      encodeModules(command.Publish.Bytes),
+			Dependencies: formatObjectIDs(command.Publish.Objects),
+		}}, nil
+	case command.Upgrade != nil:
+		return serializedCommand{Kind: "Upgrade", Upgrade: &serializedUpgrade{
+			Modules:      encodeModules(command.Upgrade.Bytes),
+			Dependencies: formatObjectIDs(command.Upgrade.Objects),
+			Package:      command.Upgrade.ObjectID.String(),
+			Ticket:       txb.serializeArgument(command.Upgrade.Argument),
+		}}, nil
+	default:
+		return serializedCommand{}, fmt.Errorf("empty command")
+	}
+}
+
+// serializeArgument returns the argument as JSON transaction data, inputs are marked as pure or object inputs.
+func (txb *Transaction) serializeArgument(argument sui_types.Argument) serializedArgument {
+	switch {
+	case argument.GasCoin != nil:
+		gasCoin := true
+		return serializedArgument{Kind: "GasCoin", GasCoin: &gasCoin}
+	case argument.Input != nil:
+		serialized := serializedArgument{Kind: "Input", Input: argument.Input, Type: "pure"}
+		if int(*argument.Input) < len(txb.builder.InputsKeyOrder) && txb.builder.InputsKeyOrder[*argument.Input].Object != nil {
+			serialized.Type = "object"
+		}
+		return serialized
+	case argument.Result != nil:
+		return serializedArgument{Kind: "Result", Result: argument.Result}
+	case argument.NestedResult != nil:
+		return serializedArgument{Kind: "NestedResult", NestedResult: &[2]uint16{argument.NestedResult.Result1, argument.NestedResult.Result2}}
+	default:
+		return serializedArgument{}
+	}
+}
+
+// toObjectArg converts a resolved object input, it reports whether the object is a receiving object.
+func (object *serializedObjectArg) toObjectArg() (*sui_types.ObjectArg, bool, error) {
+	switch {
+	case object.ImmOrOwnedObject != nil:
+		ref, err := object.ImmOrOwnedObject.toObjectRef()
+		return &sui_types.ObjectArg{ImmOrOwnedObject: ref}, false, err
+	case object.Receiving != nil:
+		ref, err := object.Receiving.toObjectRef()
+		return &sui_types.ObjectArg{ImmOrOwnedObject: ref}, true, err
+	case object.SharedObject != nil:
+		id, err := sui_types.NewObjectIdFromHex(utils.NormalizeSuiObjectID(object.SharedObject.ObjectID))
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid object id [%s]: %v", object.SharedObject.ObjectID, err)
+		}
+		return sharedObjectArg(*id, uint64(object.SharedObject.InitialSharedVersion), object.SharedObject.Mutable), false, nil
+	default:
+		return nil, false, fmt.Errorf("empty object input")
+	}
+}
+
+func (ref serializedObjectRef) toObjectRef() (*sui_types.ObjectRef, error) {
+	return ObjectStringRef{ObjectID: utils.NormalizeSuiObjectID(ref.ObjectID), Version: strconv.FormatUint(uint64(ref.Version), 10), Digest: ref.Digest}.ToObjectRef()
+}
+
+func newSerializedObjectRef(ref *sui_types.ObjectRef) serializedObjectRef {
+	return serializedObjectRef{ObjectID: ref.ObjectId.String(), Version: jsonU64(ref.Version), Digest: ref.Digest.String()}
+}
+
+func decodeModules(modules []string) ([][]byte, error) {
+	decoded := make([][]byte, len(modules))
+	for idx, module := range modules {
+		bs, err := base64.StdEncoding.DecodeString(module)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module %d: %v", idx, err)
+		}
+		decoded[idx] = bs
+	}
+	return decoded, nil
+}
+
+func encodeModules(modules [][]byte) []string {
+	encoded := make([]string, len(modules))
+	for idx, module := range modules {
+		encoded[idx] = base64.StdEncoding.EncodeToString(module)
+	}
+	return encoded
+}
+
+func parseObjectIDs(ids []string) ([]sui_types.ObjectID, error) {
+	objectIDs := make([]sui_types.ObjectID, len(ids))
+	for idx, id := range ids {
+		objectID, err := sui_types.NewObjectIdFromHex(utils.NormalizeSuiObjectID(id))
+		if err != nil {
+			return nil, fmt.Errorf("invalid object id [%s]: %v", id, err)
+		}
+		objectIDs[idx] = *objectID
+	}
+	return objectIDs, nil
+}
+
+func formatObjectIDs(ids []sui_types.ObjectID) []string {
+	formatted := make([]string, len(ids))
+	for idx, id := range ids {
+		formatted[idx] = id.String()
+	}
+	return formatted
+}
@@ -0,0 +1,280 @@
+package transactions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+)
+
+// serializedTransaction returns the JSON of a TypeScript SDK transaction with unresolved inputs and a coin intent.
+func serializedTransaction(pkg, pool, coin, payment string) string {
+	return `{
+		"version": 2,
+		"sender": "0xabc",
+		"expiration": {"$kind": "None", "None": true},
+		"gasData": {"budget": "1000000", "price": 1000, "owner": null, "payment": [{"objectId": "` + payment + `", "version": "1", "digest": "` + zeroDigest + `"}]},
+		"inputs": [
+			{"$kind": "UnresolvedObject", "UnresolvedObject": {"objectId": "` + pool + `", "initialSharedVersion": "3"}},
+			{"$kind": "UnresolvedPure", "UnresolvedPure": {"value": 100}},
+			{"$kind": "UnresolvedPure", "UnresolvedPure": {"value": "pool"}},
+			{"$kind": "UnresolvedPure", "UnresolvedPure": {"value": null}},
+			{"$kind": "UnresolvedPure", "UnresolvedPure": {"value": ["1", 2]}},
+			{"$kind": "UnresolvedPure", "UnresolvedPure": {"value": "18446744073709551615"}},
+			{"$kind": "UnresolvedObject", "UnresolvedObject": {"objectId": "` + coin + `", "version": "2", "digest": "` + zeroDigest + `"}},
+			{"$kind": "UnresolvedPure", "UnresolvedPure": {"value": "0xabc"}}
+		],
+		"commands": [
+			{"$kind": "MoveCall", "MoveCall": {"package": "` + pkg + `", "module": "pool", "function": "create", "typeArguments": [], "arguments": [
+				{"$kind": "Input", "Input": 0, "type": "object"}, {"$kind": "Input", "Input": 1, "type": "pure"}, {"Input": 2}, {"Input": 3}, {"Input": 4}]}},
+			{"$kind": "$Intent", "$Intent": {"name": "CoinWithBalance", "inputs": {}, "data": {"type": "gas", "balance": "500"}}},
+			{"$kind": "SplitCoins", "SplitCoins": {"coin": {"GasCoin": true}, "amounts": [{"Input": 5}]}},
+			{"$kind": "TransferObjects", "TransferObjects": {"objects": [{"Result": 1}, {"NestedResult": [2, 0]}, {"Input": 6}], "address": {"Input": 7}}}
+		]
+	}`
+}
+
+// createFunction returns the signature of `create(&mut Pool, u64, String, Option<address>, vector<u64>, &mut TxContext)`.
+func createFunction(pkg string) string {
+	return `{"visibility": "Public", "isEntry": true, "typeParameters": [], "parameters": [
+		{"MutableReference": {"Struct": {"address": "` + pkg + `", "module": "pool", "name": "Pool", "typeArguments": []}}},
+		"U64",
+		{"Struct": {"address": "0x1", "module": "string", "name": "String", "typeArguments": []}},
+		{"Struct": {"address": "0x1", "module": "option", "name": "Option", "typeArguments": ["Address"]}},
+		{"Vector": "U64"},
+		{"MutableReference": {"Struct": {"address": "0x2", "module": "tx_context", "name": "TxContext", "typeArguments": []}}}], "return": []}`
+}
+
+func TestFromJSON(t *testing.T) {
+	ctx := context.Background()
+	pkg, pool, coin, payment := objectID(0x45001), objectID(0x45002), objectID(0x45003), objectID(0x45004)
+	var function types.SuiMoveNormalizedFunction
+	if err := json.Unmarshal([]byte(createFunction(pkg)), &function); err != nil {
+		t.Fatalf("failed to unmarshal function: %v", err)
+	}
+
+	tx, err := transactions.FromJSON(nil, []byte(serializedTransaction(pkg, pool, coin, payment)))
+	if err != nil {
+		t.Fatalf("failed to import transaction: %v", err)
+	}
+	exported, err := tx.ToJSON()
+	if err != nil {
+		t.Fatalf("failed to export transaction: %v", err)
+	}
+
+	// the signature is needed to encode the pure values passed to the move call
+	var missing *transactions.MissingDataError
+	if _, _, err := tx.Build(ctx, ""); !errors.As(err, &missing) || !reflect.DeepEqual(missing.Missing, []string{"normalized function [" + pkg + "::pool::create]"}) {
+		t.Fatalf("expected the normalized function to be missing, but got %v", err)
+	}
+	if err := tx.SupplyMoveFunction(pkg+"::pool::create", &function); err != nil {
+		t.Fatalf("failed to supply function: %v", err)
+	}
+	data, bs, err := tx.Build(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+
+	if got := data.V1.Sender.String(); got != recipient {
+		t.Errorf("expected sender %s, but got %s", recipient, got)
+	}
+	if gasData := data.V1.GasData; gasData.Budget != 1000000 || gasData.Price != 1000 || len(gasData.Payment) != 1 || gasData.Payment[0].ObjectId.String() != payment {
+		t.Errorf("expected the gas data of the json, but got %+v", gasData)
+	}
+	pt := data.V1.Kind.ProgrammableTransaction
+	if shared := pt.Inputs[0].Object.SharedObject; shared == nil || shared.InitialSharedVersion != 3 || !shared.Mutable {
+		t.Errorf("expected a mutable shared pool, but got %+v", pt.Inputs[0].Object)
+	}
+	if owned := pt.Inputs[6].Object.ImmOrOwnedObject; owned == nil || owned.Version != 2 {
+		t.Errorf("expected an owned coin, but got %+v", pt.Inputs[6].Object)
+	}
+	address := append(make([]byte, 30), 0x0a, 0xbc)
+	expected := map[int][]byte{
+		1: {100, 0, 0, 0, 0, 0, 0, 0},
+		2: []byte("\x04pool"),
+		3: {0},
+		4: {2, 1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0},
+		5: bytes.Repeat([]byte{0xff}, 8),
+		7: address,
+		8: {0xf4, 1, 0, 0, 0, 0, 0, 0}, // balance of the coin intent
+	}
+	for idx, value := range expected {
+		if pt.Inputs[idx].Pure == nil || !bytes.Equal(*pt.Inputs[idx].Pure, value) {
+			t.Errorf("expected pure input %d to be %v, but got %+v", idx, value, pt.Inputs[idx])
+		}
+	}
+	if len(pt.Commands) != 4 || pt.Commands[1].SplitCoins == nil || pt.Commands[1].SplitCoins.Argument.GasCoin == nil {
+		t.Errorf("expected the coin intent to be split from the gas coin, but got %+v", pt.Commands)
+	}
+
+	// the exported json is imported again, it keeps the unresolved inputs and the intent
+	reimported, err := transactions.FromJSON(nil, exported)
+	if err != nil {
+		t.Fatalf("failed to import exported transaction: %v", err)
+	}
+	again, err := reimported.ToJSON()
+	if err != nil {
+		t.Fatalf("failed to export transaction: %v", err)
+	}
+	if !bytes.Equal(exported, again) {
+		t.Errorf("expected json %s, but got %s", exported, again)
+	}
+	if err := reimported.SupplyMoveFunction(pkg+"::pool::create", &function); err != nil {
+		t.Fatalf("failed to supply function: %v", err)
+	}
+	if _, rebuilt, err := reimported.Build(ctx, ""); err != nil || !bytes.Equal(bs, rebuilt) {
+		t.Errorf("expected bytes %v, but got %v, err: %v", bs, rebuilt, err)
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	ctx := context.Background()
+	pool := objectID(0x45002)
+
+	tx := transactions.NewTransaction(nil)
+	coins, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(100))})
+	if err != nil {
+		t.Fatalf("failed to add split coins: %v", err)
+	}
+	vec, err := tx.AddMakeMoveVec("", []transactions.Arg{coins.Nested(0)})
+	if err != nil {
+		t.Fatalf("failed to add make move vec: %v", err)
+	}
+	if _, err := tx.AddMakeMoveVec("u64", []transactions.Arg{transactions.Pure(uint64(200))}); err != nil {
+		t.Fatalf("failed to add make move vec: %v", err)
+	}
+	_, err = tx.AddMoveCall(objectID(0x45001)+"::pool::join", []transactions.Arg{tx.SharedObjectRef(pool, 3, true), vec, tx.ReceivingRef(objectRef(t, objectID(0x45005), 4)), transactions.Pure("name")}, []string{"0x2::sui::SUI"})
+	if err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{tx.Object(objectID(0x45003))}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	tx.SupplyObjectRef(objectRef(t, objectID(0x45003), 2))
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x45004), 1)})
+	tx.SetGasBudget(1000000)
+	tx.SetGasPrice(1000)
+	tx.SetGasOwner(sponsor)
+	tx.SetExpiration(9)
+	tx.SetSender(recipient)
+
+	exported, err := tx.ToJSON()
+	if err != nil {
+		t.Fatalf("failed to export transaction: %v", err)
+	}
+	for _, snippet := range []string{
+		`"version":2`,
+		`"expiration":{"$kind":"Epoch","Epoch":"9"}`,
+		`"owner":"` + sponsor + `"`,
+		`{"$kind":"SharedObject","SharedObject":{"objectId":"` + pool + `","initialSharedVersion":"3","mutable":true}}`,
+		`{"$kind":"Receiving","Receiving":{"objectId":"` + objectID(0x45005) + `","version":"4","digest":"` + zeroDigest + `"}}`,
+		`{"$kind":"UnresolvedObject","UnresolvedObject":{"objectId":"` + objectID(0x45003) + `","version":"2","digest":"` + zeroDigest + `","mutable":true}}`,
+		`"typeArguments":["0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI"]`,
+		`{"$kind":"MakeMoveVec","MakeMoveVec":{"type":null,"elements":[{"$kind":"NestedResult","NestedResult":[0,0]}]}}`,
+		`{"$kind":"MakeMoveVec","MakeMoveVec":{"type":"u64","elements":[{"$kind":"Input","Input":1,"type":"pure"}]}}`,
+		`{"$kind":"Input","Input":2,"type":"object"}`,
+	} {
+		if !strings.Contains(string(exported), snippet) {
+			t.Errorf("expected [%s] in %s", snippet, exported)
+		}
+	}
+
+	_, bs, err := tx.Build(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	imported, err := transactions.FromJSON(nil, exported)
+	if err != nil {
+		t.Fatalf("failed to import transaction: %v", err)
+	}
+	if _, rebuilt, err := imported.Build(ctx, ""); err != nil || !bytes.Equal(bs, rebuilt) {
+		t.Errorf("expected bytes %v, but got %v, err: %v", bs, rebuilt, err)
+	}
+}
+
+func TestFromJSONResolvesWithClient(t *testing.T) {
+	pkg, pool, config := objectID(0x45001), objectID(0x45002), objectID(0x45006)
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_getNormalizedMoveModulesByPackage": normalizedModules,
+	})
+
+	tx, err := transactions.FromJSON(suiClient, []byte(`{"version": 2, "sender": null, "expiration": null,
+		"gasData": {"budget": "1000000", "price": "1000", "owner": null, "payment": [{"objectId": "0x45004", "version": 1, "digest": "`+zeroDigest+`"}]},
+		"inputs": [
+			{"UnresolvedObject": {"objectId": "`+pool+`", "initialSharedVersion": "3"}},
+			{"UnresolvedObject": {"objectId": "`+config+`", "initialSharedVersion": "5"}},
+			{"UnresolvedPure": {"value": "7"}}
+		],
+		"commands": [{"MoveCall": {"package": "`+pkg+`", "module": "pool", "function": "deposit", "typeArguments": [], "arguments": [{"Input": 0}, {"Input": 1}, {"Input": 2}]}}]}`))
+	if err != nil {
+		t.Fatalf("failed to import transaction: %v", err)
+	}
+	tx.SetCache(transactions.NewLRUCache(0))
+
+	data, _, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	if got := node.count("sui_getNormalizedMoveModulesByPackage"); got != 1 {
+		t.Errorf("expected 1 request of the normalized modules, but got %d", got)
+	}
+	pt := data.V1.Kind.ProgrammableTransaction
+	if !pt.Inputs[0].Object.SharedObject.Mutable || pt.Inputs[1].Object.SharedObject.Mutable {
+		t.Errorf("expected a mutable pool and an immutable config, but got %+v and %+v", pt.Inputs[0].Object, pt.Inputs[1].Object)
+	}
+	if !bytes.Equal(*pt.Inputs[2].Pure, []byte{7, 0, 0, 0, 0, 0, 0, 0}) {
+		t.Errorf("expected u64 7, but got %v", *pt.Inputs[2].Pure)
+	}
+}
+
+func TestFromJSONErrors(t *testing.T) {
+	pkg := objectID(0x45001)
+	transaction := func(inputs, commands string) string {
+		return `{"version": 2, "gasData": {"budget": null, "price": null, "owner": null, "payment": null}, "inputs": [` + inputs + `], "commands": [` + commands + `]}`
+	}
+
+	tests := []struct {
+		name  string
+		json  string
+		build bool // the error is returned when the transaction is built
+	}{
+		{name: "invalid json", json: `{"version": 2`},
+		{name: "version 1", json: `{"version": 1, "inputs": [], "commands": []}`},
+		{name: "empty input", json: transaction(`{}`, ``)},
+		{name: "invalid pure bytes", json: transaction(`{"Pure": {"bytes": "!"}}`, ``)},
+		{name: "duplicate object", json: transaction(`{"UnresolvedObject": {"objectId": "0x1"}}, {"UnresolvedObject": {"objectId": "0x1"}}`, ``)},
+		{name: "missing input", json: transaction(``, `{"SplitCoins": {"coin": {"GasCoin": true}, "amounts": [{"Input": 0}]}}`)},
+		{name: "later result", json: transaction(``, `{"TransferObjects": {"objects": [{"Result": 0}], "address": {"GasCoin": true}}}`)},
+		{name: "invalid type argument", json: transaction(``, `{"MoveCall": {"package": "`+pkg+`", "module": "pool", "function": "create", "typeArguments": ["0x2::coin"], "arguments": []}}`)},
+		{name: "unsupported intent", json: transaction(``, `{"$Intent": {"name": "Unknown", "inputs": {}, "data": {}}}`)},
+		{name: "unused pure value", json: transaction(`{"UnresolvedPure": {"value": 1}}`, ``), build: true},
+		{name: "value out of range", json: transaction(`{"UnresolvedPure": {"value": -1}}`, `{"SplitCoins": {"coin": {"GasCoin": true}, "amounts": [{"Input": 0}]}}`), build: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := transactions.FromJSON(nil, []byte(tt.json))
+			if !tt.build {
+				if err == nil {
+					t.Errorf("expected an error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to import transaction: %v", err)
+			}
+			tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x45004), 1)})
+			tx.SetGasBudget(1000000)
+			tx.SetGasPrice(1000)
+			if _, _, err := tx.Build(context.Background(), recipient); err == nil {
+				t.Errorf("expected a build error, but got nil")
+			}
+		})
+	}
+}
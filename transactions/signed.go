@@ -0,0 +1,221 @@
+package transactions
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/gosui/b64"
+	"github.com/W3Tools/gosui/cryptography"
+	"github.com/W3Tools/gosui/multisig"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+	"github.com/W3Tools/gosui/verify"
+	"github.com/fardream/go-bcs/bcs"
+	"golang.org/x/crypto/blake2b"
+)
+
+// transactionDataDigestPrefix defines the name of the hashed type that prefixes the transaction data in the digest.
+const transactionDataDigestPrefix = "TransactionData::"
+
+// SenderSignedData defines BCS-encoded transaction data with the signatures of the sender and the sponsor, as it is
+// submitted to a full node and returned as the raw transaction of an executed transaction.
+type SenderSignedData struct {
+	TransactionData []byte
+	Signatures      []cryptography.SerializedSignature
+}
+
+// TransactionDigest returns the Base58 digest of BCS-encoded transaction data, which is the digest of the transaction
+// once it is executed.
+func TransactionDigest(transactionData []byte) string {
+	digest := blake2b.Sum256(append([]byte(transactionDataDigestPrefix), transactionData...))
+	return lib.Base58(digest[:]).String()
+}
+
+// NewSenderSignedData creates signed transaction data from BCS-encoded transaction data and serialized signatures.
+func NewSenderSignedData(transactionData []byte, signatures ...cryptography.SerializedSignature) (*SenderSignedData, error) {
+	if _, _, err := decodeTransactionData(transactionData); err != nil {
+		return nil, fmt.Errorf("can not decode transaction data: %v", err)
+	}
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("missing signatures")
+	}
+	for idx, signature := range signatures {
+		if err := checkSerializedSignature(signature); err != nil {
+			return nil, fmt.Errorf("invalid signature %d: %v", idx, err)
+		}
+	}
+	return &SenderSignedData{TransactionData: transactionData, Signatures: signatures}, nil
+}
+
+// ParseSenderSignedData decodes BCS-encoded signed transaction data, such as the raw transaction of an executed transaction.
+func ParseSenderSignedData(bs []byte) (*SenderSignedData, error) {
+	d := &bcsDecoder{data: bs}
+	signed, err := d.senderSignedData()
+	if err == nil {
+		err = d.finish()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can not decode sender signed data: %v", err)
+	}
+	return signed, nil
+}
+
+// SenderSignedDataFromResponse decodes the raw transaction of a response requested with ShowRawInput.
+func SenderSignedDataFromResponse(response *types.SuiTransactionBlockResponse) (*SenderSignedData, error) {
+	if response == nil || response.RawTransaction == "" {
+		return nil, fmt.Errorf("missing raw transaction, the response must be requested with ShowRawInput")
+	}
+	bs, err := b64.FromBase64(response.RawTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw transaction: %v", err)
+	}
+	return ParseSenderSignedData(bs)
+}
+
+// Marshal returns the BCS-encoded signed transaction data, a vector with one transaction of an intent message and its signatures.
+func (s *SenderSignedData) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(bcs.ULEB128Encode(1))
+	buf.Write(cryptography.IntentWithScope(cryptography.TransactionData))
+	buf.Write(s.TransactionData)
+	buf.Write(bcs.ULEB128Encode(len(s.Signatures)))
+	for idx, signature := range s.Signatures {
+		bs, err := b64.FromBase64(signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %d: %v", idx, err)
+		}
+		buf.Write(bcs.ULEB128Encode(len(bs)))
+		buf.Write(bs)
+	}
+	return buf.Bytes(), nil
+}
+
+// Digest returns the Base58 digest of the transaction.
+func (s *SenderSignedData) Digest() string {
+	return TransactionDigest(s.TransactionData)
+}
+
+// Verify verifies every signature of the transaction data and that the sender and the gas owner, when it is another
+// address, signed the transaction. Multisig signatures are verified against the multisig public key they include.
+func (s *SenderSignedData) Verify() error {
+	data, _, err := decodeTransactionData(s.TransactionData)
+	if err != nil {
+		return fmt.Errorf("can not decode transaction data: %v", err)
+	}
+
+	signers := make(map[string]bool, len(s.Signatures))
+	for idx, signature := range s.Signatures {
+		signer, err := verifyTransactionSignature(s.TransactionData, signature)
+		if err != nil {
+			return fmt.Errorf("invalid signature %d: %v", idx, err)
+		}
+		if signers[signer] {
+			return fmt.Errorf("duplicate signature %d of [%s]", idx, signer)
+		}
+		signers[signer] = true
+	}
+
+	required := []string{data.V1.Sender.String()}
+	if owner := data.V1.GasData.Owner.String(); owner != required[0] {
+		required = append(required, owner)
+	}
+	for _, address := range required {
+		if !signers[address] {
+			return fmt.Errorf("missing signature of [%s]", address)
+		}
+		delete(signers, address)
+	}
+	for signer := range signers {
+		return fmt.Errorf("unexpected signature of [%s], only the sender and the gas owner sign the transaction", signer)
+	}
+	return nil
+}
+
+// verifyTransactionSignature verifies a serialized signature of transaction data and returns the address of the signer.
+func verifyTransactionSignature(transactionData []byte, signature cryptography.SerializedSignature) (string, error) {
+	if err := checkSerializedSignature(signature); err != nil {
+		return "", err
+	}
+	parsed, err := cryptography.ParseSerializedSignature(signature)
+	if err != nil {
+		return "", err
+	}
+
+	var publicKey cryptography.PublicKey
+	switch parsed.SignatureScheme {
+	case cryptography.MultiSigScheme:
+		publicKey, err = multisig.NewPublicKey(parsed.Multisig.MultisigPubKey)
+	default:
+		publicKey, err = verify.PublicKeyFromRawBytes(parsed.SignatureScheme, parsed.PubKey)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid public key: %v", err)
+	}
+
+	// multisig addresses are returned in their short form
+	address := utils.NormalizeSuiAddress(publicKey.ToSuiAddress())
+	ok, err := publicKey.VerifyTransactionBlock(transactionData, signature)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("signature of [%s] does not match the transaction data", address)
+	}
+	return address, nil
+}
+
+// checkSerializedSignature checks that a serialized signature is long enough to hold its flag, signature and public key.
+func checkSerializedSignature(signature cryptography.SerializedSignature) error {
+	bs, err := b64.FromBase64(signature)
+	if err != nil {
+		return err
+	}
+	if len(bs) == 0 {
+		return fmt.Errorf("empty signature")
+	}
+	scheme, ok := cryptography.SignatureFlagToScheme[bs[0]]
+	if !ok {
+		return fmt.Errorf("unsupported signature flag %d", bs[0])
+	}
+	if len(bs) <= 1+cryptography.SignatureSchemeToSize[scheme] {
+		return fmt.Errorf("%s signature is too short", scheme)
+	}
+	return nil
+}
+
+func (d *bcsDecoder) senderSignedData() (*SenderSignedData, error) {
+	n, err := d.uleb128()
+	if err != nil {
+		return nil, err
+	}
+	if n != 1 {
+		return nil, fmt.Errorf("expected one signed transaction, got %d", n)
+	}
+
+	intent, err := d.bytes(3)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode intent: %v", err)
+	}
+	if !bytes.Equal(intent, cryptography.IntentWithScope(cryptography.TransactionData)) {
+		return nil, fmt.Errorf("unsupported intent %v, expected a transaction data intent", intent)
+	}
+
+	start := d.pos
+	if _, err := d.transactionData(); err != nil {
+		return nil, fmt.Errorf("can not decode transaction data: %v", err)
+	}
+	signed := &SenderSignedData{TransactionData: d.data[start:d.pos]}
+
+	if n, err = d.uleb128(); err != nil {
+		return nil, fmt.Errorf("can not decode signatures: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		signature, err := d.vector()
+		if err != nil {
+			return nil, fmt.Errorf("can not decode signature %d: %v", i, err)
+		}
+		signed.Signatures = append(signed.Signatures, b64.ToBase64(signature))
+	}
+	return signed, nil
+}
@@ -0,0 +1,245 @@
+package transactions_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/b64"
+	"github.com/W3Tools/gosui/cryptography"
+	"github.com/W3Tools/gosui/keypairs/ed25519"
+	"github.com/W3Tools/gosui/keypairs/secp256k1"
+	"github.com/W3Tools/gosui/multisig"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+	"golang.org/x/crypto/blake2b"
+)
+
+// buildTransferData builds BCS-encoded transaction data of a sender whose gas is paid by the gas owner.
+func buildTransferData(t *testing.T, sender, gasOwner string) []byte {
+	t.Helper()
+
+	tx := transactions.NewTransaction(nil)
+	coins, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(100))})
+	if err != nil {
+		t.Fatalf("failed to add split coins: %v", err)
+	}
+	if err := tx.AddTransferObjects([]transactions.Arg{coins.Nested(0)}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	tx.SetGasOwner(gasOwner)
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x46001), 1)})
+	tx.SetGasBudget(1000000)
+	tx.SetGasPrice(1000)
+
+	_, bs, err := tx.Build(context.Background(), sender)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	return bs
+}
+
+func signTransactionData(t *testing.T, signer cryptography.Signer, bs []byte) string {
+	t.Helper()
+
+	signature, err := signer.SignTransactionBlock(bs)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	return signature.Signature
+}
+
+func TestTransactionDigest(t *testing.T) {
+	bs := buildTransferData(t, recipient, recipient)
+
+	hash := blake2b.Sum256(append([]byte("TransactionData::"), bs...))
+	expected := lib.Base58(hash[:]).String()
+	if got := transactions.TransactionDigest(bs); got != expected {
+		t.Errorf("expected digest %s, but got %s", expected, got)
+	}
+	if other := transactions.TransactionDigest(buildTransferData(t, recipient, sponsor)); other == expected {
+		t.Errorf("expected different digests of different transactions, but got %s", other)
+	}
+}
+
+func TestSenderSignedData(t *testing.T) {
+	sender, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 keypair: %v", err)
+	}
+	gasOwner, err := secp256k1.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate secp256k1 keypair: %v", err)
+	}
+	member, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 keypair: %v", err)
+	}
+	senderKey, _ := sender.GetPublicKey()
+	memberKey, _ := member.GetPublicKey()
+	multisigKey, err := new(multisig.PublicKey).FromPublicKeys([]multisig.PublicKeyWeightPair{{PublicKey: senderKey, Weight: 1}, {PublicKey: memberKey, Weight: 1}}, 2)
+	if err != nil {
+		t.Fatalf("failed to create multisig public key: %v", err)
+	}
+
+	single := buildTransferData(t, sender.ToSuiAddress(), sender.ToSuiAddress())
+	sponsored := buildTransferData(t, sender.ToSuiAddress(), gasOwner.ToSuiAddress())
+	shared := buildTransferData(t, multisigKey.ToSuiAddress(), multisigKey.ToSuiAddress())
+	combine := func(signers ...cryptography.Signer) string {
+		signatures := make([]string, len(signers))
+		for i, signer := range signers {
+			signatures[i] = signTransactionData(t, signer, shared)
+		}
+		signature, err := multisigKey.CombinePartialSignatures(signatures)
+		if err != nil {
+			t.Fatalf("failed to combine partial signatures: %v", err)
+		}
+		return signature
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		signatures []string
+		err        string
+	}{
+		{
+			name:       "sender",
+			data:       single,
+			signatures: []string{signTransactionData(t, sender, single)},
+		},
+		{
+			name:       "sender and sponsor",
+			data:       sponsored,
+			signatures: []string{signTransactionData(t, sender, sponsored), signTransactionData(t, gasOwner, sponsored)},
+		},
+		{
+			name:       "multisig sender",
+			data:       shared,
+			signatures: []string{combine(sender, member)},
+		},
+		{
+			name:       "multisig below threshold",
+			data:       shared,
+			signatures: []string{combine(sender)},
+			err:        "does not match the transaction data",
+		},
+		{
+			name:       "signature of other transaction",
+			data:       sponsored,
+			signatures: []string{signTransactionData(t, sender, single), signTransactionData(t, gasOwner, sponsored)},
+			err:        "invalid signature 0",
+		},
+		{
+			name:       "missing sponsor signature",
+			data:       sponsored,
+			signatures: []string{signTransactionData(t, sender, sponsored)},
+			err:        "missing signature of [" + gasOwner.ToSuiAddress() + "]",
+		},
+		{
+			name:       "duplicate signature",
+			data:       single,
+			signatures: []string{signTransactionData(t, sender, single), signTransactionData(t, sender, single)},
+			err:        "duplicate signature 1",
+		},
+		{
+			name:       "unexpected signer",
+			data:       single,
+			signatures: []string{signTransactionData(t, sender, single), signTransactionData(t, gasOwner, single)},
+			err:        "unexpected signature of [" + gasOwner.ToSuiAddress() + "]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := transactions.NewSenderSignedData(tt.data, tt.signatures...)
+			if err != nil {
+				t.Fatalf("failed to create sender signed data: %v", err)
+			}
+
+			bs, err := signed.Marshal()
+			if err != nil {
+				t.Fatalf("failed to marshal sender signed data: %v", err)
+			}
+			parsed, err := transactions.ParseSenderSignedData(bs)
+			if err != nil {
+				t.Fatalf("failed to parse sender signed data: %v", err)
+			}
+			if !reflect.DeepEqual(signed, parsed) {
+				t.Errorf("expected sender signed data %v, but got %v", signed, parsed)
+			}
+			if got, expected := parsed.Digest(), transactions.TransactionDigest(tt.data); got != expected {
+				t.Errorf("expected digest %s, but got %s", expected, got)
+			}
+
+			err = parsed.Verify()
+			if tt.err == "" && err != nil {
+				t.Errorf("failed to verify signatures: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("expected error containing %q, but got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestSenderSignedDataFromResponse(t *testing.T) {
+	sender, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 keypair: %v", err)
+	}
+	data := buildTransferData(t, sender.ToSuiAddress(), sender.ToSuiAddress())
+	signed, err := transactions.NewSenderSignedData(data, signTransactionData(t, sender, data))
+	if err != nil {
+		t.Fatalf("failed to create sender signed data: %v", err)
+	}
+	bs, err := signed.Marshal()
+	if err != nil {
+		t.Fatalf("failed to marshal sender signed data: %v", err)
+	}
+
+	parsed, err := transactions.SenderSignedDataFromResponse(&types.SuiTransactionBlockResponse{RawTransaction: b64.ToBase64(bs)})
+	if err != nil {
+		t.Fatalf("failed to parse raw transaction: %v", err)
+	}
+	if !reflect.DeepEqual(signed, parsed) {
+		t.Errorf("expected sender signed data %v, but got %v", signed, parsed)
+	}
+	if err := parsed.Verify(); err != nil {
+		t.Errorf("failed to verify signatures: %v", err)
+	}
+
+	if _, err := transactions.SenderSignedDataFromResponse(&types.SuiTransactionBlockResponse{}); err == nil {
+		t.Errorf("expected a missing raw transaction error, but got nil")
+	}
+	for _, bs := range [][]byte{{}, append([]byte{2}, bs[1:]...), append([]byte{1, 1}, bs[2:]...), append(bs, 0)} {
+		if _, err := transactions.ParseSenderSignedData(bs); err == nil {
+			t.Errorf("expected an error for bytes %v, but got nil", bs)
+		}
+	}
+}
+
+func TestNewSenderSignedDataErrors(t *testing.T) {
+	data := buildTransferData(t, recipient, recipient)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		signatures []string
+	}{
+		{name: "invalid transaction data", data: []byte{1, 2, 3}, signatures: []string{"AA=="}},
+		{name: "missing signatures", data: data},
+		{name: "invalid base64", data: data, signatures: []string{"not base64"}},
+		{name: "truncated signature", data: data, signatures: []string{b64.ToBase64([]byte{0, 1, 2})}},
+		{name: "unsupported flag", data: data, signatures: []string{b64.ToBase64(append([]byte{9}, make([]byte, 96)...))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := transactions.NewSenderSignedData(tt.data, tt.signatures...); err == nil {
+				t.Errorf("expected an error, but got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// BuildKind resolves the inputs and returns the BCS-encoded transaction kind without sender and gas data.
+// The bytes are sent to a sponsor, who wraps them into transaction data with NewSponsoredTransaction.
+func (txb *Transaction) BuildKind(ctx context.Context) ([]byte, error) {
+	if txb.client == nil {
+		if missing := txb.missingInputData(); len(missing) > 0 {
+			return nil, &MissingDataError{Missing: missing}
+		}
+	}
+	if err := txb.prepare(ctx); err != nil {
+		return nil, fmt.Errorf("can not resolve inputs when building transaction kind: %w", err)
+	}
+
+	bs, err := txb.marshalProgrammableTransaction(txb.builder.Finish())
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal transaction kind: %v", err)
+	}
+	return bs, nil
+}
+
+// FromKind creates a Transaction from BCS-encoded programmable transaction kind bytes.
+func FromKind(client *client.SuiClient, kind []byte) (*Transaction, error) {
+	transactionKind, receiving, err := decodeTransactionKind(kind)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode transaction kind: %v", err)
+	}
+
+	return newTransactionFromProgrammable(client, *transactionKind.ProgrammableTransaction, receiving)
+}
+
+// FromBytes creates a Transaction from BCS-encoded transaction data bytes, keeping the sender, gas data and expiration.
+// Building the decoded transaction without changes returns the same bytes.
+func FromBytes(client *client.SuiClient, bs []byte) (*Transaction, error) {
+	data, receiving, err := decodeTransactionData(bs)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode transaction data: %v", err)
+	}
+
+	v1 := data.V1
+	txb, err := newTransactionFromProgrammable(client, *v1.Kind.ProgrammableTransaction, receiving)
+	if err != nil {
+		return nil, err
+	}
+
+	sender := v1.Sender
+	txb.Sender = &sender
+	if v1.GasData.Owner != v1.Sender {
+		txb.SetGasOwner(v1.GasData.Owner.String())
+	}
+	txb.SetGasPrice(v1.GasData.Price)
+	txb.SetGasBudget(v1.GasData.Budget)
+	txb.SetGasPayment(v1.GasData.Payment)
+	if v1.Expiration.Epoch != nil {
+		txb.SetExpiration(*v1.Expiration.Epoch)
+	}
+	return txb, nil
+}
+
+// DecodeTransactionData decodes BCS-encoded transaction data bytes to inspect the sender, gas data and expiration.
+// Receiving objects are decoded as immutable or owned objects.
+func DecodeTransactionData(bs []byte) (*sui_types.TransactionData, error) {
+	data, _, err := decodeTransactionData(bs)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode transaction data: %v", err)
+	}
+	return data, nil
+}
+
+// NewSponsoredTransaction creates a transaction from the kind bytes of the sender, the gas is owned and paid by the sponsor.
+// The gas payment is fetched from the coins of the sponsor when it is not set before building.
+func NewSponsoredTransaction(client *client.SuiClient, kind []byte, sender, sponsor string) (*Transaction, error) {
+	txb, err := FromKind(client, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	senderAddress, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(sender))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address [%s]: %v", sender, err)
+	}
+	if _, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(sponsor)); err != nil {
+		return nil, fmt.Errorf("invalid sponsor address [%s]: %v", sponsor, err)
+	}
+
+	txb.Sender = senderAddress
+	txb.SetGasOwner(sponsor)
+	return txb, nil
+}
+
+// newTransactionFromProgrammable creates a Transaction with the inputs and commands of a programmable transaction,
+// pure inputs are kept separate so the transaction is encoded to the same bytes.
+func newTransactionFromProgrammable(client *client.SuiClient, pt sui_types.ProgrammableTransaction, receiving []string) (*Transaction, error) {
+	txb := NewTransaction(client)
+	for _, id := range receiving {
+		txb.receivingObjects[id] = true
+	}
+
+	for idx, input := range pt.Inputs {
+		switch {
+		case input.Pure != nil:
+			txb.builder.PureBytes(*input.Pure, true)
+		case input.Object != nil:
+			if _, err := txb.addObjectInput(*input.Object, input.Object.ImmOrOwnedObject != nil && txb.receivingObjects[input.Object.ImmOrOwnedObject.ObjectId.String()]); err != nil {
+				return nil, fmt.Errorf("invalid object input at index %d: %v", idx, err)
+			}
+		default:
+			return nil, fmt.Errorf("empty input at index %d", idx)
+		}
+
+		if len(txb.builder.InputsKeyOrder) != idx+1 {
+			return nil, fmt.Errorf("duplicate object input at index %d", idx)
+		}
+	}
+	txb.builder.Commands = append(txb.builder.Commands, pt.Commands...)
+
+	return txb, nil
+}
+
+// transactionData creates the transaction data, the gas is owned by the gas owner when it is set and by the sender otherwise.
+func (txb *Transaction) transactionData(payment []*sui_types.ObjectRef, budget, price uint64) (sui_types.TransactionData, error) {
+	owner := txb.Sender
+	if txb.GasConfig.Owner != "" {
+		address, err := sui_types.NewAddressFromHex(utils.NormalizeSuiAddress(txb.GasConfig.Owner))
+		if err != nil {
+			return sui_types.TransactionData{}, fmt.Errorf("invalid gas owner [%s]: %v", txb.GasConfig.Owner, err)
+		}
+		owner = address
+	}
+
+	tx := sui_types.NewProgrammableAllowSponsor(*txb.Sender, payment, txb.builder.Finish(), budget, price, *owner)
+	if txb.expiration != nil {
+		epoch := *txb.expiration
+		tx.V1.Expiration = sui_types.TransactionExpiration{Epoch: &epoch}
+	}
+	return tx, nil
+}
@@ -0,0 +1,271 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+)
+
+const sponsor = "0x0000000000000000000000000000000000000000000000000000000000000def"
+
+func TestSponsoredTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	owned, err := sui_types.NewObjectIdFromHex(objectID(0x29001))
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+
+	tx := transactions.NewTransaction(nil)
+	coins, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(100))})
+	if err != nil {
+		t.Fatalf("failed to add split coins: %v", err)
+	}
+	object := tx.ObjectRef(&sui_types.ObjectRef{ObjectId: *owned, Version: 3, Digest: make([]byte, 32)})
+	if err := tx.AddTransferObjects([]transactions.Arg{coins.Nested(0), object}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+
+	kind, err := tx.BuildKind(ctx)
+	if err != nil {
+		t.Fatalf("failed to build transaction kind: %v", err)
+	}
+
+	sponsored, err := transactions.NewSponsoredTransaction(nil, kind, recipient, sponsor)
+	if err != nil {
+		t.Fatalf("failed to create sponsored transaction: %v", err)
+	}
+	payment := []*sui_types.ObjectRef{{ObjectId: *owned, Version: 1, Digest: make([]byte, 32)}}
+	sponsored.SetGasPayment(payment)
+	sponsored.SetGasBudget(1000000)
+	sponsored.SetGasPrice(1000)
+
+	data, _, err := sponsored.Build(ctx, sponsor)
+	if err != nil {
+		t.Fatalf("failed to build sponsored transaction: %v", err)
+	}
+
+	if got := data.V1.Sender.String(); got != recipient {
+		t.Errorf("expected sender %s, but got %s", recipient, got)
+	}
+	if got := data.V1.GasData.Owner.String(); got != sponsor {
+		t.Errorf("expected gas owner %s, but got %s", sponsor, got)
+	}
+	if !reflect.DeepEqual(payment, data.V1.GasData.Payment) {
+		t.Errorf("expected gas payment %v, but got %v", payment, data.V1.GasData.Payment)
+	}
+
+	rebuilt, err := sponsored.BuildKind(ctx)
+	if err != nil {
+		t.Fatalf("failed to build transaction kind: %v", err)
+	}
+	if !reflect.DeepEqual(kind, rebuilt) {
+		t.Errorf("expected transaction kind %v, but got %v", kind, rebuilt)
+	}
+}
+
+func TestSponsoredCoinWithBalanceUsesCoinsOfSender(t *testing.T) {
+	coins := newCoins(100, 80)
+	tests := []struct {
+		name  string
+		build func(tx *transactions.Transaction) (*sui_types.ProgrammableTransaction, error)
+	}{
+		{
+			name: "gas owned by a sponsor",
+			build: func(tx *transactions.Transaction) (*sui_types.ProgrammableTransaction, error) {
+				if _, err := tx.CoinWithBalance("0x2::sui::SUI", 150); err != nil {
+					return nil, err
+				}
+				tx.SetGasOwner(sponsor)
+				tx.SetGasPrice(1000)
+				tx.SetGasBudget(1000000)
+				tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x29101), 1)})
+				data, _, err := tx.Build(context.Background(), recipient)
+				if err != nil {
+					return nil, err
+				}
+				return data.V1.Kind.ProgrammableTransaction, nil
+			},
+		},
+		{
+			name: "transaction kind for a sponsor",
+			build: func(tx *transactions.Transaction) (*sui_types.ProgrammableTransaction, error) {
+				if _, err := tx.CoinWithBalanceFromSender("0x2::sui::SUI", 150); err != nil {
+					return nil, err
+				}
+				tx.SetSender(recipient)
+				kind, err := tx.BuildKind(context.Background())
+				if err != nil {
+					return nil, err
+				}
+				sponsored, err := transactions.NewSponsoredTransaction(nil, kind, recipient, sponsor)
+				if err != nil {
+					return nil, err
+				}
+				builder := sponsored.TransactionBuilder().Finish()
+				return &builder, nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+				"suix_getCoins": func(params []json.RawMessage) (any, error) {
+					var owner string
+					if err := json.Unmarshal(params[0], &owner); err != nil {
+						return nil, err
+					}
+					if owner != recipient {
+						return nil, fmt.Errorf("unexpected coin owner [%s]", owner)
+					}
+					return getCoins(coins)(params)
+				},
+			})
+
+			tx := transactions.NewTransaction(suiClient)
+			pt, err := tt.build(tx)
+			if err != nil {
+				t.Fatalf("failed to build transaction: %v", err)
+			}
+			if got := node.count("suix_getCoins"); got != 1 {
+				t.Errorf("expected 1 get coins request, but got %d", got)
+			}
+
+			// merge the coins of the sender, split from the merged coin
+			if len(pt.Commands) != 2 || pt.Commands[0].MergeCoins == nil || pt.Commands[1].SplitCoins == nil {
+				t.Fatalf("expected merge and split commands, but got %+v", pt.Commands)
+			}
+			source := pt.Commands[1].SplitCoins.Argument
+			if source.GasCoin != nil || source.Input == nil {
+				t.Fatalf("expected split from a coin of the sender, but got %+v", source)
+			}
+			if ref := pt.Inputs[*source.Input].Object.ImmOrOwnedObject; ref == nil || ref.ObjectId.String() != coins[0].CoinObjectID {
+				t.Errorf("expected split from coin %s, but got %+v", coins[0].CoinObjectID, pt.Inputs[*source.Input])
+			}
+		})
+	}
+}
+
+func TestFromKindErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		kind []byte
+	}{
+		{name: "empty bytes", kind: []byte{}},
+		{name: "not a programmable transaction", kind: []byte{1}},
+		{name: "truncated inputs", kind: []byte{0, 2, 0, 1}},
+		{name: "length exceeds bytes", kind: []byte{0, 0xff, 0xff, 0xff, 0xff, 0x07}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := transactions.FromKind(nil, tt.kind); err == nil {
+				t.Errorf("expected an error, but got nil")
+			}
+		})
+	}
+
+	if _, err := transactions.NewSponsoredTransaction(nil, []byte{0, 0, 0}, recipient, "not an address"); err == nil {
+		t.Errorf("expected an invalid sponsor error, but got nil")
+	}
+}
+
+func TestFromBytes(t *testing.T) {
+	ctx := context.Background()
+	payment := []*sui_types.ObjectRef{objectRef(t, objectID(0x44001), 1)}
+
+	tests := []struct {
+		name  string
+		build func(tx *transactions.Transaction) error
+	}{
+		{
+			name: "split and transfer",
+			build: func(tx *transactions.Transaction) error {
+				coins, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(100)), transactions.Pure(uint64(200))})
+				if err != nil {
+					return err
+				}
+				return tx.AddTransferObjects([]transactions.Arg{coins.Nested(0), coins.Nested(1)}, transactions.Pure(sui_types.SuiAddress{}))
+			},
+		},
+		{
+			name: "sponsored with expiration",
+			build: func(tx *transactions.Transaction) error {
+				tx.SetGasOwner(sponsor)
+				tx.SetExpiration(42)
+				_, err := tx.AddMoveCall(objectID(0x44002)+"::pool::deposit", []transactions.Arg{tx.SharedObjectRef(objectID(0x44003), 7, true), tx.Gas(), transactions.Pure(uint64(1))}, nil)
+				return err
+			},
+		},
+		{
+			name: "owned and receiving objects",
+			build: func(tx *transactions.Transaction) error {
+				_, err := tx.AddMoveCall(objectID(0x44002)+"::pool::receive", []transactions.Arg{tx.ObjectRef(objectRef(t, objectID(0x44004), 2)), tx.ReceivingRef(objectRef(t, objectID(0x44005), 3))}, nil)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := transactions.NewTransaction(nil)
+			if err := tt.build(tx); err != nil {
+				t.Fatalf("failed to add commands: %v", err)
+			}
+			tx.SetGasPayment(payment)
+			tx.SetGasBudget(1000000)
+			tx.SetGasPrice(1000)
+			_, bs, err := tx.Build(ctx, recipient)
+			if err != nil {
+				t.Fatalf("failed to build transaction: %v", err)
+			}
+
+			decoded, err := transactions.FromBytes(nil, bs)
+			if err != nil {
+				t.Fatalf("failed to decode transaction: %v", err)
+			}
+			if got := decoded.Sender.String(); got != recipient {
+				t.Errorf("expected sender %s, but got %s", recipient, got)
+			}
+			if !reflect.DeepEqual(tx.Expiration(), decoded.Expiration()) {
+				t.Errorf("expected expiration %v, but got %v", tx.Expiration(), decoded.Expiration())
+			}
+			_, rebuilt, err := decoded.Build(ctx, sponsor)
+			if err != nil {
+				t.Fatalf("failed to build decoded transaction: %v", err)
+			}
+			if !reflect.DeepEqual(bs, rebuilt) {
+				t.Errorf("expected bytes %v, but got %v", bs, rebuilt)
+			}
+
+			// the decoded transaction is edited before signing
+			commands := len(decoded.TransactionBuilder().Commands)
+			decoded.SetGasBudget(2000000)
+			if err := decoded.AddTransferObjects([]transactions.Arg{decoded.ObjectRef(objectRef(t, objectID(0x44006), 4))}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+				t.Fatalf("failed to add transfer objects: %v", err)
+			}
+			data, _, err := decoded.Build(ctx, sponsor)
+			if err != nil {
+				t.Fatalf("failed to build edited transaction: %v", err)
+			}
+			if got := data.V1.GasData.Budget; got != 2000000 {
+				t.Errorf("expected gas budget 2000000, but got %d", got)
+			}
+			if got := len(data.V1.Kind.ProgrammableTransaction.Commands); got != commands+1 {
+				t.Errorf("expected %d commands, but got %d", commands+1, got)
+			}
+		})
+	}
+
+	// the length of the inputs is checked against the remaining bytes before the inputs are allocated
+	for _, bs := range [][]byte{{}, {0, 0, 0}, {1}, {0, 0, 0xff, 0xff, 0xff, 0xff, 0x07}} {
+		if _, err := transactions.FromBytes(nil, bs); err == nil {
+			t.Errorf("expected an error for bytes %v, but got nil", bs)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// StakeStatus defines the status of a stake as reported by the Sui node.
+type StakeStatus string
+
+const (
+	// StakeStatusPending defines a stake that becomes active in the next epoch.
+	StakeStatusPending StakeStatus = "Pending"
+	// StakeStatusActive defines a stake that earns rewards.
+	StakeStatusActive StakeStatus = "Active"
+	// StakeStatusUnstaked defines a stake whose validator left the validator set.
+	StakeStatusUnstaked StakeStatus = "Unstaked"
+)
+
+// StakeSummary defines a stake of an owner with the validator it is delegated to.
+type StakeSummary struct {
+	StakedSuiID       string
+	Status            StakeStatus
+	Principal         uint64
+	EstimatedReward   uint64 // zero unless the stake is active
+	StakeRequestEpoch uint64
+	StakeActiveEpoch  uint64
+	ValidatorAddress  string
+	ValidatorName     string // empty when the validator is not active
+	StakingPool       string
+	ValidatorApy      float64
+}
+
+// AddStake splits the amount from the gas coin and stakes it with the validator.
+func (txb *Transaction) AddStake(validator string, amount uint64) (err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	address, err := sui_types.NewAddressFromHex(validator)
+	if err != nil {
+		return fmt.Errorf("invalid validator [%s]: %v", validator, err)
+	}
+	if amount < utils.MinStakeAmount {
+		return fmt.Errorf("stake amount %d is less than the minimum stake amount %d", amount, utils.MinStakeAmount)
+	}
+
+	systemState, err := txb.systemStateArg()
+	if err != nil {
+		return err
+	}
+	coins, err := txb.AddSplitCoins(txb.Gas(), []Arg{Pure(amount)})
+	if err != nil {
+		return err
+	}
+	_, err = txb.AddMoveCall(fmt.Sprintf("%s::%s::request_add_stake", utils.SuiSystemAddress, utils.SuiSystemModuleName), []Arg{systemState, coins.Nested(0), Pure(*address)}, nil)
+	return err
+}
+
+// WithdrawStake withdraws each StakedSui object, the principal and the rewards are transferred to the sender.
+func (txb *Transaction) WithdrawStake(stakedSuiIDs ...string) (err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	if len(stakedSuiIDs) == 0 {
+		return fmt.Errorf("missing staked sui ids")
+	}
+
+	systemState, err := txb.systemStateArg()
+	if err != nil {
+		return err
+	}
+	for _, id := range stakedSuiIDs {
+		if !utils.IsValidSuiObjectID(utils.NormalizeSuiObjectID(id)) {
+			return fmt.Errorf("invalid staked sui id [%s]", id)
+		}
+		if _, err := txb.AddMoveCall(fmt.Sprintf("%s::%s::request_withdraw_stake", utils.SuiSystemAddress, utils.SuiSystemModuleName), []Arg{systemState, txb.Object(id)}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// systemStateArg adds the Sui system state object as a mutable shared input, it does not need to be resolved.
+func (txb *Transaction) systemStateArg() (Arg, error) {
+	id, err := sui_types.NewObjectIdFromHex(utils.SuiSystemStateObjectID)
+	if err != nil {
+		return nil, err
+	}
+	argument, err := txb.objectInput(*sharedObjectArg(*id, suiSystemStateInitialSharedVersion, true))
+	if err != nil {
+		return nil, fmt.Errorf("can not add sui system state: %v", err)
+	}
+	return inputArg{index: *argument.Input}, nil
+}
+
+// GetStakeSummaries returns the stakes of the owner with the status, rewards, name and APY of their validators.
+func GetStakeSummaries(ctx context.Context, suiClient *client.SuiClient, owner string) ([]StakeSummary, error) {
+	if suiClient == nil {
+		return nil, fmt.Errorf("missing sui client to get stakes")
+	}
+
+	delegated, err := suiClient.GetStakes(ctx, types.GetStakesParams{Owner: owner})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stakes, err: %v", err)
+	}
+	apys, err := suiClient.GetValidatorsApy(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get validators apy, err: %v", err)
+	}
+	systemState, err := suiClient.GetLatestSuiSystemState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest sui system state, err: %v", err)
+	}
+
+	apyByValidator := make(map[string]float64, len(apys.APYs))
+	for _, apy := range apys.APYs {
+		apyByValidator[utils.NormalizeSuiAddress(apy.Address)] = apy.APY
+	}
+	nameByValidator := make(map[string]string, len(systemState.ActiveValidators))
+	for _, validator := range systemState.ActiveValidators {
+		nameByValidator[utils.NormalizeSuiAddress(validator.SuiAddress)] = validator.Name
+	}
+
+	var summaries []StakeSummary
+	for _, stake := range delegated {
+		if stake == nil {
+			continue
+		}
+		validator := utils.NormalizeSuiAddress(stake.ValidatorAddress)
+		for _, object := range stake.Stakes {
+			summary, err := newStakeSummary(object.StakeObject)
+			if err != nil {
+				return nil, err
+			}
+			summary.ValidatorAddress = validator
+			summary.ValidatorName = nameByValidator[validator]
+			summary.StakingPool = stake.StakingPool
+			summary.ValidatorApy = apyByValidator[validator]
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
+
+// newStakeSummary parses the numbers of a stake object.
+func newStakeSummary(object types.StakeObject) (StakeSummary, error) {
+	var summary StakeSummary
+	var principal, requestEpoch, activeEpoch, reward string
+	switch stake := object.(type) {
+	case types.StakeObjectPending:
+		summary.StakedSuiID, summary.Status = stake.StakedSuiID, StakeStatusPending
+		principal, requestEpoch, activeEpoch = stake.Principal, stake.StakeRequestEpoch, stake.StakeActiveEpoch
+	case types.StakeObjectActive:
+		summary.StakedSuiID, summary.Status = stake.StakedSuiID, StakeStatusActive
+		principal, requestEpoch, activeEpoch, reward = stake.Principal, stake.StakeRequestEpoch, stake.StakeActiveEpoch, stake.EstimatedReward
+	case types.StakeObjectUnstaked:
+		summary.StakedSuiID, summary.Status = stake.StakedSuiID, StakeStatusUnstaked
+		principal, requestEpoch, activeEpoch = stake.Principal, stake.StakeRequestEpoch, stake.StakeActiveEpoch
+	default:
+		return StakeSummary{}, fmt.Errorf("unknown stake object %T", object)
+	}
+
+	var err error
+	for _, field := range []struct {
+		value  string
+		target *uint64
+	}{{principal, &summary.Principal}, {requestEpoch, &summary.StakeRequestEpoch}, {activeEpoch, &summary.StakeActiveEpoch}, {reward, &summary.EstimatedReward}} {
+		if field.value == "" {
+			continue
+		}
+		if *field.target, err = strconv.ParseUint(field.value, 10, 64); err != nil {
+			return StakeSummary{}, fmt.Errorf("invalid number [%s] of stake [%s]: %v", field.value, summary.StakedSuiID, err)
+		}
+	}
+	return summary, nil
+}
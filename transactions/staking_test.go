@@ -0,0 +1,104 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/utils"
+)
+
+const validator = "0x0000000000000000000000000000000000000000000000000000000000033001"
+
+func TestAddStake(t *testing.T) {
+	tx := transactions.NewTransaction(nil)
+	if err := tx.AddStake(validator, utils.MinStakeAmount-1); err == nil {
+		t.Errorf("expected a minimum stake amount error, but got nil")
+	}
+	if err := tx.AddStake("validator", utils.MinStakeAmount); err == nil {
+		t.Errorf("expected an invalid validator error, but got nil")
+	}
+	if err := tx.AddStake(validator, utils.MinStakeAmount); err != nil {
+		t.Fatalf("failed to add stake: %v", err)
+	}
+
+	if _, err := tx.BuildKind(context.Background()); err != nil {
+		t.Fatalf("failed to build transaction kind: %v", err)
+	}
+	builder := tx.TransactionBuilder()
+	if len(builder.Commands) != 2 || builder.Commands[0].SplitCoins == nil || builder.Commands[1].MoveCall == nil {
+		t.Fatalf("expected split coins and move call commands, but got %+v", builder.Commands)
+	}
+	if call := builder.Commands[1].MoveCall; call.Function != "request_add_stake" || call.Module != "sui_system" {
+		t.Errorf("expected request_add_stake call, but got %s::%s", call.Module, call.Function)
+	}
+	input := builder.Inputs[builder.InputsKeyOrder[0].String()]
+	if shared := input.Object.SharedObject; shared == nil || shared.InitialSharedVersion != 1 || !shared.Mutable || shared.Id.String() != utils.SuiSystemStateObjectID {
+		t.Errorf("expected mutable sui system state, but got %+v", input.Object)
+	}
+}
+
+func TestWithdrawStake(t *testing.T) {
+	tx := transactions.NewTransaction(nil)
+	if err := tx.WithdrawStake(); err == nil {
+		t.Errorf("expected a missing staked sui ids error, but got nil")
+	}
+	if err := tx.WithdrawStake(objectID(0x33101), "staked sui"); err == nil {
+		t.Errorf("expected an invalid staked sui id error, but got nil")
+	}
+	if builder := tx.TransactionBuilder(); len(builder.Commands) != 0 || len(builder.InputsKeyOrder) != 0 {
+		t.Fatalf("expected an empty transaction, but got %d commands and %d inputs", len(builder.Commands), len(builder.InputsKeyOrder))
+	}
+
+	if err := tx.WithdrawStake(objectID(0x33101), objectID(0x33102)); err != nil {
+		t.Fatalf("failed to withdraw stake: %v", err)
+	}
+	builder := tx.TransactionBuilder()
+	if len(builder.Commands) != 2 || len(builder.InputsKeyOrder) != 3 {
+		t.Fatalf("expected 2 commands and 3 inputs, but got %d commands and %d inputs", len(builder.Commands), len(builder.InputsKeyOrder))
+	}
+	for _, command := range builder.Commands {
+		if command.MoveCall == nil || command.MoveCall.Function != "request_withdraw_stake" || *command.MoveCall.Arguments[0].Input != 0 {
+			t.Errorf("expected request_withdraw_stake call with the sui system state, but got %+v", command)
+		}
+	}
+}
+
+func TestGetStakeSummaries(t *testing.T) {
+	inactive := "0x0000000000000000000000000000000000000000000000000000000000033002"
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getStakes": func(params []json.RawMessage) (any, error) {
+			return []map[string]any{
+				{"validatorAddress": validator, "stakingPool": objectID(0x33201), "stakes": []map[string]any{
+					{"stakedSuiId": objectID(0x33301), "status": "Active", "principal": "2000000000", "stakeRequestEpoch": "10", "stakeActiveEpoch": "11", "estimatedReward": "5000"},
+					{"stakedSuiId": objectID(0x33302), "status": "Pending", "principal": "1000000000", "stakeRequestEpoch": "20", "stakeActiveEpoch": "21"},
+				}},
+				{"validatorAddress": inactive, "stakingPool": objectID(0x33202), "stakes": []map[string]any{
+					{"stakedSuiId": objectID(0x33303), "status": "Unstaked", "principal": "3000000000", "stakeRequestEpoch": "1", "stakeActiveEpoch": "2"},
+				}},
+			}, nil
+		},
+		"suix_getValidatorsApy": func(params []json.RawMessage) (any, error) {
+			return map[string]any{"epoch": "21", "apys": []map[string]any{{"address": validator, "apy": 0.05}}}, nil
+		},
+		"suix_getLatestSuiSystemState": func(params []json.RawMessage) (any, error) {
+			return map[string]any{"epoch": "21", "activeValidators": []map[string]any{{"suiAddress": validator, "name": "validator one"}}}, nil
+		},
+	})
+
+	summaries, err := transactions.GetStakeSummaries(context.Background(), suiClient, recipient)
+	if err != nil {
+		t.Fatalf("failed to get stake summaries: %v", err)
+	}
+
+	expected := []transactions.StakeSummary{
+		{StakedSuiID: objectID(0x33301), Status: transactions.StakeStatusActive, Principal: 2000000000, EstimatedReward: 5000, StakeRequestEpoch: 10, StakeActiveEpoch: 11, ValidatorAddress: validator, ValidatorName: "validator one", StakingPool: objectID(0x33201), ValidatorApy: 0.05},
+		{StakedSuiID: objectID(0x33302), Status: transactions.StakeStatusPending, Principal: 1000000000, StakeRequestEpoch: 20, StakeActiveEpoch: 21, ValidatorAddress: validator, ValidatorName: "validator one", StakingPool: objectID(0x33201), ValidatorApy: 0.05},
+		{StakedSuiID: objectID(0x33303), Status: transactions.StakeStatusUnstaked, Principal: 3000000000, StakeRequestEpoch: 1, StakeActiveEpoch: 2, ValidatorAddress: inactive, StakingPool: objectID(0x33202)},
+	}
+	if !reflect.DeepEqual(expected, summaries) {
+		t.Errorf("expected stake summaries %+v, but got %+v", expected, summaries)
+	}
+}
@@ -0,0 +1,281 @@
+package transactions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// Variants of the execution failure status reported by Sui nodes.
+const (
+	FailureInsufficientGas           = "InsufficientGas"
+	FailureInsufficientCoinBalance   = "InsufficientCoinBalance"
+	FailureCoinBalanceOverflow       = "CoinBalanceOverflow"
+	FailureMoveAbort                 = "MoveAbort"
+	FailureMovePrimitiveRuntimeError = "MovePrimitiveRuntimeError"
+	FailureFunctionNotFound          = "FunctionNotFound"
+	FailureCommandArgumentError      = "CommandArgumentError"
+	FailureTypeArgumentError         = "TypeArgumentError"
+	FailureUnusedValueWithoutDrop    = "UnusedValueWithoutDrop"
+	FailureInputObjectDeleted        = "InputObjectDeleted"
+	FailureSharedObjectCongestion    = "ExecutionCancelledDueToSharedObjectCongestion"
+)
+
+var (
+	defaultAbortCodeRegistry = NewAbortCodeRegistry()
+
+	commandSuffixPattern = regexp.MustCompile(` in command (\d+)$`)
+	innerCommandPattern  = regexp.MustCompile(`command: Some\((\d+)\)`)
+	moveAbortPattern     = regexp.MustCompile(`^\(MoveLocation \{ module: ModuleId \{ address: (?:0x)?([0-9a-fA-F]+), name: Identifier\("(\w+)"\) \}, function: (\d+), instruction: (\d+), function_name: (?:Some\("(\w+)"\)|None) \}, (\d+)\)$`)
+	objectVersionPattern = regexp.MustCompile(`Object ID (0x[0-9a-fA-F]+)(?: Version 0x([0-9a-fA-F]+))?.*? is not available for consumption(?:, current version: 0x([0-9a-fA-F]+))?`)
+	objectVersionVariant = regexp.MustCompile(`provided_obj_ref: \((0x[0-9a-fA-F]+), SequenceNumber\((\d+)\), [^)]*\), current_version: SequenceNumber\((\d+)\)`)
+	variantNamePattern   = regexp.MustCompile(`^[A-Z]\w*`)
+)
+
+// ExecutionError defines an error of a transaction that failed when it was executed, dry run or dev inspected.
+type ExecutionError struct {
+	Kind    string // variant of the execution failure status, one of the Failure constants or another variant
+	Details string // fields of the variant, empty when it has none
+	Command int    // index of the command, -1 when the error is not about one command
+	Message string // error reported by the node
+}
+
+// Error implements the error interface.
+func (e *ExecutionError) Error() string {
+	return e.Message
+}
+
+// MoveAbortError defines an error of a transaction that aborted in a Move function.
+type MoveAbortError struct {
+	Package       string
+	Module        string
+	Function      string // empty when the node does not report the name of the function
+	FunctionIndex uint64
+	Instruction   uint64
+	Code          uint64
+	Command       int   // index of the command, -1 when the error is not about one command
+	Named         error // error registered for the abort code, nil when the code is not registered
+	Message       string
+}
+
+// Error implements the error interface.
+func (e *MoveAbortError) Error() string {
+	location := fmt.Sprintf("%s::%s", e.Package, e.Module)
+	if e.Function != "" {
+		location = fmt.Sprintf("%s::%s", location, e.Function)
+	}
+	message := fmt.Sprintf("move abort in %s with code %d", location, e.Code)
+	if e.Named != nil {
+		message = fmt.Sprintf("%s (%v)", message, e.Named)
+	}
+	if e.Command >= 0 {
+		message = fmt.Sprintf("%s in command %d", message, e.Command)
+	}
+	return message
+}
+
+// Unwrap returns the error registered for the abort code, errors.Is matches the registered error.
+func (e *MoveAbortError) Unwrap() error {
+	return e.Named
+}
+
+// ObjectVersionUnavailableError defines an error of a transaction with an input object version that was already consumed.
+type ObjectVersionUnavailableError struct {
+	ObjectID       string // empty when the node does not report the object
+	Version        uint64 // zero when the node does not report the version
+	CurrentVersion uint64 // zero when the node does not report the current version
+	Message        string
+}
+
+// Error implements the error interface.
+func (e *ObjectVersionUnavailableError) Error() string {
+	return e.Message
+}
+
+// AbortCodeRegistry holds the errors of abort codes by Move module, it is safe for concurrent use.
+type AbortCodeRegistry struct {
+	mutex  sync.RWMutex
+	errors map[string]map[uint64]error // map key is the module name with a full length address
+}
+
+// NewAbortCodeRegistry creates an empty AbortCodeRegistry.
+func NewAbortCodeRegistry() *AbortCodeRegistry {
+	return &AbortCodeRegistry{errors: make(map[string]map[uint64]error)}
+}
+
+// DefaultAbortCodeRegistry returns the AbortCodeRegistry used by ParseExecutionError, DryRunError, DevInspectError and
+// ExecutionResponseError.
+func DefaultAbortCodeRegistry() *AbortCodeRegistry {
+	return defaultAbortCodeRegistry
+}
+
+// Register registers the error of an abort code of a module in the format `address::module`. Aborts are reported with the
+// address of the original package, which is the address the package was first published at.
+func (r *AbortCodeRegistry) Register(module string, code uint64, err error) error {
+	entry := strings.Split(module, "::")
+	if len(entry) != 2 || entry[1] == "" {
+		return fmt.Errorf("invalid module [%s]", module)
+	}
+	if err == nil {
+		return fmt.Errorf("missing error of abort code %d in module [%s]", code, module)
+	}
+	name := fmt.Sprintf("%s::%s", utils.NormalizeSuiObjectID(entry[0]), entry[1])
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.errors[name] == nil {
+		r.errors[name] = make(map[uint64]error)
+	}
+	r.errors[name][code] = err
+	return nil
+}
+
+// RegisterModule registers the errors of abort codes of a module in the format `address::module`.
+func (r *AbortCodeRegistry) RegisterModule(module string, errors map[uint64]error) error {
+	for code, err := range errors {
+		if err := r.Register(module, code, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup returns the error registered for an abort code of a module, nil when the code is not registered.
+func (r *AbortCodeRegistry) Lookup(pkg, module string, code uint64) error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.errors[fmt.Sprintf("%s::%s", utils.NormalizeSuiObjectID(pkg), module)][code]
+}
+
+// Parse parses the error of an execution status, a dev inspect result or a rejected transaction into a *MoveAbortError,
+// an *ObjectVersionUnavailableError or an *ExecutionError, nil is returned for an empty message.
+func (r *AbortCodeRegistry) Parse(message string) error {
+	if message == "" {
+		return nil
+	}
+	if strings.Contains(message, "ObjectVersionUnavailableForConsumption") || strings.Contains(message, "not available for consumption") {
+		return parseObjectVersionUnavailable(message)
+	}
+
+	status, command := message, -1
+	if idx := strings.Index(message, "kind: "); idx >= 0 && strings.Contains(message, "ExecutionErrorInner") {
+		// dev inspect errors wrap the status: ExecutionError { inner: ExecutionErrorInner { kind: ..., command: Some(0) } }
+		status = message[idx+len("kind: "):]
+		if match := innerCommandPattern.FindStringSubmatch(message); match != nil {
+			command, _ = strconv.Atoi(match[1])
+		}
+	} else if match := commandSuffixPattern.FindStringSubmatchIndex(message); match != nil {
+		status = message[:match[0]]
+		command, _ = strconv.Atoi(message[match[2]:match[3]])
+	}
+
+	kind, details := splitVariant(status)
+	if kind == FailureMoveAbort {
+		if abort := r.parseMoveAbort(details, command, message); abort != nil {
+			return abort
+		}
+	}
+	return &ExecutionError{Kind: kind, Details: details, Command: command, Message: message}
+}
+
+func (r *AbortCodeRegistry) parseMoveAbort(details string, command int, message string) *MoveAbortError {
+	match := moveAbortPattern.FindStringSubmatch(details)
+	if match == nil {
+		return nil
+	}
+	abort := &MoveAbortError{Package: utils.NormalizeSuiObjectID(match[1]), Module: match[2], Function: match[5], Command: command, Message: message}
+	var err1, err2, err3 error
+	abort.FunctionIndex, err1 = strconv.ParseUint(match[3], 10, 64)
+	abort.Instruction, err2 = strconv.ParseUint(match[4], 10, 64)
+	abort.Code, err3 = strconv.ParseUint(match[6], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil
+	}
+	abort.Named = r.Lookup(abort.Package, abort.Module, abort.Code)
+	return abort
+}
+
+// splitVariant splits a status into the name of its variant and the fields that follow the name.
+func splitVariant(status string) (string, string) {
+	kind := variantNamePattern.FindString(status)
+	rest := status[len(kind):]
+	if kind == "" || rest == "" {
+		return kind, ""
+	}
+
+	start := 0
+	if strings.HasPrefix(rest, " {") {
+		start = 1
+	} else if rest[0] != '(' {
+		return kind, ""
+	}
+	depth := 0
+	for idx := start; idx < len(rest); idx++ {
+		switch rest[idx] {
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+			if depth == 0 {
+				return kind, rest[start : idx+1]
+			}
+		}
+	}
+	return kind, rest[start:]
+}
+
+func parseObjectVersionUnavailable(message string) *ObjectVersionUnavailableError {
+	unavailable := &ObjectVersionUnavailableError{Message: message}
+	if match := objectVersionVariant.FindStringSubmatch(message); match != nil {
+		unavailable.ObjectID = utils.NormalizeSuiObjectID(match[1])
+		unavailable.Version, _ = strconv.ParseUint(match[2], 10, 64)
+		unavailable.CurrentVersion, _ = strconv.ParseUint(match[3], 10, 64)
+	} else if match := objectVersionPattern.FindStringSubmatch(message); match != nil {
+		unavailable.ObjectID = utils.NormalizeSuiObjectID(match[1])
+		unavailable.Version, _ = strconv.ParseUint(match[2], 16, 64)
+		unavailable.CurrentVersion, _ = strconv.ParseUint(match[3], 16, 64)
+	}
+	return unavailable
+}
+
+// ParseExecutionError parses the error of an execution status with the default AbortCodeRegistry.
+func ParseExecutionError(message string) error {
+	return defaultAbortCodeRegistry.Parse(message)
+}
+
+// DryRunError returns the error of a dry run that failed, nil when it succeeded.
+func DryRunError(response *types.DryRunTransactionBlockResponse) error {
+	return statusError(response.Effects.Status)
+}
+
+// DevInspectError returns the error of a dev inspect that failed, nil when it succeeded.
+func DevInspectError(results *types.DevInspectResults) error {
+	if results.Error != "" && results.Effects.Status.Error == "" {
+		return ParseExecutionError(results.Error)
+	}
+	return statusError(results.Effects.Status)
+}
+
+// ExecutionResponseError returns the error of an executed transaction that failed, nil when it succeeded or the response
+// was requested without effects.
+func ExecutionResponseError(response *types.SuiTransactionBlockResponse) error {
+	if response.Effects == nil {
+		return nil
+	}
+	return statusError(response.Effects.Status)
+}
+
+func statusError(status types.ExecutionStatus) error {
+	if status.Status == "success" {
+		return nil
+	}
+	if status.Error == "" {
+		return &ExecutionError{Command: -1, Message: "transaction failed without an error"}
+	}
+	return ParseExecutionError(status.Error)
+}
@@ -0,0 +1,248 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/keypairs/ed25519"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+)
+
+var errSlippage = errors.New("ESlippage")
+
+// moveAbortStatus returns the execution status error of an abort in the swap function of the pool module.
+func moveAbortStatus(pkg, code string) string {
+	return `MoveAbort(MoveLocation { module: ModuleId { address: ` + strings.TrimPrefix(pkg, "0x") + `, name: Identifier("pool") }, function: 3, instruction: 12, function_name: Some("swap") }, ` + code + `)`
+}
+
+func TestParseExecutionError(t *testing.T) {
+	pkg, object := objectID(0x50001), objectID(0x50002)
+	registry := transactions.NewAbortCodeRegistry()
+	if err := registry.Register(pkg+"::pool", 2, errSlippage); err != nil {
+		t.Fatalf("failed to register abort code: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		message  string
+		expected error
+	}{
+		{
+			name:     "registered abort code",
+			message:  moveAbortStatus(pkg, "2") + " in command 1",
+			expected: &transactions.MoveAbortError{Package: pkg, Module: "pool", Function: "swap", FunctionIndex: 3, Instruction: 12, Code: 2, Command: 1, Named: errSlippage},
+		},
+		{
+			name:     "unregistered abort code",
+			message:  `MoveAbort(MoveLocation { module: ModuleId { address: 0x2, name: Identifier("balance") }, function: 9, instruction: 7, function_name: None }, 2)`,
+			expected: &transactions.MoveAbortError{Package: objectID(2), Module: "balance", FunctionIndex: 9, Instruction: 7, Code: 2, Command: -1},
+		},
+		{
+			name: "dev inspect abort",
+			message: `ExecutionError: ExecutionError { inner: ExecutionErrorInner { kind: ` + moveAbortStatus(pkg, "2") + `, source: Some(VMError { major_status: ABORTED, sub_status: Some(2), message: None, exec_state: None, ` +
+				`location: Module(ModuleId { address: ` + strings.TrimPrefix(pkg, "0x") + `, name: Identifier("pool") }), indices: [], offsets: [(FunctionDefinitionIndex(3), 12)] }), command: Some(0) } }`,
+			expected: &transactions.MoveAbortError{Package: pkg, Module: "pool", Function: "swap", FunctionIndex: 3, Instruction: 12, Code: 2, Command: 0, Named: errSlippage},
+		},
+		{
+			name:     "insufficient gas",
+			message:  "InsufficientGas",
+			expected: &transactions.ExecutionError{Kind: transactions.FailureInsufficientGas, Command: -1},
+		},
+		{
+			name:     "command argument error",
+			message:  "CommandArgumentError { arg_idx: 0, kind: TypeMismatch } in command 2",
+			expected: &transactions.ExecutionError{Kind: transactions.FailureCommandArgumentError, Details: "{ arg_idx: 0, kind: TypeMismatch }", Command: 2},
+		},
+		{
+			name:     "dev inspect error",
+			message:  "ExecutionError: ExecutionError { inner: ExecutionErrorInner { kind: UnusedValueWithoutDrop { result_idx: 0, secondary_idx: 1 }, source: None, command: None } }",
+			expected: &transactions.ExecutionError{Kind: transactions.FailureUnusedValueWithoutDrop, Details: "{ result_idx: 0, secondary_idx: 1 }", Command: -1},
+		},
+		{
+			name:     "abort without location",
+			message:  "MoveAbort in command 0",
+			expected: &transactions.ExecutionError{Kind: transactions.FailureMoveAbort, Command: 0},
+		},
+		{
+			name:     "object version unavailable",
+			message:  "Transaction validator signing failed due to issues with transaction inputs, please review the errors and try again:\n- Object ID " + object + " Version 0x5 Digest " + zeroDigest + " is not available for consumption, current version: 0x1a",
+			expected: &transactions.ObjectVersionUnavailableError{ObjectID: object, Version: 5, CurrentVersion: 26},
+		},
+		{
+			name:     "object version unavailable variant",
+			message:  "ObjectVersionUnavailableForConsumption { provided_obj_ref: (" + object + ", SequenceNumber(5), o#" + zeroDigest + "), current_version: SequenceNumber(26) }",
+			expected: &transactions.ObjectVersionUnavailableError{ObjectID: object, Version: 5, CurrentVersion: 26},
+		},
+		{
+			name:    "empty",
+			message: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the message is the error reported by the node
+			switch expected := tt.expected.(type) {
+			case *transactions.MoveAbortError:
+				expected.Message = tt.message
+			case *transactions.ExecutionError:
+				expected.Message = tt.message
+			case *transactions.ObjectVersionUnavailableError:
+				expected.Message = tt.message
+			}
+
+			if err := registry.Parse(tt.message); !reflect.DeepEqual(tt.expected, err) {
+				t.Errorf("expected %#v, but got %#v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestMoveAbortError(t *testing.T) {
+	pkg := objectID(0x50003)
+	registry := transactions.NewAbortCodeRegistry()
+	if err := registry.RegisterModule("0x50003::pool", map[uint64]error{2: errSlippage}); err != nil {
+		t.Fatalf("failed to register abort codes: %v", err)
+	}
+	if got := registry.Lookup(pkg, "pool", 2); got != errSlippage {
+		t.Errorf("expected the registered error, but got %v", got)
+	}
+
+	err := fmt.Errorf("transaction failed: %w", registry.Parse(moveAbortStatus(pkg, "2")+" in command 1"))
+	if !errors.Is(err, errSlippage) {
+		t.Errorf("expected the error to match the registered error, but got %v", err)
+	}
+	expected := "move abort in " + pkg + "::pool::swap with code 2 (ESlippage) in command 1"
+	if !strings.HasSuffix(err.Error(), expected) {
+		t.Errorf("expected error %q, but got %q", expected, err.Error())
+	}
+
+	for _, module := range []string{"pool", "0x1::", "0x1::pool::swap"} {
+		if err := registry.Register(module, 1, errSlippage); err == nil {
+			t.Errorf("expected an error for module %q, but got nil", module)
+		}
+	}
+	if err := registry.Register(pkg+"::pool", 1, nil); err == nil {
+		t.Errorf("expected an error for a nil error, but got nil")
+	}
+}
+
+func TestExecutionResultErrors(t *testing.T) {
+	pkg := objectID(0x50004)
+	if err := transactions.DefaultAbortCodeRegistry().Register(pkg+"::pool", 2, errSlippage); err != nil {
+		t.Fatalf("failed to register abort code: %v", err)
+	}
+	failure := types.ExecutionStatus{Status: "failure", Error: moveAbortStatus(pkg, "2") + " in command 1"}
+	success := types.ExecutionStatus{Status: "success"}
+
+	var abort *transactions.MoveAbortError
+	if err := transactions.DryRunError(&types.DryRunTransactionBlockResponse{Effects: types.TransactionEffects{Status: failure}}); !errors.As(err, &abort) || abort.Command != 1 {
+		t.Errorf("expected a move abort in command 1, but got %v", err)
+	}
+	if err := transactions.ExecutionResponseError(&types.SuiTransactionBlockResponse{Effects: &types.TransactionEffects{Status: failure}}); !errors.Is(err, errSlippage) {
+		t.Errorf("expected the registered error, but got %v", err)
+	}
+	if err := transactions.DevInspectError(&types.DevInspectResults{Effects: types.TransactionEffects{Status: failure}, Error: "ExecutionError: ..."}); !errors.Is(err, errSlippage) {
+		t.Errorf("expected the registered error, but got %v", err)
+	}
+	if err := transactions.DevInspectError(&types.DevInspectResults{Effects: types.TransactionEffects{Status: success}, Error: "InsufficientGas"}); err == nil {
+		t.Errorf("expected the dev inspect error, but got nil")
+	}
+	if err := transactions.DryRunError(&types.DryRunTransactionBlockResponse{Effects: types.TransactionEffects{Status: success}}); err != nil {
+		t.Errorf("expected no error, but got %v", err)
+	}
+	if err := transactions.ExecutionResponseError(&types.SuiTransactionBlockResponse{}); err != nil {
+		t.Errorf("expected no error without effects, but got %v", err)
+	}
+
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_dryRunTransactionBlock": func([]json.RawMessage) (any, error) {
+			return map[string]any{"effects": map[string]any{"status": failure}}, nil
+		},
+	})
+	tx := transactions.NewTransaction(suiClient)
+	if err := tx.AddTransferObjects([]transactions.Arg{tx.ObjectRef(objectRef(t, objectID(0x50005), 1))}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+		t.Fatalf("failed to add transfer objects: %v", err)
+	}
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x50006), 1)})
+	tx.SetGasPrice(1000)
+	if _, _, err := tx.Build(context.Background(), recipient); !errors.As(err, &abort) || !errors.Is(err, errSlippage) {
+		t.Errorf("expected the move abort of the gas estimate dry run, but got %v", err)
+	}
+}
+
+func TestTransactionReturnsExecutionErrors(t *testing.T) {
+	pkg := objectID(0x50005)
+	signer, err := ed25519.GenerateKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate keypair: %v", err)
+	}
+	effects := map[string]any{"status": map[string]any{"status": "failure", "error": moveAbortStatus(pkg, "2") + " in command 1"}}
+	suiClient, _ := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_dryRunTransactionBlock": func([]json.RawMessage) (any, error) {
+			return map[string]any{"effects": effects}, nil
+		},
+		"sui_devInspectTransactionBlock": func([]json.RawMessage) (any, error) {
+			return map[string]any{"effects": effects}, nil
+		},
+		"sui_executeTransactionBlock": func([]json.RawMessage) (any, error) {
+			return map[string]any{"digest": zeroDigest, "effects": effects}, nil
+		},
+	})
+
+	tests := []struct {
+		name    string
+		execute func(tx *transactions.Transaction) (any, error)
+	}{
+		{
+			name: "dry run",
+			execute: func(tx *transactions.Transaction) (any, error) {
+				return tx.DryRunTransactionBlock(context.Background())
+			},
+		},
+		{
+			name: "dev inspect",
+			execute: func(tx *transactions.Transaction) (any, error) {
+				return tx.DevInspectTransactionBlock(context.Background())
+			},
+		},
+		{
+			name: "sign and execute",
+			execute: func(tx *transactions.Transaction) (any, error) {
+				return tx.SignAndExecuteTransactionBlock(context.Background(), signer, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := transactions.NewTransaction(suiClient)
+			tx.SetSender(signer.ToSuiAddress())
+			tx.SetGasPrice(1000)
+			tx.SetGasBudget(1000000)
+			tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x50006), 1)})
+			coins, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{transactions.Pure(uint64(1))})
+			if err != nil {
+				t.Fatalf("failed to add split coins: %v", err)
+			}
+			if err := tx.AddTransferObjects([]transactions.Arg{coins.Nested(0)}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+				t.Fatalf("failed to add transfer objects: %v", err)
+			}
+
+			response, err := tt.execute(tx)
+			var abort *transactions.MoveAbortError
+			if !errors.As(err, &abort) || abort.Command != 1 || abort.Code != 2 {
+				t.Errorf("expected a move abort with code 2 in command 1, but got %v", err)
+			}
+			if reflect.ValueOf(response).IsNil() {
+				t.Errorf("expected the response with the error")
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// systemObject defines a well-known shared object that is injected for an omitted parameter of its type.
+type systemObject struct {
+	id                   string
+	initialSharedVersion uint64 // zero when the version differs between networks
+}
+
+// Initial shared versions of the system objects created at genesis.
+const (
+	suiClockInitialSharedVersion       = 1
+	suiSystemStateInitialSharedVersion = 1
+)
+
+// systemObjects defines the injected system objects by the name of their struct. The clock and the system state are
+// created at genesis, randomness and the deny list are created by protocol upgrades at a version that depends on the network.
+var systemObjects = map[string]systemObject{
+	utils.SuiFrameworkAddress + "::clock::Clock":            {id: utils.SuiClockObjectID, initialSharedVersion: suiClockInitialSharedVersion},
+	utils.SuiSystemAddress + "::sui_system::SuiSystemState": {id: utils.SuiSystemStateObjectID, initialSharedVersion: suiSystemStateInitialSharedVersion},
+	utils.SuiFrameworkAddress + "::random::Random":          {id: utils.SuiRandomObjectID},
+	utils.SuiFrameworkAddress + "::deny_list::DenyList":     {id: utils.SuiDenyListObjectID},
+}
+
+// systemObjectParameter returns the system object passed to a parameter and whether it is passed by mutable reference,
+// it returns false when the parameter is not a reference to a system object.
+func systemObjectParameter(parameter types.SuiMoveNormalizedType) (systemObject, bool, bool) {
+	var mutable bool
+	switch t := parameter.(type) {
+	case types.SuiMoveNormalizedTypeReference:
+		parameter = t.Reference.SuiMoveNormalizedType
+	case types.SuiMoveNormalizedTypeMutableReference:
+		parameter, mutable = t.MutableReference.SuiMoveNormalizedType, true
+	default:
+		return systemObject{}, false, false
+	}
+
+	structType, ok := parameter.(types.SuiMoveNormalizedTypeStruct)
+	if !ok {
+		return systemObject{}, false, false
+	}
+	object, ok := systemObjects[fmt.Sprintf("%s::%s::%s", utils.NormalizeSuiAddress(structType.Struct.Address), structType.Struct.Module, structType.Struct.Name)]
+	return object, mutable, ok
+}
+
+// arg returns the argument of the system object. The version of randomness and the deny list is taken from the supplied
+// objects or the cache, where it is kept once resolved, and the object is resolved like other shared objects otherwise.
+func (object systemObject) arg(txb *Transaction, mutable bool) Arg {
+	version := object.initialSharedVersion
+	if supplied, ok := txb.suppliedObjects[object.id]; ok && version == 0 && supplied.initialSharedVersion != nil {
+		version = *supplied.initialSharedVersion
+	}
+	if entry := txb.Cache().GetSharedObject(object.id); entry != nil && version == 0 && entry.InitialSharedVersion != nil {
+		version = *entry.InitialSharedVersion
+	}
+	if version == 0 {
+		return txb.SharedObject(object.id, mutable)
+	}
+	return txb.SharedObjectRef(object.id, version, mutable)
+}
+
+// omitsSystemObjects reports whether the parameters include system objects which are all omitted by the arguments.
+func omitsSystemObjects(parameters []*types.SuiMoveNormalizedTypeWrapper, arguments int) bool {
+	var system int
+	for _, parameter := range parameters {
+		if _, _, ok := systemObjectParameter(parameter.SuiMoveNormalizedType); ok {
+			system++
+		}
+	}
+	return system > 0 && arguments == len(parameters)-system
+}
+
+// withSystemObjects returns the arguments with the system objects inserted at their parameters when all of them are
+// omitted, otherwise the arguments are returned unchanged.
+func withSystemObjects[T any](txb *Transaction, parameters []*types.SuiMoveNormalizedTypeWrapper, arguments []T, convert func(Arg) T) []T {
+	if !omitsSystemObjects(parameters, len(arguments)) {
+		return arguments
+	}
+
+	injected := make([]T, 0, len(parameters))
+	for _, parameter := range parameters {
+		if object, mutable, ok := systemObjectParameter(parameter.SuiMoveNormalizedType); ok {
+			injected = append(injected, convert(object.arg(txb, mutable)))
+			continue
+		}
+		injected = append(injected, arguments[0])
+		arguments = arguments[1:]
+	}
+	return injected
+}
+
+// injectSystemObjects inserts the omitted system objects into the Move calls added before their signatures were known.
+// Signatures that are not cached are fetched once per package when the transaction has a SuiClient, as the arguments of a
+// call omitting system objects can not be told apart from a call with missing arguments.
+func (txb *Transaction) injectSystemObjects(ctx context.Context) error {
+	commands := slices.Clone(txb.builder.Commands)
+	fetched := make(map[string]bool)
+	for idx, command := range commands {
+		moveCall := command.MoveCall
+		if moveCall == nil {
+			continue
+		}
+		pkg := moveCall.Package.String()
+		entry := txb.Cache().GetMoveFunctionDefinition(pkg, string(moveCall.Module), string(moveCall.Function))
+		if entry == nil && txb.client != nil && !fetched[pkg] {
+			if err := cacheNormalizedMoveModules(ctx, txb.client, txb.Cache(), pkg); err != nil {
+				return fmt.Errorf("can not get normalized move modules of package [%s]: %v", pkg, err)
+			}
+			fetched[pkg] = true
+			entry = txb.Cache().GetMoveFunctionDefinition(pkg, string(moveCall.Module), string(moveCall.Function))
+		}
+		if entry == nil {
+			continue
+		}
+
+		parameters := moveFunctionParameters(entry.Normalized)
+		if !omitsSystemObjects(parameters, len(moveCall.Arguments)) {
+			continue
+		}
+
+		unresolvedParameter := NewUnresolvedParameter(len(parameters))
+		arguments := moveCall.Arguments
+		for i, parameter := range parameters {
+			if object, mutable, ok := systemObjectParameter(parameter.SuiMoveNormalizedType); ok {
+				if err := unresolvedParameter.setArg(txb, i, object.arg(txb, mutable), false); err != nil {
+					return fmt.Errorf("can not inject system object [%s] in command %d: %v", object.id, idx, err)
+				}
+				continue
+			}
+			unresolvedParameter.Arguments[i] = &UnresolvedArgument{Argument: &arguments[0]}
+			arguments = arguments[1:]
+		}
+		injected, err := unresolvedParameter.toArguments(txb)
+		if err != nil {
+			return fmt.Errorf("can not inject system objects in command %d: %v", idx, err)
+		}
+
+		call := *moveCall
+		call.Arguments = injected
+		commands[idx] = sui_types.Command{MoveCall: &call}
+	}
+
+	txb.builder.Commands = commands
+	return nil
+}
@@ -0,0 +1,197 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// swapFunction returns the signature of `swap(&mut Pool, &Clock, u64, &Random, &mut SuiSystemState, &mut TxContext)`.
+func swapFunction(pkg string) string {
+	structType := func(address, module, name string) string {
+		return `{"Struct": {"address": "` + address + `", "module": "` + module + `", "name": "` + name + `", "typeArguments": []}}`
+	}
+	return `{"visibility": "Public", "isEntry": true, "typeParameters": [], "parameters": [
+		{"MutableReference": ` + structType(pkg, "pool", "Pool") + `},
+		{"Reference": ` + structType("0x2", "clock", "Clock") + `},
+		"U64",
+		{"Reference": ` + structType("0x2", "random", "Random") + `},
+		{"MutableReference": ` + structType("0x3", "sui_system", "SuiSystemState") + `},
+		{"MutableReference": ` + structType("0x2", "tx_context", "TxContext") + `}], "return": []}`
+}
+
+func TestInjectSystemObjects(t *testing.T) {
+	pkg, pool := objectID(0x43001), objectID(0x43002)
+	target := pkg + "::pool::swap"
+	var function types.SuiMoveNormalizedFunction
+	if err := json.Unmarshal([]byte(swapFunction(pkg)), &function); err != nil {
+		t.Fatalf("failed to unmarshal function: %v", err)
+	}
+
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_getNormalizedMoveModulesByPackage": func([]json.RawMessage) (any, error) {
+			return json.RawMessage(`{"pool": {"fileFormatVersion": 6, "address": "` + pkg + `", "name": "pool", "friends": [], "structs": {},
+				"exposedFunctions": {"swap": ` + swapFunction(pkg) + `}}}`), nil
+		},
+	})
+
+	tests := []struct {
+		name  string
+		add   func(tx *transactions.Transaction) error
+		fetch int // requests of the normalized modules
+	}{
+		{
+			name: "supplied signature",
+			add: func(tx *transactions.Transaction) error {
+				if err := tx.SupplyMoveFunction(target, &function); err != nil {
+					return err
+				}
+				_, err := tx.AddMoveCall(target, []transactions.Arg{tx.Object(pool), transactions.Pure(uint64(5))}, nil)
+				return err
+			},
+		},
+		{
+			name: "signature fetched at build",
+			add: func(tx *transactions.Transaction) error {
+				_, err := tx.AddMoveCall(target, []transactions.Arg{tx.Object(pool), transactions.Pure(uint64(5))}, nil)
+				return err
+			},
+			fetch: 1,
+		},
+		{
+			name: "move call",
+			add: func(tx *transactions.Transaction) error {
+				if err := tx.SupplyMoveFunction(target, &function); err != nil {
+					return err
+				}
+				_, err := tx.MoveCall(context.Background(), target, []any{pool, uint64(5)}, nil)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := node.count("sui_getNormalizedMoveModulesByPackage")
+			tx := transactions.NewTransaction(suiClient)
+			tx.SetCache(transactions.NewLRUCache(0))
+			if err := tx.AddTransferObjects([]transactions.Arg{tx.ObjectRef(objectRef(t, objectID(0x43003), 1))}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+				t.Fatalf("failed to add transfer objects: %v", err)
+			}
+			if err := tt.add(tx); err != nil {
+				t.Fatalf("failed to add move call: %v", err)
+			}
+			if err := tx.SupplySharedObject(pool, 3); err != nil {
+				t.Fatalf("failed to supply pool: %v", err)
+			}
+			if err := tx.SupplySharedObject(utils.SuiRandomObjectID, 42); err != nil {
+				t.Fatalf("failed to supply random: %v", err)
+			}
+			tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x43004), 1)})
+			tx.SetGasBudget(1000000)
+			tx.SetGasPrice(1000)
+
+			data, _, err := tx.Build(context.Background(), recipient)
+			if err != nil {
+				t.Fatalf("failed to build transaction: %v", err)
+			}
+			if got := node.count("sui_getNormalizedMoveModulesByPackage") - requests; got != tt.fetch {
+				t.Errorf("expected %d requests of the normalized modules, but got %d", tt.fetch, got)
+			}
+
+			pt := data.V1.Kind.ProgrammableTransaction
+			moveCall := pt.Commands[1].MoveCall
+			if moveCall == nil || len(moveCall.Arguments) != 5 {
+				t.Fatalf("expected a move call with 5 arguments, but got %+v", pt.Commands[1])
+			}
+			expected := []struct {
+				id      string
+				version uint64
+				mutable bool
+			}{{pool, 3, true}, {utils.SuiClockObjectID, 1, false}, {"", 0, false}, {utils.SuiRandomObjectID, 42, false}, {utils.SuiSystemStateObjectID, 1, true}}
+			for i, argument := range moveCall.Arguments {
+				input := pt.Inputs[*argument.Input]
+				if expected[i].id == "" {
+					if input.Pure == nil {
+						t.Errorf("expected argument %d to be pure, but got %+v", i, input)
+					}
+					continue
+				}
+				shared := input.Object.SharedObject
+				if shared == nil || shared.Id.String() != expected[i].id || shared.InitialSharedVersion != expected[i].version || shared.Mutable != expected[i].mutable {
+					t.Errorf("expected argument %d to be shared object %+v, but got %+v", i, expected[i], input.Object)
+				}
+			}
+		})
+	}
+
+	// explicit system objects are kept
+	tx := transactions.NewTransaction(nil)
+	tx.SetCache(transactions.NewLRUCache(0))
+	if err := tx.SupplyMoveFunction(target, &function); err != nil {
+		t.Fatalf("failed to supply function: %v", err)
+	}
+	arguments := []transactions.Arg{tx.Object(pool), tx.SharedObjectRef(utils.SuiClockObjectID, 1, false), transactions.Pure(uint64(5)), tx.Object(utils.SuiRandomObjectID), tx.SharedObjectRef(utils.SuiSystemStateObjectID, 1, true)}
+	if _, err := tx.AddMoveCall(target, arguments, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	if got := len(tx.TransactionBuilder().Commands[0].MoveCall.Arguments); got != 5 {
+		t.Errorf("expected 5 arguments, but got %d", got)
+	}
+}
+
+func TestInjectSystemObjectsWithPureArguments(t *testing.T) {
+	pkg := objectID(0x43005)
+	// tick(amount: u64, clock: &Clock, random: &Random)
+	tick := `{"visibility": "Public", "isEntry": true, "typeParameters": [], "parameters": ["U64",
+		{"Reference": {"Struct": {"address": "0x2", "module": "clock", "name": "Clock", "typeArguments": []}}},
+		{"Reference": {"Struct": {"address": "0x2", "module": "random", "name": "Random", "typeArguments": []}}}], "return": []}`
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_getNormalizedMoveModulesByPackage": func([]json.RawMessage) (any, error) {
+			return json.RawMessage(`{"timer": {"fileFormatVersion": 6, "address": "` + pkg + `", "name": "timer", "friends": [], "structs": {},
+				"exposedFunctions": {"tick": ` + tick + `}}}`), nil
+		},
+	})
+
+	tx := transactions.NewTransaction(suiClient)
+	cache := transactions.NewLRUCache(0)
+	random, err := sui_types.NewObjectIdFromHex(utils.SuiRandomObjectID)
+	if err != nil {
+		t.Fatalf("failed to parse object id: %v", err)
+	}
+	version := uint64(42)
+	cache.AddSharedObject(&transactions.SharedObjectCacheEntry{ObjectID: random, InitialSharedVersion: &version})
+	tx.SetCache(cache)
+	if _, err := tx.AddMoveCall(pkg+"::timer::tick", []transactions.Arg{transactions.Pure(uint64(5))}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x43006), 1)})
+	tx.SetGasBudget(1000000)
+	tx.SetGasPrice(1000)
+
+	// the signature is fetched for a call without object arguments, randomness is injected with its cached version
+	data, _, err := tx.Build(context.Background(), recipient)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	if fetched, resolved := node.count("sui_getNormalizedMoveModulesByPackage"), node.count("sui_multiGetObjects"); fetched != 1 || resolved != 0 {
+		t.Errorf("expected 1 request of the normalized modules and no object requests, but got %d and %d", fetched, resolved)
+	}
+	pt := data.V1.Kind.ProgrammableTransaction
+	if arguments := pt.Commands[0].MoveCall.Arguments; len(arguments) != 3 {
+		t.Fatalf("expected a move call with 3 arguments, but got %+v", pt.Commands[0])
+	}
+	for i, expected := range []struct {
+		id      string
+		version uint64
+	}{{utils.SuiClockObjectID, 1}, {utils.SuiRandomObjectID, 42}} {
+		shared := pt.Inputs[*pt.Commands[0].MoveCall.Arguments[i+1].Input].Object.SharedObject
+		if shared == nil || shared.Id.String() != expected.id || shared.InitialSharedVersion != expected.version || shared.Mutable {
+			t.Errorf("expected argument %d to be shared object %+v, but got %+v", i+1, expected, shared)
+		}
+	}
+}
@@ -2,15 +2,15 @@ package transactions
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/W3Tools/go-sui-sdk/v2/move_types"
 	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
 	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/cryptography"
 	"github.com/W3Tools/gosui/types"
 	"github.com/W3Tools/gosui/utils"
-	"github.com/fardream/go-bcs/bcs"
 )
 
 // Transaction defines a programmable transaction builder for Sui.
@@ -18,6 +18,18 @@ type Transaction struct {
 	client  *client.SuiClient
 	builder *sui_types.ProgrammableTransactionBuilder
 
+	unresolvedObjects     map[string]UnresolvedObject // map key is normalized object id
+	intents               map[uint16]coinIntent       // map key is the index of the placeholder command
+	receivingObjects      map[string]bool             // map key is normalized object id
+	suppliedObjects       map[string]suppliedObject   // map key is normalized object id
+	unresolvedPures       map[uint16]json.RawMessage  // map key is the index of the input
+	coinSelectionStrategy CoinSelectionStrategy
+	cache                 Cache
+	gasEstimator          GasEstimator
+	gasEstimate           *GasEstimate
+	expiration            *uint64
+	protocolLimits        *ProtocolLimits
+
 	Sender    *sui_types.SuiAddress `json:"sender"`
 	GasConfig *GasData              `json:"gasConfig"`
 }
@@ -41,11 +53,19 @@ func NewTransaction(client *client.SuiClient) *Transaction {
 		client:  client,
 		builder: sui_types.NewProgrammableTransactionBuilder(),
 
+		unresolvedObjects: make(map[string]UnresolvedObject),
+		intents:           make(map[uint16]coinIntent),
+		receivingObjects:  make(map[string]bool),
+		suppliedObjects:   make(map[string]suppliedObject),
+		unresolvedPures:   make(map[uint16]json.RawMessage),
+
 		GasConfig: new(GasData),
 	}
 }
 
 // TransactionBuilder returns the underlying ProgrammableTransactionBuilder.
+// Objects referenced by ID are placeholder inputs until the transaction is built.
+// Commands are added atomically, a failed command leaves the inputs and commands of the builder unchanged.
 func (txb *Transaction) TransactionBuilder() *sui_types.ProgrammableTransactionBuilder {
 	return txb.builder
 }
@@ -66,23 +86,59 @@ func (txb *Transaction) SplitCoins(ctx context.Context, coin interface{}, amount
 		return nil, nil
 	}
 
-	unresolvedParameter, err := txb.resolveSplitCoinsCoin(coin)
+	coinArg, err := txb.resolveSplitCoinsCoin(coin)
 	if err != nil {
 		return nil, fmt.Errorf("can not resolve coin in command %d: %v", len(txb.builder.Commands), err)
 	}
 
-	amountArguments, err := txb.resolveSplitCoinsAmounts(amounts)
+	amountArgs, err := txb.resolveSplitCoinsAmounts(amounts)
 	if err != nil {
 		return nil, fmt.Errorf("can not resolve amounts in command %d: %v", len(txb.builder.Commands), err)
 	}
 
-	unresolvedParameter.merge(amountArguments)
-	arguments, err := unresolvedParameter.resolveAndParseToArguments(ctx, txb.client, txb)
+	if _, err := txb.AddSplitCoins(coinArg, amountArgs); err != nil {
+		return nil, err
+	}
+
+	return txb.createTransactionResult(len(amounts)), nil
+}
+
+// AddSplitCoins encodes a split coins command with typed arguments, each split coin is a nested value of the returned Result.
+func (txb *Transaction) AddSplitCoins(coin Arg, amounts []Arg) (result Result, err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	if len(amounts) == 0 {
+		return Result{}, fmt.Errorf("missing amounts in command %d", len(txb.builder.Commands))
+	}
+
+	unresolvedParameter := NewUnresolvedParameter(1 + len(amounts))
+	if isPure(coin) {
+		return Result{}, fmt.Errorf("coin must not be a pure value in command %d", len(txb.builder.Commands))
+	}
+	if err := unresolvedParameter.setArg(txb, 0, coin, true); err != nil {
+		return Result{}, fmt.Errorf("can not resolve coin in command %d: %v", len(txb.builder.Commands), err)
+	}
+
+	for idx, amount := range amounts {
+		if isObject(amount) {
+			return Result{}, fmt.Errorf("amount at index %d must not be an object in command %d", idx, len(txb.builder.Commands))
+		}
+		if err := unresolvedParameter.setArg(txb, idx+1, amount, false); err != nil {
+			return Result{}, fmt.Errorf("can not resolve amount at index %d in command %d: %v", idx, len(txb.builder.Commands), err)
+		}
+	}
+
+	arguments, err := unresolvedParameter.toArguments(txb)
 	if err != nil {
-		return nil, fmt.Errorf("can not resolve and parse to arguments in command %d, err: %v", len(txb.builder.Commands), err)
+		return Result{}, fmt.Errorf("can not parse to arguments in command %d, err: %v", len(txb.builder.Commands), err)
 	}
 
-	txb.builder.Command(
+	return txb.command(
 		sui_types.Command{
 			SplitCoins: &struct {
 				Argument  sui_types.Argument
@@ -92,9 +148,7 @@ func (txb *Transaction) SplitCoins(ctx context.Context, coin interface{}, amount
 				Arguments: arguments[1:],
 			},
 		},
-	)
-
-	return txb.createTransactionResult(len(amounts)), nil
+	), nil
 }
 
 // TransferObjects encodes a transfer objects command in the transaction.
@@ -103,23 +157,55 @@ func (txb *Transaction) TransferObjects(ctx context.Context, objects []interface
 		return nil
 	}
 
-	unresolvedParameter, err := txb.resolveTransferObjectsObjects(objects)
+	objectArgs, err := txb.resolveTransferObjectsObjects(objects)
 	if err != nil {
 		return fmt.Errorf("can not resolve objects in command %d: %v", len(txb.builder.Commands), err)
 	}
 
-	unresolvedAddressArgument, err := txb.resolveTransferObjectsAddress(address)
+	addressArg, err := txb.resolveTransferObjectsAddress(address)
 	if err != nil {
 		return fmt.Errorf("can not resolve address in command %d: %v", len(txb.builder.Commands), err)
 	}
 
-	unresolvedParameter.merge(unresolvedAddressArgument)
-	arguments, err := unresolvedParameter.resolveAndParseToArguments(ctx, txb.client, txb)
+	return txb.AddTransferObjects(objectArgs, addressArg)
+}
+
+// AddTransferObjects encodes a transfer objects command with typed arguments.
+func (txb *Transaction) AddTransferObjects(objects []Arg, address Arg) (err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	if len(objects) == 0 {
+		return fmt.Errorf("missing objects in command %d", len(txb.builder.Commands))
+	}
+
+	unresolvedParameter := NewUnresolvedParameter(len(objects) + 1)
+	for idx, object := range objects {
+		if isPure(object) {
+			return fmt.Errorf("object at index %d must not be a pure value in command %d", idx, len(txb.builder.Commands))
+		}
+		if err := unresolvedParameter.setArg(txb, idx, object, true); err != nil {
+			return fmt.Errorf("can not resolve object at index %d in command %d: %v", idx, len(txb.builder.Commands), err)
+		}
+	}
+
+	if isObject(address) {
+		return fmt.Errorf("address must not be an object in command %d", len(txb.builder.Commands))
+	}
+	if err := unresolvedParameter.setArg(txb, len(objects), address, false); err != nil {
+		return fmt.Errorf("can not resolve address in command %d: %v", len(txb.builder.Commands), err)
+	}
+
+	arguments, err := unresolvedParameter.toArguments(txb)
 	if err != nil {
-		return fmt.Errorf("can not resolve and parse to arguments in command %d, err: %v", len(txb.builder.Commands), err)
+		return fmt.Errorf("can not parse to arguments in command %d, err: %v", len(txb.builder.Commands), err)
 	}
 
-	txb.builder.Command(
+	txb.command(
 		sui_types.Command{
 			TransferObjects: &struct {
 				Arguments []sui_types.Argument
@@ -140,23 +226,55 @@ func (txb *Transaction) MergeCoins(ctx context.Context, destination interface{},
 		return nil
 	}
 
-	unresolvedParameter, err := txb.resolveMergeCoinsDestination(destination)
+	destinationArg, err := txb.resolveMergeCoinsDestination(destination)
 	if err != nil {
 		return fmt.Errorf("failed to resolve destination in command %d, err: %v", len(txb.builder.Commands), err)
 	}
 
-	unresolvedSourceParameter, err := txb.resolveMergeCoinsSources(sources)
+	sourceArgs, err := txb.resolveMergeCoinsSources(sources)
 	if err != nil {
 		return fmt.Errorf("failed to resolve sources in command %d, err: %v", len(txb.builder.Commands), err)
 	}
 
-	unresolvedParameter.merge(unresolvedSourceParameter)
-	arguments, err := unresolvedParameter.resolveAndParseToArguments(ctx, txb.client, txb)
+	return txb.AddMergeCoins(destinationArg, sourceArgs)
+}
+
+// AddMergeCoins encodes a merge coins command with typed arguments.
+func (txb *Transaction) AddMergeCoins(destination Arg, sources []Arg) (err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	if len(sources) == 0 {
+		return fmt.Errorf("missing sources in command %d", len(txb.builder.Commands))
+	}
+
+	unresolvedParameter := NewUnresolvedParameter(1 + len(sources))
+	if isPure(destination) {
+		return fmt.Errorf("destination must not be a pure value in command %d", len(txb.builder.Commands))
+	}
+	if err := unresolvedParameter.setArg(txb, 0, destination, true); err != nil {
+		return fmt.Errorf("failed to resolve destination in command %d, err: %v", len(txb.builder.Commands), err)
+	}
+
+	for idx, source := range sources {
+		if isPure(source) {
+			return fmt.Errorf("source at index %d must not be a pure value in command %d", idx, len(txb.builder.Commands))
+		}
+		if err := unresolvedParameter.setArg(txb, idx+1, source, true); err != nil {
+			return fmt.Errorf("failed to resolve source at index %d in command %d, err: %v", idx, len(txb.builder.Commands), err)
+		}
+	}
+
+	arguments, err := unresolvedParameter.toArguments(txb)
 	if err != nil {
-		return fmt.Errorf("can not resolve and parse to arguments in command %d, err: %v", len(txb.builder.Commands), err)
+		return fmt.Errorf("can not parse to arguments in command %d, err: %v", len(txb.builder.Commands), err)
 	}
 
-	txb.builder.Command(
+	txb.command(
 		sui_types.Command{
 			MergeCoins: &struct {
 				Argument  sui_types.Argument
@@ -173,18 +291,42 @@ func (txb *Transaction) MergeCoins(ctx context.Context, destination interface{},
 
 // MakeMoveVec encodes a make move vector command in the transaction.
 func (txb *Transaction) MakeMoveVec(ctx context.Context, vecType string, arguments []interface{}) ([]*sui_types.Argument, error) {
-	typeTag := txb.resolveMakeMoveVecType(vecType)
-	unresolvedParameter, err := txb.resolveMakeMoveElement(arguments)
+	elements, err := txb.resolveMakeMoveElement(arguments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve make move vec element in command %d, err: %v", len(txb.builder.Commands), err)
 	}
 
-	inputArguments, err := unresolvedParameter.resolveAndParseToArguments(ctx, txb.client, txb)
+	if _, err := txb.AddMakeMoveVec(vecType, elements); err != nil {
+		return nil, err
+	}
+
+	return txb.createTransactionResult(1), nil
+}
+
+// AddMakeMoveVec encodes a make move vector command with typed elements, an empty vecType lets the element type be inferred.
+func (txb *Transaction) AddMakeMoveVec(vecType string, elements []Arg) (result Result, err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	typeTag := txb.resolveMakeMoveVecType(vecType)
+
+	unresolvedParameter := NewUnresolvedParameter(len(elements))
+	for idx, element := range elements {
+		if err := unresolvedParameter.setArg(txb, idx, element, true); err != nil {
+			return Result{}, fmt.Errorf("failed to resolve make move vec element at index %d in command %d, err: %v", idx, len(txb.builder.Commands), err)
+		}
+	}
+
+	inputArguments, err := unresolvedParameter.toArguments(txb)
 	if err != nil {
-		return nil, fmt.Errorf("can not resolve and parse to arguments in command %d, err: %v", len(txb.builder.Commands), err)
+		return Result{}, fmt.Errorf("can not parse to arguments in command %d, err: %v", len(txb.builder.Commands), err)
 	}
 
-	txb.builder.Command(
+	return txb.command(
 		sui_types.Command{
 			MakeMoveVec: &struct {
 				TypeTag   *move_types.TypeTag `bcs:"optional"`
@@ -194,30 +336,71 @@ func (txb *Transaction) MakeMoveVec(ctx context.Context, vecType string, argumen
 				Arguments: inputArguments,
 			},
 		},
-	)
-
-	return txb.createTransactionResult(1), nil
+	), nil
 }
 
-// MoveCall encodes a programmable Move call transaction.
+// MoveCall encodes a programmable Move call transaction. The function signature is fetched when the call is added, without a
+// SuiClient it must be supplied with SupplyMoveFunction or a *MissingDataError is returned.
 func (txb *Transaction) MoveCall(ctx context.Context, target string, arguments []interface{}, typeArguments []string) (returnArguments []*sui_types.Argument, err error) {
-	entry := strings.Split(target, "::")
-	if len(entry) != 3 {
-		return nil, fmt.Errorf("invalid target [%s]", target)
+	pkg, mod, fn, err := parseMoveCallTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := getNormalizedMoveFunctionFromCache(ctx, txb.client, txb.Cache(), pkg, mod, fn)
+	if err != nil {
+		return nil, fmt.Errorf("can not get normalized move function in command %d: %w", len(txb.builder.Commands), err)
+	}
+
+	parameters := moveFunctionParameters(normalized)
+	arguments = withSystemObjects(txb, parameters, arguments, func(arg Arg) interface{} { return arg })
+	if len(arguments) != len(parameters) || len(typeArguments) != len(normalized.TypeParameters) {
+		return nil, fmt.Errorf("incorrect number of arguments or type arguments in command %d, required arguments: %d, type arguments: %d", len(txb.builder.Commands), len(parameters), len(normalized.TypeParameters))
+	}
+
+	args, err := txb.resolveFunctionArguments(arguments, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("can not resolve function arguments in command %d: %v", len(txb.builder.Commands), err)
 	}
-	var pkg, mod, fn = utils.NormalizeSuiObjectID(entry[0]), entry[1], entry[2]
 
-	inputArguments, inputTypeArguments, returnsCount, err := txb.resolveMoveFunction(ctx, pkg, mod, fn, arguments, typeArguments)
+	if _, err := txb.AddMoveCall(target, args, typeArguments); err != nil {
+		return nil, err
+	}
+
+	return txb.createTransactionResult(len(normalized.Return)), nil
+}
+
+// AddMoveCall encodes a programmable Move call with typed arguments, the TxContext parameter is added automatically.
+// Functions with multiple return values are referenced with Result.Nested. No request is made, the function signature
+// is checked when the transaction is built. The clock, randomness, deny list and system state objects can be omitted,
+// they are injected when the signature is cached or supplied, or when it is fetched at build time.
+func (txb *Transaction) AddMoveCall(target string, arguments []Arg, typeArguments []string) (result Result, err error) {
+	state := txb.snapshot()
+	defer func() {
+		if err != nil {
+			txb.restore(state)
+		}
+	}()
+
+	pkg, mod, fn, err := parseMoveCallTarget(target)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse function arguments, err: %v", err)
+		return Result{}, err
 	}
 
 	packageID, err := sui_types.NewAddressFromHex(pkg)
 	if err != nil {
-		return nil, fmt.Errorf("invalid package address [%v]", err)
+		return Result{}, fmt.Errorf("invalid package address [%v]", err)
 	}
 
-	txb.builder.Command(
+	if entry := txb.Cache().GetMoveFunctionDefinition(pkg, mod, fn); entry != nil {
+		arguments = withSystemObjects(txb, moveFunctionParameters(entry.Normalized), arguments, func(arg Arg) Arg { return arg })
+	}
+	inputArguments, inputTypeArguments, err := txb.resolveMoveFunction(arguments, typeArguments)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to parse function arguments, err: %v", err)
+	}
+
+	return txb.command(
 		sui_types.Command{
 			MoveCall: &sui_types.ProgrammableMoveCall{
 				Package:       *packageID,
@@ -227,77 +410,130 @@ func (txb *Transaction) MoveCall(ctx context.Context, target string, arguments [
 				TypeArguments: inputTypeArguments,
 			},
 		},
-	)
-
-	return txb.createTransactionResult(returnsCount), nil
+	), nil
 }
 
 // Build encodes and builds the transaction, returning the transaction data and its BCS-encoded bytes.
+// Without a SuiClient nothing is fetched, and a *MissingDataError lists the data that must be supplied or set.
+// Receiving objects are immutable or owned objects in the transaction data, only the bytes encode them as receiving objects.
 func (txb *Transaction) Build(ctx context.Context, sender string) (*sui_types.TransactionData, []byte, error) {
 	txb.SetSenderIfNotSet(sender)
 
+	if txb.client == nil {
+		if missing := append(txb.missingInputData(), txb.missingGasData()...); len(missing) > 0 {
+			return nil, nil, &MissingDataError{Missing: missing}
+		}
+	}
+	if err := txb.prepare(ctx); err != nil {
+		return nil, nil, fmt.Errorf("can not resolve inputs when building transaction: %w", err)
+	}
+	if err := checkExpiration(ctx, txb); err != nil {
+		return nil, nil, fmt.Errorf("invalid expiration when building transaction: %v", err)
+	}
+	limits := txb.getProtocolLimits(ctx)
+	if limits != nil {
+		if err := limits.CheckProgrammableTransaction(txb.builder.Finish()); err != nil {
+			return nil, nil, fmt.Errorf("invalid transaction: %w", err)
+		}
+	}
 	if err := setGasPrice(ctx, txb); err != nil {
 		return nil, nil, fmt.Errorf("can not set gas price when building transaction: %v", err)
 	}
 	if err := setGasBudget(ctx, txb); err != nil {
-		return nil, nil, fmt.Errorf("can not set gas budget when building transaction: %v", err)
+		return nil, nil, fmt.Errorf("can not set gas budget when building transaction: %w", err)
 	}
 	if err := setGasPayment(ctx, txb); err != nil {
-		return nil, nil, fmt.Errorf("can not set gas payment when building transaction: %v", err)
+		return nil, nil, fmt.Errorf("can not set gas payment when building transaction: %w", err)
 	}
 
-	tx := sui_types.NewProgrammable(
-		*txb.Sender,
-		txb.GasConfig.Payment,
-		txb.builder.Finish(),
-		txb.GasConfig.Budget,
-		txb.GasConfig.Price,
-	)
-	bs, err := bcs.Marshal(tx)
+	tx, err := txb.transactionData(txb.GasConfig.Payment, txb.GasConfig.Budget, txb.GasConfig.Price)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can not create transaction data: %v", err)
+	}
+	bs, err := txb.marshalTransactionData(tx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("can not marshal transaction: %v", err)
 	}
+	if limits != nil {
+		if err := limits.CheckTransactionData(&tx, bs); err != nil {
+			return nil, nil, fmt.Errorf("invalid transaction: %w", err)
+		}
+	}
 	return &tx, bs, err
 }
 
-// DryRunTransactionBlock encodes and simulates the transaction block without executing it on-chain.
+// DryRunTransactionBlock encodes and simulates the transaction block without executing it on-chain. When the simulated
+// transaction fails, the response is returned with the error of DryRunError.
 func (txb *Transaction) DryRunTransactionBlock(ctx context.Context) (*types.DryRunTransactionBlockResponse, error) {
 	if txb.Sender == nil {
 		return nil, fmt.Errorf("missing transaction sender")
 	}
+	if txb.client == nil {
+		return nil, fmt.Errorf("missing sui client to dry run transaction")
+	}
 
+	if err := txb.prepare(ctx); err != nil {
+		return nil, fmt.Errorf("failed to resolve inputs, err: %w", err)
+	}
 	if err := setGasPrice(ctx, txb); err != nil {
 		return nil, fmt.Errorf("failed to set gas price, err: %v", err)
 	}
 
-	tx := sui_types.NewProgrammable(
-		*txb.Sender,
-		nil,
-		txb.builder.Finish(),
-		utils.MaxGas,
-		txb.GasConfig.Price,
-	)
-	bs, err := bcs.Marshal(tx)
+	tx, err := txb.transactionData(nil, utils.MaxGas, txb.GasConfig.Price)
+	if err != nil {
+		return nil, fmt.Errorf("can not create transaction data, err: %v", err)
+	}
+	bs, err := txb.marshalTransactionData(tx)
 	if err != nil {
 		return nil, fmt.Errorf("can not marshal transaction, err: %v", err)
 	}
 
-	return txb.client.DryRunTransactionBlock(ctx, types.DryRunTransactionBlockParams{TransactionBlock: bs})
+	response, err := txb.client.DryRunTransactionBlock(ctx, types.DryRunTransactionBlockParams{TransactionBlock: bs})
+	if err != nil {
+		return nil, err
+	}
+	return response, DryRunError(response)
 }
 
-// DevInspectTransactionBlock encodes and simulates the transaction block for developer inspection.
+// DevInspectTransactionBlock encodes and simulates the transaction block for developer inspection. When the inspected
+// transaction fails, the results are returned with the error of DevInspectError.
 func (txb *Transaction) DevInspectTransactionBlock(ctx context.Context) (*types.DevInspectResults, error) {
 	if txb.Sender == nil {
 		return nil, fmt.Errorf("missing transaction sender")
 	}
+	if txb.client == nil {
+		return nil, fmt.Errorf("missing sui client to dev inspect transaction")
+	}
 
-	bs, err := bcs.Marshal(txb.builder.Finish())
+	txBytes, err := txb.BuildKind(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("can not marshal transaction: %v", err)
+		return nil, err
 	}
 
-	txBytes := append([]byte{0}, bs...)
-	return txb.client.DevInspectTransactionBlock(ctx, types.DevInspectTransactionBlockParams{Sender: txb.Sender.String(), TransactionBlock: txBytes})
+	results, err := txb.client.DevInspectTransactionBlock(ctx, types.DevInspectTransactionBlockParams{Sender: txb.Sender.String(), TransactionBlock: txBytes})
+	if err != nil {
+		return nil, err
+	}
+	return results, DevInspectError(results)
+}
+
+// SignAndExecuteTransactionBlock builds the transaction with the signer as sender, then signs and executes it. The effects and
+// object changes are always requested. When the executed transaction fails, the response is returned with the error of
+// ExecutionResponseError.
+func (txb *Transaction) SignAndExecuteTransactionBlock(ctx context.Context, signer cryptography.Signer, options *types.SuiTransactionBlockResponseOptions) (*types.SuiTransactionBlockResponse, error) {
+	if txb.client == nil {
+		return nil, fmt.Errorf("missing sui client to execute transaction")
+	}
+
+	_, bs, err := txb.Build(ctx, signer.ToSuiAddress())
+	if err != nil {
+		return nil, err
+	}
+	response, err := signAndExecute(ctx, txb.client, signer, bs, options)
+	if err != nil {
+		return nil, err
+	}
+	return response, executionError(response)
 }
 
 // SetSender sets the sender address for the transaction.
@@ -334,11 +570,31 @@ func (txb *Transaction) SetGasBudgetIfNotSet(budget uint64) {
 	}
 }
 
-// SetGasOwner sets the gas owner for the transaction.
+// SetGasOwner sets the gas owner for the transaction, a gas owner other than the sender sponsors the transaction.
 func (txb *Transaction) SetGasOwner(owner string) {
 	txb.GasConfig.Owner = owner
 }
 
+// SetCoinSelectionStrategy sets the strategy used to select gas coins when the gas payment is not set.
+func (txb *Transaction) SetCoinSelectionStrategy(strategy CoinSelectionStrategy) {
+	txb.coinSelectionStrategy = strategy
+}
+
+// SetExpiration sets the last epoch in which the transaction can be executed.
+func (txb *Transaction) SetExpiration(epoch uint64) {
+	txb.expiration = &epoch
+}
+
+// ClearExpiration removes the expiration, the transaction can be executed in any epoch.
+func (txb *Transaction) ClearExpiration() {
+	txb.expiration = nil
+}
+
+// Expiration returns the expiration epoch of the transaction, or nil when it does not expire.
+func (txb *Transaction) Expiration() *uint64 {
+	return txb.expiration
+}
+
 // SetGasPayment sets the gas payment objects for the transaction.
 func (txb *Transaction) SetGasPayment(payments []*sui_types.ObjectRef) {
 	txb.GasConfig.Payment = payments
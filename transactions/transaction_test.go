@@ -0,0 +1,95 @@
+package transactions_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+)
+
+func TestTransactionExpiration(t *testing.T) {
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"suix_getLatestSuiSystemState": func([]json.RawMessage) (any, error) {
+			state := mockSystemState("1")
+			state["epoch"] = "21"
+			return state, nil
+		},
+	})
+
+	newTransaction := func(client bool) *transactions.Transaction {
+		tx := transactions.NewTransaction(nil)
+		if client {
+			tx = transactions.NewTransaction(suiClient)
+		}
+		if err := tx.AddTransferObjects([]transactions.Arg{tx.ObjectRef(objectRef(t, objectID(0x39001), 1))}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+			t.Fatalf("failed to add transfer objects: %v", err)
+		}
+		tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x39002), 1)})
+		tx.SetGasBudget(1000000)
+		tx.SetGasPrice(1000)
+		return tx
+	}
+
+	tests := []struct {
+		name       string
+		client     bool
+		expiration uint64 // zero for no expiration
+		err        string
+	}{
+		{name: "no expiration", client: true},
+		{name: "current epoch", client: true, expiration: 21},
+		{name: "later epoch", client: true, expiration: 30},
+		{name: "expired", client: true, expiration: 20, err: "expired in epoch 20"},
+		{name: "offline", expiration: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newTransaction(tt.client)
+			if tt.expiration != 0 {
+				tx.SetExpiration(tt.expiration)
+			}
+
+			_, bs, err := tx.Build(context.Background(), recipient)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, but got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to build transaction: %v", err)
+			}
+
+			// the transaction data ends with TransactionExpiration::None or TransactionExpiration::Epoch
+			expected := []byte{0}
+			if tt.expiration != 0 {
+				expected = binary.LittleEndian.AppendUint64([]byte{1}, tt.expiration)
+			}
+			if !bytes.HasSuffix(bs, expected) {
+				t.Errorf("expected transaction bytes to end with %v, but got %v", expected, bs[len(bs)-len(expected):])
+			}
+
+			data, err := transactions.DecodeTransactionData(bs)
+			if err != nil {
+				t.Fatalf("failed to decode transaction data: %v", err)
+			}
+			var epoch uint64
+			if data.V1.Expiration.Epoch != nil {
+				epoch = *data.V1.Expiration.Epoch
+			}
+			if epoch != tt.expiration {
+				t.Errorf("expected decoded expiration epoch %d, but got %d", tt.expiration, epoch)
+			}
+		})
+	}
+
+	// the protocol version is fetched once in the epoch
+	if got := node.count("suix_getLatestSuiSystemState"); got != 4 {
+		t.Errorf("expected the epoch to be fetched for each transaction with an expiration and a client, but got %d requests", got)
+	}
+}
@@ -0,0 +1,636 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+var (
+	receivingStruct    = utils.SuiFrameworkAddress + "::transfer::Receiving"
+	upgradeCapType     = utils.SuiFrameworkAddress + "::package::UpgradeCap"
+	upgradeReceiptType = utils.SuiFrameworkAddress + "::package::UpgradeReceipt"
+)
+
+// TypeError reports a command whose arguments or type arguments do not match the Move function signature.
+type TypeError struct {
+	Command  int // index of the command
+	Argument int // index of the argument, -1 when the error is not about one argument
+	Message  string
+}
+
+// Error implements the error interface.
+func (e *TypeError) Error() string {
+	if e.Argument < 0 {
+		return fmt.Sprintf("type error in command %d: %s", e.Command, e.Message)
+	}
+	return fmt.Sprintf("type error in argument %d of command %d: %s", e.Argument, e.Command, e.Message)
+}
+
+// CheckTypes checks the commands against the Move function signatures without executing the transaction. The type of
+// each command result is tracked through the type parameters of the functions, type arguments must have the abilities
+// required by the functions, values without copy must not be used after they are moved and mutably borrowed values must
+// not be used twice by a command. Missing signatures and struct definitions are fetched with the SuiClient, commands
+// calling a function with an unknown signature are not checked. Build and DryRunTransactionBlock check cached signatures.
+func (txb *Transaction) CheckTypes(ctx context.Context) error {
+	if txb.client != nil {
+		if err := txb.loadTypeDefinitions(ctx, txb.client, DefaultTypeRegistry()); err != nil {
+			return err
+		}
+	}
+	return txb.checkTypes(DefaultTypeRegistry())
+}
+
+// loadTypeDefinitions fetches the signatures of the called functions, one request per package, and the definitions of
+// the structs used as type arguments.
+func (txb *Transaction) loadTypeDefinitions(ctx context.Context, suiClient *client.SuiClient, registry *TypeRegistry) error {
+	fetched := make(map[string]bool)
+	for _, command := range txb.builder.Commands {
+		moveCall := command.MoveCall
+		if moveCall == nil {
+			continue
+		}
+		pkg := moveCall.Package.String()
+		if !fetched[pkg] && txb.Cache().GetMoveFunctionDefinition(pkg, string(moveCall.Module), string(moveCall.Function)) == nil {
+			if err := cacheNormalizedMoveModules(ctx, suiClient, txb.Cache(), pkg); err != nil {
+				return fmt.Errorf("can not get normalized move modules of package [%s]: %v", pkg, err)
+			}
+			fetched[pkg] = true
+		}
+		for _, typeArgument := range moveCall.TypeArguments {
+			if err := registry.loadDefinitions(ctx, suiClient, &typeArgument); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadDefinitions fetches the definitions of the structs in a Move type which are not registered, the types of their
+// fields are not loaded.
+func (r *TypeRegistry) loadDefinitions(ctx context.Context, suiClient *client.SuiClient, typeTag *move_types.TypeTag) error {
+	if typeTag.Vector != nil {
+		return r.loadDefinitions(ctx, suiClient, typeTag.Vector)
+	}
+	if typeTag.Struct == nil {
+		return nil
+	}
+	for _, param := range typeTag.Struct.TypeParams {
+		if err := r.loadDefinitions(ctx, suiClient, &param); err != nil {
+			return err
+		}
+	}
+
+	name := structName(typeTag.Struct)
+	if _, ok := builtinAbilities(typeTag.Struct); ok || r.lookup(typeTag.Struct) != nil {
+		return nil
+	}
+	definition, err := suiClient.GetNormalizedMoveStruct(ctx, types.GetNormalizedMoveStructParams{Package: typeTag.Struct.Address.String(), Module: string(typeTag.Struct.Module), Struct: string(typeTag.Struct.Name)})
+	if err != nil {
+		return fmt.Errorf("failed to get struct [%s], err: %v", name, err)
+	}
+	return r.RegisterStruct(name, definition)
+}
+
+// checkTypes checks the commands with the cached function signatures and the registered struct definitions.
+func (txb *Transaction) checkTypes(registry *TypeRegistry) error {
+	c := &typeChecker{txb: txb, registry: registry, results: make([]commandResults, len(txb.builder.Commands)), moved: make(map[string]int)}
+	for idx, command := range txb.builder.Commands {
+		if err := c.command(idx, command); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reference defines how a value is passed to a Move function.
+type reference int
+
+const (
+	byValue reference = iota
+	byReference
+	byMutableReference
+)
+
+// abilitySet defines the abilities of a Move type as a bit set.
+type abilitySet uint8
+
+const (
+	abilityCopy abilitySet = 1 << iota
+	abilityDrop
+	abilityStore
+	abilityKey
+
+	primitiveAbilities = abilityCopy | abilityDrop | abilityStore
+)
+
+func newAbilitySet(abilities types.SuiMoveAbilitySet) abilitySet {
+	var set abilitySet
+	for _, ability := range abilities.Abilities {
+		switch ability {
+		case types.Copy:
+			set |= abilityCopy
+		case types.Drop:
+			set |= abilityDrop
+		case types.Store:
+			set |= abilityStore
+		case types.Key:
+			set |= abilityKey
+		}
+	}
+	return set
+}
+
+func (s abilitySet) String() string {
+	var names []string
+	for _, ability := range []struct {
+		ability abilitySet
+		name    string
+	}{{abilityCopy, "copy"}, {abilityDrop, "drop"}, {abilityStore, "store"}, {abilityKey, "key"}} {
+		if s&ability.ability != 0 {
+			names = append(names, ability.name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// commandResults defines the types of the values returned by a command, types are nil when they are unknown.
+type commandResults struct {
+	known bool // the number of results is known
+	types []*move_types.TypeTag
+}
+
+// argumentValue defines a value used as an argument.
+type argumentValue struct {
+	key             string              // Input(n), Result(n.m) or GasCoin
+	typeTag         *move_types.TypeTag // nil when unknown, the types of inputs are not fetched
+	pure            bool
+	object          bool
+	gasCoin         bool
+	receiving       bool
+	immutableShared bool
+}
+
+// typeChecker tracks the results of the commands and the values moved by them.
+type typeChecker struct {
+	txb      *Transaction
+	registry *TypeRegistry
+	results  []commandResults
+	moved    map[string]int // the index of the command that moved a value by its key
+}
+
+func (c *typeChecker) command(idx int, command sui_types.Command) error {
+	if intent, ok := c.txb.intents[uint16(idx)]; ok {
+		coinType, err := ParseTypeTag(fmt.Sprintf("%s::coin::Coin<%s>", utils.SuiFrameworkAddress, intent.CoinType))
+		if err != nil {
+			return &TypeError{Command: idx, Argument: -1, Message: err.Error()}
+		}
+		c.results[idx] = commandResults{known: true, types: []*move_types.TypeTag{coinType}}
+		return nil
+	}
+
+	switch {
+	case command.MoveCall != nil:
+		return c.moveCall(idx, command.MoveCall)
+	case command.TransferObjects != nil:
+		return c.transferObjects(idx, command.TransferObjects.Arguments, command.TransferObjects.Argument)
+	case command.SplitCoins != nil:
+		return c.splitCoins(idx, command.SplitCoins.Argument, command.SplitCoins.Arguments)
+	case command.MergeCoins != nil:
+		return c.mergeCoins(idx, command.MergeCoins.Argument, command.MergeCoins.Arguments)
+	case command.MakeMoveVec != nil:
+		return c.makeMoveVec(idx, command.MakeMoveVec.TypeTag, command.MakeMoveVec.Arguments)
+	case command.Publish != nil:
+		upgradeCap, _ := ParseTypeTag(upgradeCapType)
+		c.results[idx] = commandResults{known: true, types: []*move_types.TypeTag{upgradeCap}}
+	case command.Upgrade != nil:
+		ticket, err := c.value(idx, 0, command.Upgrade.Argument)
+		if err != nil {
+			return err
+		}
+		c.move(idx, ticket)
+		receipt, _ := ParseTypeTag(upgradeReceiptType)
+		c.results[idx] = commandResults{known: true, types: []*move_types.TypeTag{receipt}}
+	}
+	return nil
+}
+
+func (c *typeChecker) moveCall(idx int, moveCall *sui_types.ProgrammableMoveCall) error {
+	target := fmt.Sprintf("%s::%s::%s", moveCall.Package.String(), moveCall.Module, moveCall.Function)
+	entry := c.txb.Cache().GetMoveFunctionDefinition(moveCall.Package.String(), string(moveCall.Module), string(moveCall.Function))
+	if entry == nil {
+		for i, argument := range moveCall.Arguments {
+			if _, err := c.value(idx, i, argument); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	function := entry.Normalized
+	if function.Visibility != types.Public && !function.IsEntry {
+		return &TypeError{Command: idx, Argument: -1, Message: fmt.Sprintf("function [%s] is neither public nor entry", target)}
+	}
+	parameters := moveFunctionParameters(function)
+	if len(moveCall.TypeArguments) != len(function.TypeParameters) {
+		return &TypeError{Command: idx, Argument: -1, Message: fmt.Sprintf("function [%s] has %d type parameters, got %d type arguments", target, len(function.TypeParameters), len(moveCall.TypeArguments))}
+	}
+	if len(moveCall.Arguments) != len(parameters) {
+		return &TypeError{Command: idx, Argument: -1, Message: fmt.Sprintf("function [%s] has %d parameters, got %d arguments", target, len(parameters), len(moveCall.Arguments))}
+	}
+
+	for i, constraints := range function.TypeParameters {
+		required := newAbilitySet(constraints)
+		abilities, ok := c.abilities(&moveCall.TypeArguments[i])
+		if missing := required &^ abilities; ok && missing != 0 {
+			return &TypeError{Command: idx, Argument: -1, Message: fmt.Sprintf("type argument %d [%s] of function [%s] does not have the abilities: %v", i, FormatTypeTag(moveCall.TypeArguments[i]), target, missing)}
+		}
+	}
+
+	used := make(map[string]reference)
+	values := make([]*argumentValue, len(parameters))
+	for i, argument := range moveCall.Arguments {
+		parameterType, ref, err := parameterType(parameters[i].SuiMoveNormalizedType, moveCall.TypeArguments)
+		if err != nil {
+			return &TypeError{Command: idx, Argument: i, Message: err.Error()}
+		}
+		value, err := c.value(idx, i, argument)
+		if err != nil {
+			return err
+		}
+		if message := c.checkArgument(value, parameterType, ref); message != "" {
+			return &TypeError{Command: idx, Argument: i, Message: message}
+		}
+
+		if previous, ok := used[value.key]; ok && (previous == byMutableReference || ref == byMutableReference || (previous == byValue || ref == byValue) && !c.copyable(value)) {
+			return &TypeError{Command: idx, Argument: i, Message: fmt.Sprintf("%s is used more than once while it is borrowed mutably or moved", value.key)}
+		}
+		if previous, ok := used[value.key]; !ok || previous == byReference {
+			used[value.key] = ref
+		}
+		if ref == byValue {
+			values[i] = value
+		}
+	}
+	for _, value := range values {
+		if value != nil {
+			c.move(idx, value)
+		}
+	}
+
+	results := commandResults{known: true, types: make([]*move_types.TypeTag, len(function.Return))}
+	for i, ret := range function.Return {
+		returnType, ref, err := parameterType(ret.SuiMoveNormalizedType, moveCall.TypeArguments)
+		if err != nil {
+			return &TypeError{Command: idx, Argument: -1, Message: fmt.Sprintf("invalid return type %d: %v", i, err)}
+		}
+		if ref != byValue {
+			return &TypeError{Command: idx, Argument: -1, Message: fmt.Sprintf("function [%s] returns a reference, which can not be used in a transaction", target)}
+		}
+		results.types[i] = returnType
+	}
+	c.results[idx] = results
+	return nil
+}
+
+// checkArgument checks a value passed to a parameter, it returns the reason when the value does not match.
+func (c *typeChecker) checkArgument(value *argumentValue, parameterType *move_types.TypeTag, ref reference) string {
+	receiving := parameterType.Struct != nil && structName(parameterType.Struct) == receivingStruct
+	switch {
+	case value.gasCoin && ref == byValue:
+		return "the gas coin can only be passed by value to TransferObjects"
+	case value.pure && !isPureType(parameterType):
+		return fmt.Sprintf("a pure value can not be passed to a parameter of type [%s]", FormatTypeTag(*parameterType))
+	case value.object && isPureType(parameterType):
+		return fmt.Sprintf("an object can not be passed to a parameter of type [%s]", FormatTypeTag(*parameterType))
+	case receiving && !value.receiving:
+		return fmt.Sprintf("a receiving object is required by the parameter of type [%s]", FormatTypeTag(*parameterType))
+	case value.receiving && !receiving:
+		return fmt.Sprintf("a receiving object can not be passed to a parameter of type [%s]", FormatTypeTag(*parameterType))
+	case value.immutableShared && ref != byReference:
+		return "an immutable shared object can only be passed by immutable reference"
+	case value.typeTag != nil && FormatTypeTag(*value.typeTag) != FormatTypeTag(*parameterType):
+		return fmt.Sprintf("expected a value of type [%s], got [%s]", FormatTypeTag(*parameterType), FormatTypeTag(*value.typeTag))
+	}
+	return ""
+}
+
+func (c *typeChecker) transferObjects(idx int, objects []sui_types.Argument, address sui_types.Argument) error {
+	for i, argument := range objects {
+		value, err := c.value(idx, i, argument)
+		if err != nil {
+			return err
+		}
+		if value.pure {
+			return &TypeError{Command: idx, Argument: i, Message: "a pure value can not be transferred"}
+		}
+		if abilities, ok := c.typeAbilities(value.typeTag); ok && abilities&(abilityKey|abilityStore) != abilityKey|abilityStore {
+			return &TypeError{Command: idx, Argument: i, Message: fmt.Sprintf("a value of type [%s] can not be transferred without the key and store abilities", FormatTypeTag(*value.typeTag))}
+		}
+		c.move(idx, value)
+	}
+
+	recipient, err := c.value(idx, len(objects), address)
+	if err != nil {
+		return err
+	}
+	if recipient.object || recipient.gasCoin || recipient.typeTag != nil && recipient.typeTag.Address == nil {
+		return &TypeError{Command: idx, Argument: len(objects), Message: "the recipient must be an address"}
+	}
+	c.results[idx] = commandResults{known: true}
+	return nil
+}
+
+func (c *typeChecker) splitCoins(idx int, coin sui_types.Argument, amounts []sui_types.Argument) error {
+	value, err := c.value(idx, 0, coin)
+	if err != nil {
+		return err
+	}
+	if message := c.checkCoin(value); message != "" {
+		return &TypeError{Command: idx, Argument: 0, Message: message}
+	}
+	for i, argument := range amounts {
+		amount, err := c.value(idx, i+1, argument)
+		if err != nil {
+			return err
+		}
+		if amount.object || amount.gasCoin || amount.typeTag != nil && amount.typeTag.U64 == nil {
+			return &TypeError{Command: idx, Argument: i + 1, Message: "the amount must be a u64"}
+		}
+	}
+
+	results := commandResults{known: true, types: make([]*move_types.TypeTag, len(amounts))}
+	for i := range results.types {
+		results.types[i] = value.typeTag
+	}
+	c.results[idx] = results
+	return nil
+}
+
+func (c *typeChecker) mergeCoins(idx int, destination sui_types.Argument, sources []sui_types.Argument) error {
+	value, err := c.value(idx, 0, destination)
+	if err != nil {
+		return err
+	}
+	if message := c.checkCoin(value); message != "" {
+		return &TypeError{Command: idx, Argument: 0, Message: message}
+	}
+
+	for i, argument := range sources {
+		source, err := c.value(idx, i+1, argument)
+		if err != nil {
+			return err
+		}
+		switch {
+		case source.gasCoin:
+			return &TypeError{Command: idx, Argument: i + 1, Message: "the gas coin can only be the destination of MergeCoins"}
+		case source.key == value.key:
+			return &TypeError{Command: idx, Argument: i + 1, Message: fmt.Sprintf("%s is merged into itself", source.key)}
+		}
+		if message := c.checkCoin(source); message != "" {
+			return &TypeError{Command: idx, Argument: i + 1, Message: message}
+		}
+		if value.typeTag != nil && source.typeTag != nil && FormatTypeTag(*value.typeTag) != FormatTypeTag(*source.typeTag) {
+			return &TypeError{Command: idx, Argument: i + 1, Message: fmt.Sprintf("expected a coin of type [%s], got [%s]", FormatTypeTag(*value.typeTag), FormatTypeTag(*source.typeTag))}
+		}
+		c.move(idx, source)
+	}
+	c.results[idx] = commandResults{known: true}
+	return nil
+}
+
+// checkCoin checks a value used as a coin, it returns the reason when the value is not a coin.
+func (c *typeChecker) checkCoin(value *argumentValue) string {
+	if value.pure {
+		return "a pure value can not be used as a coin"
+	}
+	if value.typeTag != nil && (value.typeTag.Struct == nil || structName(value.typeTag.Struct) != utils.SuiFrameworkAddress+"::coin::Coin") {
+		return fmt.Sprintf("expected a coin, got [%s]", FormatTypeTag(*value.typeTag))
+	}
+	return ""
+}
+
+func (c *typeChecker) makeMoveVec(idx int, typeTag *move_types.TypeTag, elements []sui_types.Argument) error {
+	if typeTag == nil && len(elements) == 0 {
+		return &TypeError{Command: idx, Argument: -1, Message: "the type of an empty vector must be specified"}
+	}
+
+	elementType := typeTag
+	for i, argument := range elements {
+		value, err := c.value(idx, i, argument)
+		if err != nil {
+			return err
+		}
+		switch {
+		case value.gasCoin:
+			return &TypeError{Command: idx, Argument: i, Message: "the gas coin can only be passed by value to TransferObjects"}
+		case value.pure && typeTag == nil:
+			return &TypeError{Command: idx, Argument: i, Message: "the type of a vector of pure values must be specified"}
+		case value.pure && !isPureType(typeTag):
+			return &TypeError{Command: idx, Argument: i, Message: fmt.Sprintf("a pure value can not be an element of type [%s]", FormatTypeTag(*typeTag))}
+		case value.object && typeTag != nil && isPureType(typeTag):
+			return &TypeError{Command: idx, Argument: i, Message: fmt.Sprintf("an object can not be an element of type [%s]", FormatTypeTag(*typeTag))}
+		case value.typeTag != nil && elementType != nil && FormatTypeTag(*value.typeTag) != FormatTypeTag(*elementType):
+			return &TypeError{Command: idx, Argument: i, Message: fmt.Sprintf("expected an element of type [%s], got [%s]", FormatTypeTag(*elementType), FormatTypeTag(*value.typeTag))}
+		}
+		if elementType == nil {
+			elementType = value.typeTag
+		}
+		c.move(idx, value)
+	}
+
+	results := commandResults{known: true, types: []*move_types.TypeTag{nil}}
+	if elementType != nil {
+		results.types[0] = &move_types.TypeTag{Vector: elementType}
+	}
+	c.results[idx] = results
+	return nil
+}
+
+// value returns the value of an argument of a command, values moved by previous commands can not be used.
+func (c *typeChecker) value(idx, argumentIdx int, argument sui_types.Argument) (*argumentValue, error) {
+	value, message := c.argumentValue(idx, argument)
+	if message == "" {
+		if command, ok := c.moved[value.key]; ok {
+			message = fmt.Sprintf("%s is used after it is moved in command %d", value.key, command)
+		}
+	}
+	if message != "" {
+		return nil, &TypeError{Command: idx, Argument: argumentIdx, Message: message}
+	}
+	return value, nil
+}
+
+func (c *typeChecker) argumentValue(idx int, argument sui_types.Argument) (*argumentValue, string) {
+	switch {
+	case argument.GasCoin != nil:
+		coin, _ := ParseTypeTag(utils.SuiFrameworkAddress + "::coin::Coin<" + utils.SuiTypeArg + ">")
+		return &argumentValue{key: "GasCoin", typeTag: coin, gasCoin: true}, ""
+	case argument.Input != nil:
+		keys := c.txb.builder.InputsKeyOrder
+		if int(*argument.Input) >= len(keys) {
+			return nil, fmt.Sprintf("input %d does not exist", *argument.Input)
+		}
+		value := &argumentValue{key: fmt.Sprintf("Input(%d)", *argument.Input)}
+		key := keys[*argument.Input]
+		if key.Object == nil {
+			value.pure = true
+			return value, ""
+		}
+		id := key.Object.String()
+		value.object = true
+		value.receiving = c.txb.receivingObjects[id] || c.txb.suppliedObjects[id].receiving
+		if input, ok := c.txb.builder.Inputs[key.String()]; ok && input.Object != nil && input.Object.SharedObject != nil {
+			value.immutableShared = !input.Object.SharedObject.Mutable
+		}
+		return value, ""
+	case argument.Result != nil:
+		return c.result(idx, *argument.Result, nil)
+	case argument.NestedResult != nil:
+		return c.result(idx, argument.NestedResult.Result1, &argument.NestedResult.Result2)
+	default:
+		return nil, "invalid argument"
+	}
+}
+
+func (c *typeChecker) result(idx int, command uint16, nested *uint16) (*argumentValue, string) {
+	if int(command) >= idx {
+		return nil, fmt.Sprintf("the result of command %d is used before it is created", command)
+	}
+
+	results := c.results[command]
+	if nested == nil {
+		if results.known && len(results.types) != 1 {
+			return nil, fmt.Sprintf("command %d returns %d values, a nested result must be used", command, len(results.types))
+		}
+		nested = new(uint16)
+	}
+	value := &argumentValue{key: fmt.Sprintf("Result(%d.%d)", command, *nested)}
+	if !results.known {
+		return value, ""
+	}
+	if int(*nested) >= len(results.types) {
+		return nil, fmt.Sprintf("command %d returns %d values, there is no value at index %d", command, len(results.types), *nested)
+	}
+	value.typeTag = results.types[*nested]
+	return value, ""
+}
+
+// move marks a value passed by value as moved, copyable values are not moved.
+func (c *typeChecker) move(idx int, value *argumentValue) {
+	if !c.copyable(value) {
+		c.moved[value.key] = idx
+	}
+}
+
+// copyable reports whether a value can be used after it is passed by value, values of unknown types are assumed to be copyable.
+func (c *typeChecker) copyable(value *argumentValue) bool {
+	if value.pure {
+		return true
+	}
+	if value.object || value.gasCoin {
+		return false
+	}
+	abilities, ok := c.typeAbilities(value.typeTag)
+	return !ok || abilities&abilityCopy != 0
+}
+
+func (c *typeChecker) typeAbilities(typeTag *move_types.TypeTag) (abilitySet, bool) {
+	if typeTag == nil {
+		return 0, false
+	}
+	return c.abilities(typeTag)
+}
+
+// abilities returns the abilities of a Move type, it returns false when the definition of a struct is not registered.
+func (c *typeChecker) abilities(typeTag *move_types.TypeTag) (abilitySet, bool) {
+	switch {
+	case typeTag.Signer != nil:
+		return abilityDrop, true
+	case typeTag.Vector != nil:
+		element, ok := c.abilities(typeTag.Vector)
+		return element & primitiveAbilities, ok
+	case typeTag.Struct != nil:
+		abilities, ok := builtinAbilities(typeTag.Struct)
+		var phantom []bool
+		if !ok {
+			definition := c.registry.lookup(typeTag.Struct)
+			if definition == nil {
+				return 0, false
+			}
+			abilities = newAbilitySet(definition.Abilities)
+			for _, param := range definition.TypeParameters {
+				phantom = append(phantom, param.IsPhantom)
+			}
+		}
+		// the abilities of a struct instance are limited by the abilities of its non-phantom type arguments
+		for i, param := range typeTag.Struct.TypeParams {
+			if i < len(phantom) && phantom[i] {
+				continue
+			}
+			paramAbilities, ok := c.abilities(&param)
+			if !ok {
+				return 0, false
+			}
+			if paramAbilities&abilityStore == 0 {
+				abilities &^= abilityKey
+			}
+			abilities &^= primitiveAbilities &^ paramAbilities
+		}
+		return abilities, true
+	default:
+		return primitiveAbilities, true
+	}
+}
+
+// builtinAbilities returns the abilities of the structs that are decoded without their definitions.
+func builtinAbilities(tag *move_types.StructTag) (abilitySet, bool) {
+	switch structName(tag) {
+	case stringStruct, asciiStringStruct, idStruct, optionStruct:
+		return primitiveAbilities, true
+	case uidStruct:
+		return abilityStore, true
+	default:
+		return 0, false
+	}
+}
+
+// parameterType returns the type of a parameter with the type parameters replaced by the type arguments, and how the
+// value is passed.
+func parameterType(normalizedType types.SuiMoveNormalizedType, typeArguments []move_types.TypeTag) (*move_types.TypeTag, reference, error) {
+	ref := byValue
+	switch t := normalizedType.(type) {
+	case types.SuiMoveNormalizedTypeReference:
+		ref, normalizedType = byReference, t.Reference.SuiMoveNormalizedType
+	case types.SuiMoveNormalizedTypeMutableReference:
+		ref, normalizedType = byMutableReference, t.MutableReference.SuiMoveNormalizedType
+	}
+	typeTag, err := normalizedTypeToTypeTag(normalizedType, typeArguments)
+	return typeTag, ref, err
+}
+
+// isPureType reports whether values of a Move type can be passed as pure inputs.
+func isPureType(typeTag *move_types.TypeTag) bool {
+	switch {
+	case typeTag.Signer != nil:
+		return false
+	case typeTag.Vector != nil:
+		return isPureType(typeTag.Vector)
+	case typeTag.Struct != nil:
+		switch structName(typeTag.Struct) {
+		case stringStruct, asciiStringStruct, idStruct:
+			return true
+		case optionStruct:
+			return len(typeTag.Struct.TypeParams) == 1 && isPureType(&typeTag.Struct.TypeParams[0])
+		}
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,307 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+)
+
+// typeCheckFunctions returns the signatures of the functions of the module `pool`, Pool<phantom T> has key and store.
+func typeCheckFunctions(pkg string) map[string]string {
+	pool := `{"Struct": {"address": "` + pkg + `", "module": "pool", "name": "Pool", "typeArguments": [{"TypeParameter": 0}]}}`
+	coin := `{"Struct": {"address": "0x2", "module": "coin", "name": "Coin", "typeArguments": [{"TypeParameter": 0}]}}`
+	txContext := `{"MutableReference": {"Struct": {"address": "0x2", "module": "tx_context", "name": "TxContext", "typeArguments": []}}}`
+	return map[string]string{
+		"new":     `{"visibility": "Public", "isEntry": false, "typeParameters": [{"abilities": ["Store"]}], "parameters": ["U64", ` + txContext + `], "return": [` + pool + `]}`,
+		"deposit": `{"visibility": "Public", "isEntry": false, "typeParameters": [{"abilities": []}], "parameters": [{"MutableReference": ` + pool + `}, ` + coin + `], "return": []}`,
+		"value":   `{"visibility": "Public", "isEntry": false, "typeParameters": [{"abilities": []}], "parameters": [{"Reference": ` + pool + `}], "return": ["U64"]}`,
+		"destroy": `{"visibility": "Public", "isEntry": false, "typeParameters": [{"abilities": []}], "parameters": [` + pool + `], "return": []}`,
+		"merge":   `{"visibility": "Public", "isEntry": false, "typeParameters": [{"abilities": []}], "parameters": [{"MutableReference": ` + pool + `}, {"Reference": ` + pool + `}], "return": []}`,
+		"pair":    `{"visibility": "Public", "isEntry": false, "typeParameters": [], "parameters": [], "return": ["U64", "U64"]}`,
+		"name":    `{"visibility": "Public", "isEntry": false, "typeParameters": [], "parameters": [{"Struct": {"address": "0x1", "module": "string", "name": "String", "typeArguments": []}}], "return": []}`,
+		"inner":   `{"visibility": "Friend", "isEntry": false, "typeParameters": [], "parameters": [], "return": []}`,
+	}
+}
+
+func supplyTypeCheckFunctions(t *testing.T, tx *transactions.Transaction, pkg string) {
+	for name, function := range typeCheckFunctions(pkg) {
+		var normalized types.SuiMoveNormalizedFunction
+		if err := json.Unmarshal([]byte(function), &normalized); err != nil {
+			t.Fatalf("failed to unmarshal function [%s]: %v", name, err)
+		}
+		if err := tx.SupplyMoveFunction(pkg+"::pool::"+name, &normalized); err != nil {
+			t.Fatalf("failed to supply function [%s]: %v", name, err)
+		}
+	}
+}
+
+func TestCheckTypes(t *testing.T) {
+	pkg, pool := objectID(0x42001), objectID(0x42002)
+	sui, other, hot := "0x2::sui::SUI", pkg+"::pool::Other", pkg+"::pool::Hot"
+	registry := transactions.DefaultTypeRegistry()
+	for name, abilities := range map[string]string{"Pool": `["Key", "Store"]`, "Other": `["Drop", "Store"]`, "Hot": `["Drop"]`} {
+		var definition types.SuiMoveNormalizedStruct
+		if err := json.Unmarshal([]byte(`{"abilities": {"abilities": `+abilities+`}, "typeParameters": [{"constraints": {"abilities": []}, "isPhantom": true}], "fields": []}`), &definition); err != nil {
+			t.Fatalf("failed to unmarshal struct: %v", err)
+		}
+		if err := registry.RegisterStruct(pkg+"::pool::"+name, &definition); err != nil {
+			t.Fatalf("failed to register struct: %v", err)
+		}
+	}
+
+	call := func(tx *transactions.Transaction, name string, arguments []transactions.Arg, typeArguments ...string) transactions.Result {
+		result, err := tx.AddMoveCall(pkg+"::pool::"+name, arguments, typeArguments)
+		if err != nil {
+			t.Fatalf("failed to add move call [%s]: %v", name, err)
+		}
+		return result
+	}
+	split := func(tx *transactions.Transaction, coin transactions.Arg) transactions.Result {
+		result, err := tx.AddSplitCoins(coin, []transactions.Arg{transactions.Pure(uint64(1))})
+		if err != nil {
+			t.Fatalf("failed to add split coins: %v", err)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		build    func(tx *transactions.Transaction)
+		command  int
+		argument int
+		message  string
+	}{
+		{
+			name: "valid",
+			build: func(tx *transactions.Transaction) {
+				created := call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1))}, sui)
+				call(tx, "deposit", []transactions.Arg{created, split(tx, tx.Gas())}, sui)
+				call(tx, "merge", []transactions.Arg{created, tx.SharedObjectRef(pool, 1, false)}, sui)
+				call(tx, "name", []transactions.Arg{transactions.Pure("pool")})
+				pair := call(tx, "pair", nil)
+				if _, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{pair.Nested(0), pair.Nested(1)}); err != nil {
+					t.Fatalf("failed to add split coins: %v", err)
+				}
+				if err := tx.AddTransferObjects([]transactions.Arg{created}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+					t.Fatalf("failed to add transfer objects: %v", err)
+				}
+			},
+		},
+		{
+			name: "type argument mismatch",
+			build: func(tx *transactions.Transaction) {
+				created := call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1))}, sui)
+				call(tx, "value", []transactions.Arg{created}, other)
+			},
+			command: 1, argument: 0, message: "expected a value of type [" + pkg + "::pool::Pool<" + pkg + "::pool::Other>], got [" + pkg + "::pool::Pool<0x0000000000000000000000000000000000000000000000000000000000000002::sui::SUI>]",
+		},
+		{
+			name: "coin type mismatch",
+			build: func(tx *transactions.Transaction) {
+				created := call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1))}, other)
+				call(tx, "deposit", []transactions.Arg{created, split(tx, tx.Gas())}, other)
+			},
+			command: 2, argument: 1, message: "::coin::Coin<" + pkg + "::pool::Other>], got [",
+		},
+		{
+			name: "missing ability",
+			build: func(tx *transactions.Transaction) {
+				call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1))}, hot)
+			},
+			command: 0, argument: -1, message: "does not have the abilities: store",
+		},
+		{
+			name: "used after move",
+			build: func(tx *transactions.Transaction) {
+				created := call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1))}, sui)
+				call(tx, "destroy", []transactions.Arg{created}, sui)
+				call(tx, "value", []transactions.Arg{created}, sui)
+			},
+			command: 2, argument: 0, message: "Result(0.0) is used after it is moved in command 1",
+		},
+		{
+			name: "copyable value used twice",
+			build: func(tx *transactions.Transaction) {
+				pair := call(tx, "pair", nil)
+				call(tx, "new", []transactions.Arg{pair.Nested(1)}, sui)
+				call(tx, "new", []transactions.Arg{pair.Nested(1)}, sui)
+			},
+		},
+		{
+			name: "mutable borrow aliased",
+			build: func(tx *transactions.Transaction) {
+				created := call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1))}, sui)
+				call(tx, "merge", []transactions.Arg{created, created}, sui)
+			},
+			command: 1, argument: 1, message: "Result(0.0) is used more than once while it is borrowed mutably or moved",
+		},
+		{
+			name: "pure value as object",
+			build: func(tx *transactions.Transaction) {
+				call(tx, "value", []transactions.Arg{transactions.Pure(uint64(1))}, sui)
+			},
+			command: 0, argument: 0, message: "a pure value can not be passed to a parameter of type",
+		},
+		{
+			name: "object as pure value",
+			build: func(tx *transactions.Transaction) {
+				call(tx, "name", []transactions.Arg{tx.SharedObjectRef(pool, 1, true)})
+			},
+			command: 0, argument: 0, message: "an object can not be passed to a parameter of type [0x0000000000000000000000000000000000000000000000000000000000000001::string::String]",
+		},
+		{
+			name: "gas coin by value",
+			build: func(tx *transactions.Transaction) {
+				created := call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1))}, sui)
+				call(tx, "deposit", []transactions.Arg{created, tx.Gas()}, sui)
+			},
+			command: 1, argument: 1, message: "the gas coin can only be passed by value to TransferObjects",
+		},
+		{
+			name: "multiple results",
+			build: func(tx *transactions.Transaction) {
+				call(tx, "new", []transactions.Arg{call(tx, "pair", nil)}, sui)
+			},
+			command: 1, argument: 0, message: "command 0 returns 2 values, a nested result must be used",
+		},
+		{
+			name: "missing nested result",
+			build: func(tx *transactions.Transaction) {
+				call(tx, "new", []transactions.Arg{call(tx, "pair", nil).Nested(2)}, sui)
+			},
+			command: 1, argument: 0, message: "command 0 returns 2 values, there is no value at index 2",
+		},
+		{
+			name: "result as amount",
+			build: func(tx *transactions.Transaction) {
+				created := call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1))}, sui)
+				if _, err := tx.AddSplitCoins(tx.Gas(), []transactions.Arg{created}); err != nil {
+					t.Fatalf("failed to add split coins: %v", err)
+				}
+			},
+			command: 1, argument: 1, message: "the amount must be a u64",
+		},
+		{
+			name: "transfer without store",
+			build: func(tx *transactions.Transaction) {
+				pair := call(tx, "pair", nil)
+				if err := tx.AddTransferObjects([]transactions.Arg{pair.Nested(0)}, transactions.Pure(sui_types.SuiAddress{})); err != nil {
+					t.Fatalf("failed to add transfer objects: %v", err)
+				}
+			},
+			command: 1, argument: 0, message: "a value of type [u64] can not be transferred without the key and store abilities",
+		},
+		{
+			name: "private function",
+			build: func(tx *transactions.Transaction) {
+				call(tx, "inner", nil)
+			},
+			command: 0, argument: -1, message: "is neither public nor entry",
+		},
+		{
+			name: "wrong number of arguments",
+			build: func(tx *transactions.Transaction) {
+				call(tx, "new", []transactions.Arg{transactions.Pure(uint64(1)), transactions.Pure(uint64(2)), transactions.Pure(uint64(3))}, sui)
+			},
+			command: 0, argument: -1, message: "has 1 parameters, got 3 arguments",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := transactions.NewTransaction(nil)
+			tx.SetCache(transactions.NewLRUCache(0))
+			supplyTypeCheckFunctions(t, tx, pkg)
+			tt.build(tx)
+
+			err := tx.CheckTypes(context.Background())
+			if tt.message == "" {
+				if err != nil {
+					t.Fatalf("expected no type error, but got %v", err)
+				}
+				return
+			}
+			var typeError *transactions.TypeError
+			if !errors.As(err, &typeError) {
+				t.Fatalf("expected a type error, but got %v", err)
+			}
+			if typeError.Command != tt.command || typeError.Argument != tt.argument || !strings.Contains(typeError.Message, tt.message) {
+				t.Errorf("expected [%s] in argument %d of command %d, but got %v", tt.message, tt.argument, tt.command, typeError)
+			}
+		})
+	}
+
+	tx := transactions.NewTransaction(nil)
+	tx.SetCache(transactions.NewLRUCache(0))
+	supplyTypeCheckFunctions(t, tx, pkg)
+	call(tx, "value", []transactions.Arg{transactions.Pure(uint64(1))}, sui)
+	tx.SetGasPrice(1000)
+	tx.SetGasBudget(1000000)
+	tx.SetGasPayment([]*sui_types.ObjectRef{objectRef(t, objectID(0x42003), 1)})
+	var typeError *transactions.TypeError
+	if _, _, err := tx.Build(context.Background(), recipient); !errors.As(err, &typeError) {
+		t.Errorf("expected a type error when building the transaction, but got %v", err)
+	}
+}
+
+func TestCheckTypesFetchesDefinitions(t *testing.T) {
+	pkg, pool, config := objectID(0x42101), objectID(0x42102), objectID(0x42103)
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_getNormalizedMoveModulesByPackage": normalizedModules,
+	})
+
+	tx := transactions.NewTransaction(suiClient)
+	tx.SetCache(transactions.NewLRUCache(0))
+	if _, err := tx.AddMoveCall(pkg+"::pool::deposit", []transactions.Arg{tx.Object(pool), tx.Object(config), transactions.Pure(uint64(1))}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+	if _, err := tx.AddMoveCall(pkg+"::pool::deposit", []transactions.Arg{tx.Object(pool), transactions.Pure(uint64(1)), transactions.Pure(uint64(1))}, nil); err != nil {
+		t.Fatalf("failed to add move call: %v", err)
+	}
+
+	var typeError *transactions.TypeError
+	if err := tx.CheckTypes(context.Background()); !errors.As(err, &typeError) || typeError.Command != 1 || typeError.Argument != 1 {
+		t.Errorf("expected a type error in argument 1 of command 1, but got %v", err)
+	}
+	if got := node.count("sui_getNormalizedMoveModulesByPackage"); got != 1 {
+		t.Errorf("expected the modules to be fetched once, but got %d requests", got)
+	}
+
+	// the TxContext parameter is not counted, too many arguments return an error
+	if _, err := tx.MoveCall(context.Background(), pkg+"::pool::deposit", []any{pool, config, uint64(1), uint64(2)}, nil); err == nil || !strings.Contains(err.Error(), "incorrect number of arguments") {
+		t.Errorf("expected an error for too many arguments, but got %v", err)
+	}
+	if _, err := tx.MoveCall(context.Background(), pkg+"::pool::deposit", []any{pool}, nil); err == nil || !strings.Contains(err.Error(), "incorrect number of arguments") {
+		t.Errorf("expected an error for too few arguments, but got %v", err)
+	}
+}
+
+func TestMoveCallFetchesFunctionsPerPackage(t *testing.T) {
+	pkg, pool, config := objectID(0x42111), objectID(0x42112), objectID(0x42113)
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_getNormalizedMoveModulesByPackage": normalizedModules,
+	})
+
+	tx := transactions.NewTransaction(suiClient)
+	tx.SetCache(transactions.NewLRUCache(0))
+	for i := 0; i < 3; i++ {
+		if _, err := tx.MoveCall(context.Background(), pkg+"::pool::deposit", []any{pool, config, uint64(i)}, nil); err != nil {
+			t.Fatalf("failed to add move call %d: %v", i, err)
+		}
+	}
+	if got := node.count("sui_getNormalizedMoveModulesByPackage"); got != 1 {
+		t.Errorf("expected the modules to be fetched once, but got %d requests", got)
+	}
+	if got := node.count("sui_getNormalizedMoveFunction"); got != 0 {
+		t.Errorf("expected no function requests, but got %d", got)
+	}
+
+	if _, err := tx.MoveCall(context.Background(), pkg+"::pool::withdraw", []any{pool}, nil); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected an error for a missing function, but got %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package transactions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+)
+
+// ParseTypeTag parses a Move type such as `u64`, `vector<u8>` or `0x2::coin::Coin<0x2::sui::SUI>`.
+func ParseTypeTag(moveType string) (*move_types.TypeTag, error) {
+	p := &typeTagParser{input: moveType}
+	typeTag, err := p.typeTag(0)
+	if err == nil && p.skipSpaces() < len(p.input) {
+		err = fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid type [%s]: %v", moveType, err)
+	}
+	return typeTag, nil
+}
+
+// FormatTypeTag formats a Move type with full length addresses, the format is accepted by ParseTypeTag.
+func FormatTypeTag(typeTag move_types.TypeTag) string {
+	switch {
+	case typeTag.Bool != nil:
+		return "bool"
+	case typeTag.U8 != nil:
+		return "u8"
+	case typeTag.U16 != nil:
+		return "u16"
+	case typeTag.U32 != nil:
+		return "u32"
+	case typeTag.U64 != nil:
+		return "u64"
+	case typeTag.U128 != nil:
+		return "u128"
+	case typeTag.U256 != nil:
+		return "u256"
+	case typeTag.Address != nil:
+		return "address"
+	case typeTag.Signer != nil:
+		return "signer"
+	case typeTag.Vector != nil:
+		return "vector<" + FormatTypeTag(*typeTag.Vector) + ">"
+	case typeTag.Struct != nil:
+		name := structName(typeTag.Struct)
+		if len(typeTag.Struct.TypeParams) == 0 {
+			return name
+		}
+		params := make([]string, len(typeTag.Struct.TypeParams))
+		for idx, param := range typeTag.Struct.TypeParams {
+			params[idx] = FormatTypeTag(param)
+		}
+		return name + "<" + strings.Join(params, ", ") + ">"
+	default:
+		return "unknown"
+	}
+}
+
+// structName returns the full length address, module and name of a struct without its type parameters.
+func structName(tag *move_types.StructTag) string {
+	return fmt.Sprintf("%s::%s::%s", tag.Address.String(), tag.Module, tag.Name)
+}
+
+// typeTagParser parses Move types, addresses and identifiers are tokens ending at a delimiter.
+type typeTagParser struct {
+	input string
+	pos   int
+}
+
+func (p *typeTagParser) skipSpaces() int {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+	return p.pos
+}
+
+func (p *typeTagParser) token() (string, error) {
+	start := p.skipSpaces()
+	for p.pos < len(p.input) && !strings.ContainsRune("<>,: ", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", fmt.Errorf("missing identifier at position %d", start)
+	}
+	return p.input[start:p.pos], nil
+}
+
+// consume skips the delimiter when it follows, and reports whether it was found.
+func (p *typeTagParser) consume(delimiter string) bool {
+	if strings.HasPrefix(p.input[p.skipSpaces():], delimiter) {
+		p.pos += len(delimiter)
+		return true
+	}
+	return false
+}
+
+func (p *typeTagParser) expect(delimiter string) error {
+	if !p.consume(delimiter) {
+		return fmt.Errorf("missing %q at position %d", delimiter, p.pos)
+	}
+	return nil
+}
+
+func (p *typeTagParser) typeTag(depth int) (*move_types.TypeTag, error) {
+	if depth > maxTypeTagDepth {
+		return nil, fmt.Errorf("type nested deeper than %d", maxTypeTagDepth)
+	}
+
+	token, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+
+	typeTag := new(move_types.TypeTag)
+	switch token {
+	case "bool":
+		typeTag.Bool = &lib.EmptyEnum{}
+	case "u8":
+		typeTag.U8 = &lib.EmptyEnum{}
+	case "u16":
+		typeTag.U16 = &lib.EmptyEnum{}
+	case "u32":
+		typeTag.U32 = &lib.EmptyEnum{}
+	case "u64":
+		typeTag.U64 = &lib.EmptyEnum{}
+	case "u128":
+		typeTag.U128 = &lib.EmptyEnum{}
+	case "u256":
+		typeTag.U256 = &lib.EmptyEnum{}
+	case "address":
+		typeTag.Address = &lib.EmptyEnum{}
+	case "signer":
+		typeTag.Signer = &lib.EmptyEnum{}
+	case "vector":
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		if typeTag.Vector, err = p.typeTag(depth + 1); err != nil {
+			return nil, err
+		}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+	default:
+		if typeTag.Struct, err = p.structTag(token, depth); err != nil {
+			return nil, err
+		}
+	}
+	return typeTag, nil
+}
+
+func (p *typeTagParser) structTag(address string, depth int) (*move_types.StructTag, error) {
+	if !strings.HasPrefix(address, "0x") {
+		return nil, fmt.Errorf("unknown type [%s]", address)
+	}
+	pkg, err := sui_types.NewAddressFromHex(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address [%s]: %v", address, err)
+	}
+
+	var names [2]string
+	for idx := range names {
+		if err := p.expect("::"); err != nil {
+			return nil, err
+		}
+		if names[idx], err = p.token(); err != nil {
+			return nil, err
+		}
+	}
+
+	tag := &move_types.StructTag{Address: *pkg, Module: move_types.Identifier(names[0]), Name: move_types.Identifier(names[1]), TypeParams: []move_types.TypeTag{}}
+	if !p.consume("<") {
+		return tag, nil
+	}
+	for {
+		param, err := p.typeTag(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		tag.TypeParams = append(tag.TypeParams, *param)
+		if p.consume(">") {
+			return tag, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
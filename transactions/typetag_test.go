@@ -0,0 +1,43 @@
+package transactions_test
+
+import (
+	"testing"
+
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/utils"
+)
+
+func TestParseTypeTag(t *testing.T) {
+	sui, std := utils.SuiFrameworkAddress, utils.MoveStdlibAddress
+
+	tests := []struct {
+		moveType string
+		expected string
+	}{
+		{moveType: "u8", expected: "u8"},
+		{moveType: "vector<vector<u256>>", expected: "vector<vector<u256>>"},
+		{moveType: "0x2::sui::SUI", expected: sui + "::sui::SUI"},
+		{moveType: "0x2::coin::Coin<0x2::sui::SUI>", expected: sui + "::coin::Coin<" + sui + "::sui::SUI>"},
+		{moveType: "0x1::option::Option<vector<0x2::table::Table<address,  u64>>>", expected: std + "::option::Option<vector<" + sui + "::table::Table<address, u64>>>"},
+		{moveType: sui + "::dynamic_field::Field<u64, bool>", expected: sui + "::dynamic_field::Field<u64, bool>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.moveType, func(t *testing.T) {
+			typeTag, err := transactions.ParseTypeTag(tt.moveType)
+			if err != nil {
+				t.Fatalf("failed to parse type: %v", err)
+			}
+			if got := transactions.FormatTypeTag(*typeTag); got != tt.expected {
+				t.Errorf("expected type %s, but got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseTypeTagErrors(t *testing.T) {
+	for _, moveType := range []string{"", "u63", "vector<u8", "vector<u8>>", "0x2::coin", "0x2::coin::Coin<>", "0x2::coin::Coin<u8 u8>", "0xg::coin::Coin"} {
+		if _, err := transactions.ParseTypeTag(moveType); err == nil {
+			t.Errorf("expected an error for type [%s], but got nil", moveType)
+		}
+	}
+}
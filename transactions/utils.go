@@ -2,22 +2,45 @@ package transactions
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/W3Tools/gosui/client"
 	"github.com/W3Tools/gosui/types"
 )
 
-func getNormalizedMoveFunctionFromCache(ctx context.Context, suiClient *client.SuiClient, pkg, mod, fn string) (*types.SuiMoveNormalizedFunction, error) {
+// getNormalizedMoveFunctionFromCache returns the cached signature of a Move function. When it is not cached, the functions
+// of all modules of the package are fetched with one request and cached, so later calls into the package make no request.
+// Without a SuiClient a *MissingDataError is returned for a signature that is not cached.
+func getNormalizedMoveFunctionFromCache(ctx context.Context, suiClient *client.SuiClient, cache Cache, pkg, mod, fn string) (*types.SuiMoveNormalizedFunction, error) {
 	entry := cache.GetMoveFunctionDefinition(pkg, mod, fn)
 	if entry != nil {
 		return entry.Normalized, nil
 	}
+	if suiClient == nil {
+		return nil, &MissingDataError{Missing: []string{fmt.Sprintf("normalized function [%s::%s::%s]", pkg, mod, fn)}}
+	}
 
-	result, err := suiClient.GetNormalizedMoveFunction(ctx, types.GetNormalizedMoveFunctionParams{Package: pkg, Module: mod, Function: fn})
+	if err := cacheNormalizedMoveModules(ctx, suiClient, cache, pkg); err != nil {
+		return nil, fmt.Errorf("can not get normalized move modules of package [%s]: %v", pkg, err)
+	}
+	if entry = cache.GetMoveFunctionDefinition(pkg, mod, fn); entry == nil {
+		return nil, fmt.Errorf("function [%s::%s::%s] not found", pkg, mod, fn)
+	}
+	return entry.Normalized, nil
+}
+
+// cacheNormalizedMoveModules fetches all modules of a package with one request and caches their exposed functions.
+func cacheNormalizedMoveModules(ctx context.Context, suiClient *client.SuiClient, cache Cache, pkg string) error {
+	modules, err := suiClient.GetNormalizedMoveModulesByPackage(ctx, types.GetNormalizedMoveModulesByPackageParams{Package: pkg})
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	for mod, module := range *modules {
+		for fn, function := range module.ExposedFunctions {
+			cache.AddMoveFunctionDefinition(&MoveFunctionCacheEntry{Package: pkg, Module: mod, Function: fn, Normalized: &function})
+		}
 	}
 
-	cache.AddMoveFunctionDefinition(&MoveFunctionCacheEntry{Package: pkg, Module: mod, Function: fn, Normalized: result})
-	return result, nil
+	return nil
 }
@@ -0,0 +1,330 @@
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/W3Tools/go-sui-sdk/v2/lib"
+	"github.com/W3Tools/go-sui-sdk/v2/move_types"
+	"github.com/W3Tools/go-sui-sdk/v2/sui_types"
+	"github.com/W3Tools/gosui/client"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// maxValueDepth defines the maximum nesting of decoded Move values.
+const maxValueDepth = 64
+
+var (
+	stringStruct      = utils.MoveStdlibAddress + "::string::String"
+	asciiStringStruct = utils.MoveStdlibAddress + "::ascii::String"
+	optionStruct      = utils.MoveStdlibAddress + "::option::Option"
+	idStruct          = utils.SuiFrameworkAddress + "::object::ID"
+	uidStruct         = utils.SuiFrameworkAddress + "::object::UID"
+
+	defaultTypeRegistry = NewTypeRegistry()
+)
+
+// TypeRegistry holds the definitions of Move structs used to decode BCS-encoded values, it is safe for concurrent use.
+// Strings, options, IDs and UIDs are decoded without definitions.
+type TypeRegistry struct {
+	mutex   sync.RWMutex
+	structs map[string]*types.SuiMoveNormalizedStruct // map key is the struct name with a full length address
+}
+
+// NewTypeRegistry creates an empty TypeRegistry.
+func NewTypeRegistry() *TypeRegistry {
+	return &TypeRegistry{structs: make(map[string]*types.SuiMoveNormalizedStruct)}
+}
+
+// DefaultTypeRegistry returns the TypeRegistry used by ViewCall.
+func DefaultTypeRegistry() *TypeRegistry {
+	return defaultTypeRegistry
+}
+
+// RegisterStruct registers the definition of a struct by its name in the format `address::module::name`.
+func (r *TypeRegistry) RegisterStruct(name string, definition *types.SuiMoveNormalizedStruct) error {
+	pkg, module, structName, err := parseMoveCallTarget(name)
+	if err != nil {
+		return fmt.Errorf("invalid struct name [%s]", name)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.structs[fmt.Sprintf("%s::%s::%s", pkg, module, structName)] = definition
+	return nil
+}
+
+// RegisterModule registers the definitions of all structs in a module.
+func (r *TypeRegistry) RegisterModule(module *types.SuiMoveNormalizedModule) error {
+	for name, definition := range module.Structs {
+		if err := r.RegisterStruct(fmt.Sprintf("%s::%s::%s", module.Address, module.Name, name), &definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TypeRegistry) lookup(tag *move_types.StructTag) *types.SuiMoveNormalizedStruct {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.structs[structName(tag)]
+}
+
+// Load fetches the definitions of the structs in a Move type which are not registered.
+func (r *TypeRegistry) Load(ctx context.Context, client *client.SuiClient, moveType string) error {
+	typeTag, err := ParseTypeTag(moveType)
+	if err != nil {
+		return err
+	}
+	return r.load(ctx, client, typeTag, make(map[string]bool))
+}
+
+func (r *TypeRegistry) load(ctx context.Context, client *client.SuiClient, typeTag *move_types.TypeTag, loaded map[string]bool) error {
+	if typeTag.Vector != nil {
+		return r.load(ctx, client, typeTag.Vector, loaded)
+	}
+	if typeTag.Struct == nil || loaded[FormatTypeTag(*typeTag)] {
+		return nil
+	}
+	loaded[FormatTypeTag(*typeTag)] = true
+
+	// type parameters of other structs are loaded with the fields using them, phantom type parameters are never loaded
+	name := structName(typeTag.Struct)
+	if slices.Contains([]string{stringStruct, asciiStringStruct, optionStruct, idStruct, uidStruct}, name) {
+		for _, param := range typeTag.Struct.TypeParams {
+			if err := r.load(ctx, client, &param, loaded); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	definition := r.lookup(typeTag.Struct)
+	if definition == nil {
+		fetched, err := client.GetNormalizedMoveStruct(ctx, types.GetNormalizedMoveStructParams{Package: typeTag.Struct.Address.String(), Module: string(typeTag.Struct.Module), Struct: string(typeTag.Struct.Name)})
+		if err != nil {
+			return fmt.Errorf("failed to get struct [%s], err: %v", name, err)
+		}
+		if err := r.RegisterStruct(name, fetched); err != nil {
+			return err
+		}
+		definition = fetched
+	}
+
+	for _, field := range definition.Fields {
+		fieldType, err := normalizedTypeToTypeTag(field.Type.SuiMoveNormalizedType, typeTag.Struct.TypeParams)
+		if err != nil {
+			return fmt.Errorf("invalid type of field [%s] in struct [%s]: %v", field.Name, name, err)
+		}
+		if err := r.load(ctx, client, fieldType, loaded); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode decodes a BCS-encoded value of a Move type into a Go value: bool, uint8, uint16, uint32, uint64, *big.Int for
+// u128 and u256, a hex string for addresses and IDs, []byte for vector<u8>, []any for other vectors, string for strings,
+// nil or the value for options and map[string]any for other structs.
+func (r *TypeRegistry) Decode(bs []byte, moveType string) (any, error) {
+	typeTag, err := ParseTypeTag(moveType)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &bcsDecoder{data: bs}
+	value, err := r.decode(d, typeTag, 0)
+	if err == nil {
+		err = d.finish()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can not decode value of type [%s]: %v", moveType, err)
+	}
+	return value, nil
+}
+
+func (r *TypeRegistry) decode(d *bcsDecoder, typeTag *move_types.TypeTag, depth int) (any, error) {
+	if depth > maxValueDepth {
+		return nil, fmt.Errorf("value nested deeper than %d", maxValueDepth)
+	}
+
+	switch {
+	case typeTag.Bool != nil:
+		return d.bool()
+	case typeTag.U8 != nil:
+		return d.u8()
+	case typeTag.U16 != nil:
+		return d.u16()
+	case typeTag.U32 != nil:
+		return d.u32()
+	case typeTag.U64 != nil:
+		return d.u64()
+	case typeTag.U128 != nil:
+		return d.bigInt(16)
+	case typeTag.U256 != nil:
+		return d.bigInt(32)
+	case typeTag.Address != nil:
+		address, err := d.address()
+		return address.String(), err
+	case typeTag.Vector != nil:
+		if typeTag.Vector.U8 != nil {
+			return d.vector()
+		}
+		n, err := d.uleb128()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]any, 0, min(n, len(d.data)-d.pos))
+		for i := 0; i < n; i++ {
+			value, err := r.decode(d, typeTag.Vector, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case typeTag.Struct != nil:
+		return r.decodeStruct(d, typeTag.Struct, depth)
+	default:
+		return nil, fmt.Errorf("unsupported type [%s]", FormatTypeTag(*typeTag))
+	}
+}
+
+func (r *TypeRegistry) decodeStruct(d *bcsDecoder, tag *move_types.StructTag, depth int) (any, error) {
+	name := structName(tag)
+	switch name {
+	case stringStruct, asciiStringStruct:
+		return d.string()
+	case idStruct, uidStruct:
+		address, err := d.address()
+		return address.String(), err
+	case optionStruct:
+		if len(tag.TypeParams) != 1 {
+			return nil, fmt.Errorf("option must have one type parameter, got %d", len(tag.TypeParams))
+		}
+		some, err := d.uleb128()
+		if err != nil {
+			return nil, err
+		}
+		switch some {
+		case 0:
+			return nil, nil
+		case 1:
+			return r.decode(d, &tag.TypeParams[0], depth+1)
+		default:
+			return nil, fmt.Errorf("invalid option length %d at position %d", some, d.pos)
+		}
+	}
+
+	definition := r.lookup(tag)
+	if definition == nil {
+		return nil, fmt.Errorf("struct [%s] is not registered", name)
+	}
+	fields := make(map[string]any, len(definition.Fields))
+	for _, field := range definition.Fields {
+		fieldType, err := normalizedTypeToTypeTag(field.Type.SuiMoveNormalizedType, tag.TypeParams)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type of field [%s] in struct [%s]: %v", field.Name, name, err)
+		}
+		if fields[field.Name], err = r.decode(d, fieldType, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// bigInt decodes a little-endian unsigned integer of n bytes.
+func (d *bcsDecoder) bigInt(n int) (*big.Int, error) {
+	bs, err := d.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	be := slices.Clone(bs)
+	slices.Reverse(be)
+	return new(big.Int).SetBytes(be), nil
+}
+
+// normalizedTypeToTypeTag converts a normalized Move type to a type tag, type parameters are replaced by the type arguments.
+func normalizedTypeToTypeTag(normalizedType types.SuiMoveNormalizedType, typeArguments []move_types.TypeTag) (*move_types.TypeTag, error) {
+	switch t := normalizedType.(type) {
+	case types.SuiMoveNormalizedTypeString:
+		typeTag := new(move_types.TypeTag)
+		switch t {
+		case "Bool":
+			typeTag.Bool = &lib.EmptyEnum{}
+		case "U8":
+			typeTag.U8 = &lib.EmptyEnum{}
+		case "U16":
+			typeTag.U16 = &lib.EmptyEnum{}
+		case "U32":
+			typeTag.U32 = &lib.EmptyEnum{}
+		case "U64":
+			typeTag.U64 = &lib.EmptyEnum{}
+		case "U128":
+			typeTag.U128 = &lib.EmptyEnum{}
+		case "U256":
+			typeTag.U256 = &lib.EmptyEnum{}
+		case "Address":
+			typeTag.Address = &lib.EmptyEnum{}
+		case "Signer":
+			typeTag.Signer = &lib.EmptyEnum{}
+		default:
+			return nil, fmt.Errorf("unknown type [%s]", t)
+		}
+		return typeTag, nil
+	case types.SuiMoveNormalizedTypeVector:
+		element, err := normalizedTypeToTypeTag(t.Vector.SuiMoveNormalizedType, typeArguments)
+		if err != nil {
+			return nil, err
+		}
+		return &move_types.TypeTag{Vector: element}, nil
+	case types.SuiMoveNormalizedTypeStruct:
+		address, err := sui_types.NewAddressFromHex(t.Struct.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address [%s]: %v", t.Struct.Address, err)
+		}
+		tag := &move_types.StructTag{Address: *address, Module: move_types.Identifier(t.Struct.Module), Name: move_types.Identifier(t.Struct.Name), TypeParams: []move_types.TypeTag{}}
+		for _, argument := range t.Struct.TypeArguments {
+			param, err := normalizedTypeToTypeTag(argument.SuiMoveNormalizedType, typeArguments)
+			if err != nil {
+				return nil, err
+			}
+			tag.TypeParams = append(tag.TypeParams, *param)
+		}
+		return &move_types.TypeTag{Struct: tag}, nil
+	case types.SuiMoveNormalizedTypeTypeParameter:
+		if t.TypeParameter >= uint64(len(typeArguments)) {
+			return nil, fmt.Errorf("missing type argument for type parameter %d", t.TypeParameter)
+		}
+		typeTag := typeArguments[t.TypeParameter]
+		return &typeTag, nil
+	case types.SuiMoveNormalizedTypeReference:
+		return normalizedTypeToTypeTag(t.Reference.SuiMoveNormalizedType, typeArguments)
+	case types.SuiMoveNormalizedTypeMutableReference:
+		return normalizedTypeToTypeTag(t.MutableReference.SuiMoveNormalizedType, typeArguments)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", normalizedType)
+	}
+}
+
+// convertValue converts a decoded Move value into T, values of other types are converted through JSON so structs are
+// decoded by their JSON field names, which must match the Move field names.
+func convertValue[T any](value any) (T, error) {
+	if converted, ok := value.(T); ok {
+		return converted, nil
+	}
+
+	var converted T
+	bs, err := json.Marshal(value)
+	if err != nil {
+		return converted, err
+	}
+	if err := json.Unmarshal(bs, &converted); err != nil {
+		return converted, fmt.Errorf("can not convert %T into %T: %v", value, converted, err)
+	}
+	return converted, nil
+}
@@ -0,0 +1,107 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// viewCallSender defines the sender of view calls without a sender.
+const viewCallSender = "0x0"
+
+// ReturnValue defines a BCS-encoded value returned by a command in dev inspect results.
+type ReturnValue struct {
+	Bytes []byte
+	Type  string
+}
+
+// ParseReturnValues parses the return values of a command in dev inspect results.
+func ParseReturnValues(result types.SuiExecutionResult) ([]ReturnValue, error) {
+	values := make([]ReturnValue, len(result.ReturnValues))
+	for idx, returnValue := range result.ReturnValues {
+		bs, err := utils.ParseDevInspectReturnValue(returnValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytes of return value %d: %v", idx, err)
+		}
+		moveType, ok := returnValue[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid type of return value %d: %v", idx, returnValue[1])
+		}
+		values[idx] = ReturnValue{Bytes: bs, Type: moveType}
+	}
+	return values, nil
+}
+
+// DecodeReturnValue decodes a return value into T with the struct definitions of the registry, see TypeRegistry.Decode
+// for the decoded Go values. Other types are converted through JSON, so struct fields are matched by their JSON names.
+func DecodeReturnValue[T any](registry *TypeRegistry, value ReturnValue) (T, error) {
+	decoded, err := registry.Decode(value.Bytes, value.Type)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return convertValue[T](decoded)
+}
+
+// ViewCall calls a Move function with dev inspect and decodes its return values into T, the values of a function with
+// several return values are decoded into a slice or an array. Definitions of returned structs are fetched into the
+// DefaultTypeRegistry, and the zero address is the sender when the sender is not set. The call is added after the commands
+// of the transaction, and the transaction is restored when ViewCall returns.
+func ViewCall[T any](ctx context.Context, tx *Transaction, target string, arguments []Arg, typeArguments []string) (T, error) {
+	var zero T
+	if tx.client == nil {
+		return zero, fmt.Errorf("missing sui client to call [%s]", target)
+	}
+
+	restore, sender := tx.snapshotForExecution(), tx.Sender
+	defer func() {
+		restore()
+		tx.Sender = sender
+	}()
+
+	result, err := tx.AddMoveCall(target, arguments, typeArguments)
+	if err != nil {
+		return zero, err
+	}
+	tx.SetSenderIfNotSet(viewCallSender)
+
+	// coin intents are expanded into several commands, which shifts the index of the call
+	index := result.Index
+	mapping, err := tx.resolveIntentsExcluding(ctx, nil)
+	if err != nil {
+		return zero, fmt.Errorf("can not resolve coin intents: %w", err)
+	}
+	if mapping != nil {
+		index = mapping[index]
+	}
+
+	inspected, err := tx.DevInspectTransactionBlock(ctx)
+	if err != nil {
+		return zero, fmt.Errorf("failed to dev inspect [%s], err: %w", target, err)
+	}
+	if int(index) >= len(inspected.Results) {
+		return zero, fmt.Errorf("missing results of command %d", index)
+	}
+
+	values, err := ParseReturnValues(inspected.Results[index])
+	if err != nil {
+		return zero, err
+	}
+	registry := DefaultTypeRegistry()
+	decoded := make([]any, len(values))
+	for idx, value := range values {
+		if err := registry.Load(ctx, tx.client, value.Type); err != nil {
+			return zero, err
+		}
+		if decoded[idx], err = registry.Decode(value.Bytes, value.Type); err != nil {
+			return zero, fmt.Errorf("invalid return value %d: %v", idx, err)
+		}
+	}
+
+	if len(decoded) == 1 {
+		return convertValue[T](decoded[0])
+	}
+	return convertValue[T](decoded)
+}
@@ -0,0 +1,219 @@
+package transactions_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/W3Tools/gosui/transactions"
+	"github.com/W3Tools/gosui/types"
+	"github.com/W3Tools/gosui/utils"
+)
+
+// viewPool mirrors the Move struct `Pool<T> { id: UID, balance: Balance<T>, fee_bps: u16, owner: Option<address>,
+// name: String, history: vector<u64>, supply: u128 }`.
+type viewPool struct {
+	ID      string `json:"id"`
+	Balance struct {
+		Value uint64 `json:"value"`
+	} `json:"balance"`
+	FeeBps  uint16   `json:"fee_bps"`
+	Owner   *string  `json:"owner"`
+	Name    string   `json:"name"`
+	History []uint64 `json:"history"`
+	Supply  *big.Int `json:"supply"`
+}
+
+func viewStructs(pkg string) map[string]string {
+	return map[string]string{
+		"Pool": `{"abilities": {"abilities": ["Key"]}, "typeParameters": [{"constraints": {"abilities": []}, "isPhantom": true}], "fields": [
+			{"name": "id", "type": {"Struct": {"address": "0x2", "module": "object", "name": "UID", "typeArguments": []}}},
+			{"name": "balance", "type": {"Struct": {"address": "0x2", "module": "balance", "name": "Balance", "typeArguments": [{"TypeParameter": 0}]}}},
+			{"name": "fee_bps", "type": "U16"},
+			{"name": "owner", "type": {"Struct": {"address": "0x1", "module": "option", "name": "Option", "typeArguments": ["Address"]}}},
+			{"name": "name", "type": {"Struct": {"address": "0x1", "module": "string", "name": "String", "typeArguments": []}}},
+			{"name": "history", "type": {"Vector": "U64"}},
+			{"name": "supply", "type": "U128"}]}`,
+		"Balance": `{"abilities": {"abilities": ["Store"]}, "typeParameters": [{"constraints": {"abilities": []}, "isPhantom": true}], "fields": [{"name": "value", "type": "U64"}]}`,
+	}
+}
+
+// viewPoolBytes returns the BCS encoding of a pool with the ID, balance 500, fee 30, owner, name "sui" and history [1 2].
+func viewPoolBytes(t *testing.T, id, owner string) []byte {
+	bs := objectRef(t, id, 0).ObjectId[:]
+	bs = binary.LittleEndian.AppendUint64(bs, 500)
+	bs = binary.LittleEndian.AppendUint16(bs, 30)
+	bs = append(append(bs, 1), objectRef(t, owner, 0).ObjectId[:]...)
+	bs = append(bs, 3, 's', 'u', 'i', 2)
+	bs = binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(bs, 1), 2)
+	return append(bs, slices.Repeat([]byte{0xff}, 16)...)
+}
+
+func TestTypeRegistryDecode(t *testing.T) {
+	registry := transactions.NewTypeRegistry()
+	max128, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
+
+	tests := []struct {
+		name     string
+		moveType string
+		bytes    []byte
+		expected any
+	}{
+		{name: "bool", moveType: "bool", bytes: []byte{1}, expected: true},
+		{name: "u32", moveType: "u32", bytes: []byte{1, 2, 0, 0}, expected: uint32(513)},
+		{name: "u64", moveType: "u64", bytes: []byte{0xff, 0, 0, 0, 0, 0, 0, 0}, expected: uint64(255)},
+		{name: "u128", moveType: "u128", bytes: slices.Repeat([]byte{0xff}, 16), expected: max128},
+		{name: "address", moveType: "address", bytes: objectRef(t, recipient, 0).ObjectId[:], expected: recipient},
+		{name: "bytes", moveType: "vector<u8>", bytes: []byte{2, 7, 8}, expected: []byte{7, 8}},
+		{name: "vector", moveType: "vector<u16>", bytes: []byte{2, 7, 0, 8, 0}, expected: []any{uint16(7), uint16(8)}},
+		{name: "string", moveType: "0x1::string::String", bytes: []byte{2, 'o', 'k'}, expected: "ok"},
+		{name: "none", moveType: "0x1::option::Option<u8>", bytes: []byte{0}, expected: nil},
+		{name: "some", moveType: "0x1::option::Option<0x1::ascii::String>", bytes: []byte{1, 1, 'a'}, expected: "a"},
+		{name: "id", moveType: "0x2::object::ID", bytes: objectRef(t, objectID(0x40001), 0).ObjectId[:], expected: objectID(0x40001)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := registry.Decode(tt.bytes, tt.moveType)
+			if err != nil {
+				t.Fatalf("failed to decode value: %v", err)
+			}
+			if !reflect.DeepEqual(tt.expected, got) {
+				t.Errorf("expected %#v, but got %#v", tt.expected, got)
+			}
+		})
+	}
+
+	for _, tt := range []struct{ moveType, bytes string }{
+		{moveType: "bool", bytes: "\x02"},
+		{moveType: "u64", bytes: "\x01"},
+		{moveType: "u8", bytes: "\x01\x02"},
+		{moveType: "0x1::option::Option<u8>", bytes: "\x02\x01\x01"},
+		{moveType: "0x2::pool::Pool", bytes: ""},
+	} {
+		if _, err := registry.Decode([]byte(tt.bytes), tt.moveType); err == nil {
+			t.Errorf("expected an error for %v of type [%s], but got nil", []byte(tt.bytes), tt.moveType)
+		}
+	}
+}
+
+func TestViewCall(t *testing.T) {
+	pkg, pool, owner := objectID(0x40101), objectID(0x40102), objectID(0x40103)
+	poolType := pkg + "::pool::Pool<0x2::sui::SUI>"
+	structs := viewStructs(pkg)
+
+	var function types.SuiMoveNormalizedFunction
+	if err := json.Unmarshal([]byte(`{"visibility": "Public", "isEntry": false, "typeParameters": [{"abilities": []}],
+		"parameters": [{"Reference": {"Struct": {"address": "`+pkg+`", "module": "pool", "name": "Pool", "typeArguments": [{"TypeParameter": 0}]}}}],
+		"return": []}`), &function); err != nil {
+		t.Fatalf("failed to unmarshal normalized function: %v", err)
+	}
+
+	poolValue := []any{viewPoolBytes(t, pool, owner), poolType}
+	returnValues, inspectError := []any{poolValue}, ""
+	var results []any
+	var sender string
+	suiClient, node := newMockSuiClient(t, map[string]func(params []json.RawMessage) (any, error){
+		"sui_devInspectTransactionBlock": func(params []json.RawMessage) (any, error) {
+			if err := json.Unmarshal(params[0], &sender); err != nil {
+				return nil, err
+			}
+			if inspectError != "" {
+				return map[string]any{"effects": map[string]any{"status": map[string]any{"status": "failure", "error": inspectError}}, "error": inspectError}, nil
+			}
+			if results == nil {
+				results = []any{map[string]any{"returnValues": returnValues}}
+			}
+			return map[string]any{"effects": map[string]any{"status": map[string]any{"status": "success"}}, "results": results}, nil
+		},
+		"suix_getCoins": getCoinsByType(map[string][]types.CoinStruct{usdc: newTypedCoins(usdc, 100, 200)}),
+		"sui_getNormalizedMoveStruct": func(params []json.RawMessage) (any, error) {
+			var name string
+			if err := json.Unmarshal(params[2], &name); err != nil {
+				return nil, err
+			}
+			return json.RawMessage(structs[name]), nil
+		},
+	})
+
+	newTransaction := func() *transactions.Transaction {
+		tx := transactions.NewTransaction(suiClient)
+		tx.SetCache(transactions.NewLRUCache(0))
+		if err := tx.SupplyMoveFunction(pkg+"::pool::info", &function); err != nil {
+			t.Fatalf("failed to supply move function: %v", err)
+		}
+		return tx
+	}
+
+	tx := newTransaction()
+	got, err := transactions.ViewCall[viewPool](context.Background(), tx, pkg+"::pool::info", []transactions.Arg{tx.SharedObjectRef(pool, 1, false)}, []string{"0x2::sui::SUI"})
+	if err != nil {
+		t.Fatalf("failed to call view function: %v", err)
+	}
+	expected := viewPool{ID: pool, FeeBps: 30, Owner: &owner, Name: "sui", History: []uint64{1, 2}, Supply: new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))}
+	expected.Balance.Value = 500
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected pool %+v, but got %+v", expected, got)
+	}
+	if sender != utils.NormalizeSuiAddress("0x0") {
+		t.Errorf("expected the zero address to be the sender, but got %s", sender)
+	}
+	if tx.Sender != nil || len(tx.TransactionBuilder().Commands) != 0 {
+		t.Errorf("expected the transaction to be restored, but got sender %v and %d commands", tx.Sender, len(tx.TransactionBuilder().Commands))
+	}
+
+	// the merge coins command of a coin intent shifts the index of the call
+	results = []any{map[string]any{}, map[string]any{}, map[string]any{"returnValues": returnValues}}
+	tx = newTransaction()
+	if _, err := tx.CoinWithBalance(usdc, 250); err != nil {
+		t.Fatalf("failed to add coin with balance: %v", err)
+	}
+	if got, err = transactions.ViewCall[viewPool](context.Background(), tx, pkg+"::pool::info", []transactions.Arg{tx.SharedObjectRef(pool, 1, false)}, []string{"0x2::sui::SUI"}); err != nil {
+		t.Fatalf("failed to call view function after a coin intent: %v", err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected pool %+v after a coin intent, but got %+v", expected, got)
+	}
+	if len(tx.TransactionBuilder().Commands) != 1 {
+		t.Errorf("expected the coin intent to be kept, but got %d commands", len(tx.TransactionBuilder().Commands))
+	}
+
+	results, returnValues = nil, []any{poolValue, []any{[]int{1, 0}, "u16"}}
+	tx = newTransaction()
+	values, err := transactions.ViewCall[[]any](context.Background(), tx, pkg+"::pool::info", []transactions.Arg{tx.SharedObjectRef(pool, 1, false)}, []string{"0x2::sui::SUI"})
+	if err != nil {
+		t.Fatalf("failed to call view function with several return values: %v", err)
+	}
+	if len(values) != 2 || values[1] != uint16(1) {
+		t.Errorf("expected two return values ending with 1, but got %#v", values)
+	}
+	if got := node.count("sui_getNormalizedMoveStruct"); got != 2 {
+		t.Errorf("expected the definitions of the pool and balance to be fetched once, but got %d requests", got)
+	}
+
+	typed, err := transactions.DecodeReturnValue[viewPool](transactions.DefaultTypeRegistry(), transactions.ReturnValue{Bytes: viewPoolBytes(t, pool, owner), Type: poolType})
+	if err != nil {
+		t.Fatalf("failed to decode pool: %v", err)
+	}
+	if !reflect.DeepEqual(expected, typed) {
+		t.Errorf("expected decoded pool %+v, but got %+v", expected, typed)
+	}
+
+	inspectError = "MoveAbort in command 0"
+	tx = newTransaction()
+	if _, err := transactions.ViewCall[viewPool](context.Background(), tx, pkg+"::pool::info", []transactions.Arg{tx.SharedObjectRef(pool, 1, false)}, []string{"0x2::sui::SUI"}); err == nil || !strings.Contains(err.Error(), inspectError) {
+		t.Errorf("expected the dev inspect error, but got %v", err)
+	}
+
+	tx = transactions.NewTransaction(nil)
+	if _, err := transactions.ViewCall[uint64](context.Background(), tx, pkg+"::pool::info", nil, nil); err == nil {
+		t.Errorf("expected an error without a sui client, but got nil")
+	}
+	if len(tx.TransactionBuilder().Commands) != 0 {
+		t.Errorf("expected no command to be added without a sui client")
+	}
+}
@@ -222,6 +222,7 @@ type ExecuteTransactionBlockParams struct {
 type SignAndExecuteTransactionBlockParams struct {
 	TransactionBlock []byte                              `json:"transactionBlock"`
 	Signer           cryptography.Signer                 `json:"signer"`
+	Signatures       []string                            `json:"signatures,omitempty"` // signatures of other signers, such as the gas sponsor
 	Options          *SuiTransactionBlockResponseOptions `json:"options,omitempty"`
 	RequestType      *ExecuteTransactionRequestType      `json:"requestType,omitempty"`
 }
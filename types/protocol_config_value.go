@@ -1,10 +1,19 @@
 package types
 
+import (
+	"encoding/json"
+)
+
 // ProtocolConfigValue is an interface that defines a protocol config value type.
 type ProtocolConfigValue interface {
 	isProtocolConfigValue()
 }
 
+// ProtocolConfigValueU16 defines a protocol config value of type U16.
+type ProtocolConfigValueU16 struct {
+	U16 string `json:"u16"`
+}
+
 // ProtocolConfigValueU32 defines a protocol config value of type U32.
 type ProtocolConfigValueU32 struct {
 	U32 string `json:"u32"`
@@ -20,6 +29,19 @@ type ProtocolConfigValueF64 struct {
 	F64 string `json:"f64"`
 }
 
+// ProtocolConfigValueBool defines a protocol config value of type Bool.
+type ProtocolConfigValueBool struct {
+	Bool string `json:"bool"`
+}
+
+// ProtocolConfigValueUnknown defines a protocol config value of a type that is not known, the value is kept as it was received.
+type ProtocolConfigValueUnknown struct {
+	Raw json.RawMessage
+}
+
+// isProtocolConfigValue implements the ProtocolConfigValue interface for ProtocolConfigValueU16.
+func (ProtocolConfigValueU16) isProtocolConfigValue() {}
+
 // isProtocolConfigValue implements the ProtocolConfigValue interface for ProtocolConfigValueU32.
 func (ProtocolConfigValueU32) isProtocolConfigValue() {}
 
@@ -28,3 +50,74 @@ func (ProtocolConfigValueU64) isProtocolConfigValue() {}
 
 // isProtocolConfigValue implements the ProtocolConfigValue interface for ProtocolConfigValueF64.
 func (ProtocolConfigValueF64) isProtocolConfigValue() {}
+
+// isProtocolConfigValue implements the ProtocolConfigValue interface for ProtocolConfigValueBool.
+func (ProtocolConfigValueBool) isProtocolConfigValue() {}
+
+// isProtocolConfigValue implements the ProtocolConfigValue interface for ProtocolConfigValueUnknown.
+func (ProtocolConfigValueUnknown) isProtocolConfigValue() {}
+
+// MarshalJSON implements the json.Marshaler interface for ProtocolConfigValueUnknown.
+func (v ProtocolConfigValueUnknown) MarshalJSON() ([]byte, error) {
+	if v.Raw == nil {
+		return []byte("null"), nil
+	}
+	return v.Raw, nil
+}
+
+// ProtocolConfigValueWrapper is a wrapper for ProtocolConfigValue that allows unmarshalling from JSON.
+type ProtocolConfigValueWrapper struct {
+	ProtocolConfigValue
+}
+
+// UnmarshalJSON custom unmarshaller for ProtocolConfigValueWrapper, values of unknown types are unmarshalled as ProtocolConfigValueUnknown.
+func (w *ProtocolConfigValueWrapper) UnmarshalJSON(data []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		w.ProtocolConfigValue = ProtocolConfigValueUnknown{Raw: append(json.RawMessage{}, data...)}
+		return nil
+	}
+
+	switch {
+	case obj["u16"] != nil:
+		var v ProtocolConfigValueU16
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		w.ProtocolConfigValue = v
+	case obj["u32"] != nil:
+		var v ProtocolConfigValueU32
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		w.ProtocolConfigValue = v
+	case obj["u64"] != nil:
+		var v ProtocolConfigValueU64
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		w.ProtocolConfigValue = v
+	case obj["f64"] != nil:
+		var v ProtocolConfigValueF64
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		w.ProtocolConfigValue = v
+	case obj["bool"] != nil:
+		var v ProtocolConfigValueBool
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		w.ProtocolConfigValue = v
+	default:
+		// values of types added by later protocol versions are kept, so that the other attributes can be read
+		w.ProtocolConfigValue = ProtocolConfigValueUnknown{Raw: append(json.RawMessage{}, data...)}
+	}
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for ProtocolConfigValueWrapper.
+func (w ProtocolConfigValueWrapper) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.ProtocolConfigValue)
+}
@@ -339,11 +339,11 @@ type SuiTransactionBlock struct {
 
 // ProtocolConfig defines the protocol configuration in SUI.
 type ProtocolConfig struct {
-	MinSupportedProtocolVersion string                         `json:"minSupportedProtocolVersion"`
-	MaxSupportedProtocolVersion string                         `json:"maxSupportedProtocolVersion"`
-	ProtocolVersion             string                         `json:"protocolVersion"`
-	FeatureFlags                map[string]bool                `json:"featureFlags"`
-	Attributes                  map[string]ProtocolConfigValue `json:"attributes"`
+	MinSupportedProtocolVersion string                                 `json:"minSupportedProtocolVersion"`
+	MaxSupportedProtocolVersion string                                 `json:"maxSupportedProtocolVersion"`
+	ProtocolVersion             string                                 `json:"protocolVersion"`
+	FeatureFlags                map[string]bool                        `json:"featureFlags"`
+	Attributes                  map[string]*ProtocolConfigValueWrapper `json:"attributes"` // nil for attributes not set in the protocol version
 }
 
 // SuiActiveJwk defines an active JWK in SUI.
@@ -11,6 +11,14 @@ var (
 	GasSafeOverhead uint64 = 1000
 	// MaxGas is the maximum gas limit for SUI transactions
 	MaxGas uint64 = 50000000000
+	// MaxGasObjects is the maximum number of coins used as gas payment in SUI transactions
+	MaxGasObjects = 256
+	// MaxArguments is the maximum number of arguments of a command in SUI programmable transactions
+	MaxArguments = 512
+	// MaxProgrammableTxCommands is the maximum number of commands in SUI programmable transactions
+	MaxProgrammableTxCommands = 1024
+	// MinStakeAmount is the minimum amount of MIST that can be staked with a validator
+	MinStakeAmount uint64 = 1000000000
 	// MoveStdlibAddress is the address of the Move standard library in SUI, 0x0000000000000000000000000000000000000000000000000000000000000001
 	MoveStdlibAddress = NormalizeSuiObjectID("0x1")
 	// SuiFrameworkAddress is the address of the SUI framework, 0x0000000000000000000000000000000000000000000000000000000000000002
@@ -25,6 +33,10 @@ var (
 	SuiTypeArg = fmt.Sprintf("%s::sui::SUI", SuiFrameworkAddress)
 	// SuiSystemStateObjectID is the object ID for the SUI system state, 0x0000000000000000000000000000000000000000000000000000000000000005
 	SuiSystemStateObjectID = NormalizeSuiObjectID("0x5")
+	// SuiRandomObjectID is the object ID for SUI on-chain randomness, 0x0000000000000000000000000000000000000000000000000000000000000008
+	SuiRandomObjectID = NormalizeSuiObjectID("0x8")
+	// SuiDenyListObjectID is the object ID for the SUI coin deny list, 0x0000000000000000000000000000000000000000000000000000000000000403
+	SuiDenyListObjectID = NormalizeSuiObjectID("0x403")
 )
 
 // Endpoint represents a SUI network endpoint